@@ -3,17 +3,34 @@ package main
 import (
 	"app/internal/application"
 	"fmt"
+	"os"
 )
 
 func main() {
 	// env
-	// ...
+	// - VEHICLES_DEV_KEYS=1 adds the API key of local development: dev-fleet-manager-key, role fleet_manager
+	var devKeysFilePath string
+	if os.Getenv("VEHICLES_DEV_KEYS") == "1" {
+		devKeysFilePath = "docs/auth/dev_keys.json"
+	}
 
 	// app
 	// - config
 	cfg := &application.ConfigServerChi{
 		ServerAddress: ":8080",
 		LoaderFilePath: "docs/db/vehicles_100.json",
+		TenantsFilePath: "docs/tenants/tenants.json",
+		RegistrationRulesFilePath: "docs/registration/formats.json",
+		NormalizationFilePath: "docs/normalization/synonyms.json",
+		EmissionRulesFilePath: "docs/emissions/factors.json",
+		PricingRulesFilePath: "docs/pricing/rules.json",
+		MetricsRulesFilePath: "docs/metrics/size_classes.json",
+		SequenceFilePath: "docs/db/vehicles_sequence.json",
+		MaintenanceRulesFilePath: "docs/maintenance/schedule.json",
+		AuthFilePath: "docs/auth/auth.json",
+		DevKeysFilePath: devKeysFilePath,
+		PolicyFilePath: "docs/auth/policy.json",
+		RateLimitsFilePath: "docs/ratelimit/limits.json",
 	}
 	app := application.NewServerChi(cfg)
 	// - run
@@ -1,11 +1,15 @@
 package application
 
 import (
+	"app/internal"
 	"app/internal/handler"
+	"app/internal/job"
 	"app/internal/loader"
 	"app/internal/repository"
 	"app/internal/service"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,6 +21,38 @@ type ConfigServerChi struct {
 	ServerAddress string
 	// LoaderFilePath is the path to the file that contains the vehicles
 	LoaderFilePath string
+	// TenantsFilePath is the path to the file that contains the tenants and their vehicles files, empty for a single default tenant of LoaderFilePath
+	TenantsFilePath string
+	// RegistrationRulesFilePath is the path to the file that contains the registration formats by country
+	RegistrationRulesFilePath string
+	// NormalizationFilePath is the path to the file that contains the synonym tables of the categorical values
+	NormalizationFilePath string
+	// EmissionRulesFilePath is the path to the file that contains the emission factors and fuel prices
+	EmissionRulesFilePath string
+	// PricingRulesFilePath is the path to the file that contains the rates and discounts of the rentals, empty for no quotes
+	PricingRulesFilePath string
+	// MetricsRulesFilePath is the path to the file that contains the size classes of the derived attributes
+	MetricsRulesFilePath string
+	// SequenceFilePath is the path to the file that persists the last vehicle id
+	SequenceFilePath string
+	// MaintenanceRulesFilePath is the path to the file that contains the service intervals, empty for no due services
+	MaintenanceRulesFilePath string
+	// AuthFilePath is the path to the file that contains the API keys and the keys of the tokens
+	AuthFilePath string
+	// DevKeysFilePath is the path to the file that contains the API keys of local development, added to the ones of AuthFilePath, empty for none
+	DevKeysFilePath string
+	// PolicyFilePath is the path to the file that contains the roles and their permissions
+	PolicyFilePath string
+	// RateLimitsFilePath is the path to the file that contains the budgets of the clients, empty for no limits
+	RateLimitsFilePath string
+	// AuthProtectReads is a flag that also requires credentials on the read routes, the others always need them
+	AuthProtectReads bool
+	// IdempotencyTTL is the time the responses of the requests with an Idempotency-Key are kept
+	IdempotencyTTL time.Duration
+	// PurgeRetention is the time a soft deleted vehicle is kept before being purged
+	PurgeRetention time.Duration
+	// PurgeInterval is the time between two purges of soft deleted vehicles
+	PurgeInterval time.Duration
 }
 
 // NewServerChi is a function that returns a new instance of ServerChi
@@ -24,6 +60,9 @@ func NewServerChi(cfg *ConfigServerChi) *ServerChi {
 	// default values
 	defaultConfig := &ConfigServerChi{
 		ServerAddress: ":8080",
+		PurgeRetention: 30 * 24 * time.Hour,
+		PurgeInterval: time.Hour,
+		IdempotencyTTL: 24 * time.Hour,
 	}
 	if cfg != nil {
 		if cfg.ServerAddress != "" {
@@ -32,11 +71,49 @@ func NewServerChi(cfg *ConfigServerChi) *ServerChi {
 		if cfg.LoaderFilePath != "" {
 			defaultConfig.LoaderFilePath = cfg.LoaderFilePath
 		}
+		defaultConfig.TenantsFilePath = cfg.TenantsFilePath
+		defaultConfig.RegistrationRulesFilePath = cfg.RegistrationRulesFilePath
+		defaultConfig.NormalizationFilePath = cfg.NormalizationFilePath
+		defaultConfig.MetricsRulesFilePath = cfg.MetricsRulesFilePath
+		defaultConfig.EmissionRulesFilePath = cfg.EmissionRulesFilePath
+		defaultConfig.PricingRulesFilePath = cfg.PricingRulesFilePath
+		defaultConfig.SequenceFilePath = cfg.SequenceFilePath
+		defaultConfig.MaintenanceRulesFilePath = cfg.MaintenanceRulesFilePath
+		defaultConfig.AuthFilePath = cfg.AuthFilePath
+		defaultConfig.DevKeysFilePath = cfg.DevKeysFilePath
+		defaultConfig.AuthProtectReads = cfg.AuthProtectReads
+		defaultConfig.PolicyFilePath = cfg.PolicyFilePath
+		defaultConfig.RateLimitsFilePath = cfg.RateLimitsFilePath
+		if cfg.IdempotencyTTL != 0 {
+			defaultConfig.IdempotencyTTL = cfg.IdempotencyTTL
+		}
+		if cfg.PurgeRetention != 0 {
+			defaultConfig.PurgeRetention = cfg.PurgeRetention
+		}
+		if cfg.PurgeInterval != 0 {
+			defaultConfig.PurgeInterval = cfg.PurgeInterval
+		}
 	}
 
 	return &ServerChi{
 		serverAddress: defaultConfig.ServerAddress,
 		loaderFilePath: defaultConfig.LoaderFilePath,
+		tenantsFilePath: defaultConfig.TenantsFilePath,
+		registrationRulesFilePath: defaultConfig.RegistrationRulesFilePath,
+		normalizationFilePath: defaultConfig.NormalizationFilePath,
+		metricsRulesFilePath: defaultConfig.MetricsRulesFilePath,
+		emissionRulesFilePath: defaultConfig.EmissionRulesFilePath,
+		pricingRulesFilePath: defaultConfig.PricingRulesFilePath,
+		sequenceFilePath: defaultConfig.SequenceFilePath,
+		maintenanceRulesFilePath: defaultConfig.MaintenanceRulesFilePath,
+		authFilePath: defaultConfig.AuthFilePath,
+		devKeysFilePath: defaultConfig.DevKeysFilePath,
+		authProtectReads: defaultConfig.AuthProtectReads,
+		policyFilePath: defaultConfig.PolicyFilePath,
+		rateLimitsFilePath: defaultConfig.RateLimitsFilePath,
+		idempotencyTTL: defaultConfig.IdempotencyTTL,
+		purgeRetention: defaultConfig.PurgeRetention,
+		purgeInterval: defaultConfig.PurgeInterval,
 	}
 }
 
@@ -46,50 +123,419 @@ type ServerChi struct {
 	serverAddress string
 	// loaderFilePath is the path to the file that contains the vehicles
 	loaderFilePath string
+	// tenantsFilePath is the path to the file that contains the tenants and their vehicles files
+	tenantsFilePath string
+	// registrationRulesFilePath is the path to the file that contains the registration formats by country
+	registrationRulesFilePath string
+	// normalizationFilePath is the path to the file that contains the synonym tables of the categorical values
+	normalizationFilePath string
+	// emissionRulesFilePath is the path to the file that contains the emission factors and fuel prices
+	emissionRulesFilePath string
+	// pricingRulesFilePath is the path to the file that contains the rates and discounts of the rentals
+	pricingRulesFilePath string
+	// metricsRulesFilePath is the path to the file that contains the size classes of the derived attributes
+	metricsRulesFilePath string
+	// sequenceFilePath is the path to the file that persists the last vehicle id
+	sequenceFilePath string
+	// maintenanceRulesFilePath is the path to the file that contains the service intervals
+	maintenanceRulesFilePath string
+	// authFilePath is the path to the file that contains the API keys and the keys of the tokens
+	authFilePath string
+	// devKeysFilePath is the path to the file that contains the API keys of local development
+	devKeysFilePath string
+	// policyFilePath is the path to the file that contains the roles and their permissions
+	policyFilePath string
+	// rateLimitsFilePath is the path to the file that contains the budgets of the clients
+	rateLimitsFilePath string
+	// authProtectReads is a flag that also requires credentials on the read routes
+	authProtectReads bool
+	// idempotencyTTL is the time the responses of the requests with an Idempotency-Key are kept
+	idempotencyTTL time.Duration
+	// purgeRetention is the time a soft deleted vehicle is kept before being purged
+	purgeRetention time.Duration
+	// purgeInterval is the time between two purges of soft deleted vehicles
+	purgeInterval time.Duration
 }
 
 // Run is a method that runs the application
 func (a *ServerChi) Run() (err error) {
 	// dependencies
-	// - loader
-	ld := loader.NewVehicleJSONFile(a.loaderFilePath)
-	db, err := ld.Load()
+	// - tenants: each one with its own vehicles file and id space, a single default one without tenants file
+	tenants := []internal.Tenant{{Id: internal.DefaultTenant, LoaderFilePath: a.loaderFilePath, SequenceFilePath: a.sequenceFilePath}}
+	if a.tenantsFilePath != "" {
+		if tenants, err = loader.NewTenantJSONFile(a.tenantsFilePath).Load(); err != nil {
+			return
+		}
+	}
+	// - derived attributes (size classes optional)
+	var rules internal.MetricsRules
+	if a.metricsRulesFilePath != "" {
+		if rules, err = loader.NewMetricsRulesJSONFile(a.metricsRulesFilePath).Load(); err != nil {
+			return
+		}
+	}
+	dv, err := service.NewMetricsDefault(rules)
 	if err != nil {
 		return
 	}
-	// - repository
-	rp := repository.NewVehicleMap(db)
-	// - service
-	sv := service.NewVehicleDefault(rp)
-	// - handler
-	hd := handler.NewVehicleDefault(sv)
+	// - registration formats (optional)
+	var rg internal.RegistrationValidator
+	if a.registrationRulesFilePath != "" {
+		rules, err := loader.NewRegistrationRuleJSONFile(a.registrationRulesFilePath).Load()
+		if err != nil {
+			return err
+		}
+		if rg, err = service.NewRegistrationDefault(rules); err != nil {
+			return err
+		}
+	}
+	// - normalization of the categorical values (optional)
+	var nz internal.VehicleNormalizer
+	if a.normalizationFilePath != "" {
+		rules, err := loader.NewNormalizationRuleJSONFile(a.normalizationFilePath).Load()
+		if err != nil {
+			return err
+		}
+		if nz, err = service.NewNormalizerDefault(rules); err != nil {
+			return err
+		}
+	}
+	// - emission factors (optional)
+	var em internal.VehicleEmissionsEstimator
+	if a.emissionRulesFilePath != "" {
+		rules, err := loader.NewEmissionRulesJSONFile(a.emissionRulesFilePath).Load()
+		if err != nil {
+			return err
+		}
+		if em, err = service.NewEmissionsDefault(rules); err != nil {
+			return err
+		}
+	}
+	// - pricing rules (optional)
+	var pr internal.VehiclePricer
+	if a.pricingRulesFilePath != "" {
+		rules, err := loader.NewPricingRulesJSONFile(a.pricingRulesFilePath).Load()
+		if err != nil {
+			return err
+		}
+		if pr, err = service.NewPricingDefault(rules); err != nil {
+			return err
+		}
+	}
+	// - vin decoder
+	vn, err := service.NewVINDefault()
+	if err != nil {
+		return
+	}
+	// - service intervals (optional)
+	var schedule []internal.MaintenanceRule
+	if a.maintenanceRulesFilePath != "" {
+		if schedule, err = loader.NewMaintenanceRulesJSONFile(a.maintenanceRulesFilePath).Load(); err != nil {
+			return
+		}
+	}
+	// - services of each tenant
+	services := make(map[string]internal.VehicleService, len(tenants))
+	depots := make(map[string]internal.DepotService, len(tenants))
+	drivers := make(map[string]internal.DriverService, len(tenants))
+	maintenance := make(map[string]internal.MaintenanceService, len(tenants))
+	fuel := make(map[string]internal.FuelService, len(tenants))
+	reservations := make(map[string]internal.ReservationService, len(tenants))
+	for _, t := range tenants {
+		if t.Id == "" || services[t.Id] != nil {
+			return fmt.Errorf("tenant %q: ids must be unique and not empty", t.Id)
+		}
+		cfg := tenantConfig{
+			vehicles: service.ConfigVehicleDefault{
+				Registrations: rg,
+				VINs:          vn,
+				Normalizer:    nz,
+				Deriver:       dv,
+				Emissions:     em,
+				Pricing:       pr,
+			},
+			maintenanceRules: schedule,
+		}
+		ts, err := newTenantServices(t, cfg)
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", t.Id, err)
+		}
+		services[t.Id], depots[t.Id], drivers[t.Id] = ts.vehicles, ts.depots, ts.drivers
+		maintenance[t.Id], fuel[t.Id], reservations[t.Id] = ts.maintenance, ts.fuel, ts.reservations
+	}
+	tp := service.NewTenantProvider(services, service.NewVehicleAuthorized)
+	// - authentication
+	cfgAuth, err := loader.NewAuthConfigJSONFile(a.authFilePath).Load()
+	if err != nil {
+		return
+	}
+	if a.devKeysFilePath != "" {
+		cfgDev, err := loader.NewAuthConfigJSONFile(a.devKeysFilePath).Load()
+		if err != nil {
+			return err
+		}
+		cfgAuth.APIKeys = append(cfgAuth.APIKeys, cfgDev.APIKeys...)
+	}
+	au, err := service.NewAuthDefault(cfgAuth)
+	if err != nil {
+		return
+	}
+	// - authorization: every request gets the service of its tenant bound to its principal
+	policy, err := loader.NewPolicyJSONFile(a.policyFilePath).Load()
+	if err != nil {
+		return
+	}
+	az, err := service.NewPolicyDefault(policy)
+	if err != nil {
+		return
+	}
+	// - handlers
+	hd := handlers{
+		vehicles:     handler.NewVehicleDefault(service.NewAuthorizedProvider(tp, az, service.NewVehicleAuthorized)),
+		depots:       handler.NewDepotDefault(service.NewAuthorizedProvider(service.NewTenantProvider(depots, service.NewDepotAuthorized), az, service.NewDepotAuthorized)),
+		drivers:      handler.NewDriverDefault(service.NewAuthorizedProvider(service.NewTenantProvider(drivers, service.NewDriverAuthorized), az, service.NewDriverAuthorized)),
+		maintenance:  handler.NewMaintenanceDefault(service.NewAuthorizedProvider(service.NewTenantProvider(maintenance, service.NewMaintenanceAuthorized), az, service.NewMaintenanceAuthorized)),
+		fuel:         handler.NewFuelDefault(service.NewAuthorizedProvider(service.NewTenantProvider(fuel, service.NewFuelAuthorized), az, service.NewFuelAuthorized)),
+		reservations: handler.NewReservationDefault(service.NewAuthorizedProvider(service.NewTenantProvider(reservations, service.NewReservationAuthorized), az, service.NewReservationAuthorized)),
+	}
+	md := middlewares{
+		auth:   handler.NewAuthMiddleware(au, &handler.ConfigAuthMiddleware{ProtectReads: a.authProtectReads}),
+		tenant: handler.NewTenantMiddleware(tp, az),
+	}
+	// - rate limits (optional)
+	var rl internal.RateLimiter
+	if a.rateLimitsFilePath != "" {
+		limits, err := loader.NewRateLimitsJSONFile(a.rateLimitsFilePath).Load()
+		if err != nil {
+			return err
+		}
+		if rl, err = repository.NewRateLimiterMap(limits); err != nil {
+			return err
+		}
+	}
+	md.rate = handler.NewRateLimitMiddleware(rl)
+	md.idempotency = handler.NewIdempotencyMiddleware(repository.NewIdempotencyMap(a.idempotencyTTL))
+	// - jobs
+	stop := make(chan struct{})
+	defer close(stop)
+	for _, sv := range services {
+		go job.NewVehiclePurge(sv, a.purgeRetention, a.purgeInterval).Run(stop)
+	}
 	// router
 	rt := chi.NewRouter()
 	// - middlewares
 	rt.Use(middleware.Logger)
 	rt.Use(middleware.Recoverer)
 	// - endpoints
-	rt.Route("/vehicles", func(rt chi.Router) {
-		// - GET /vehicles
-		rt.Get("/", hd.GetAll())
-		rt.Post("/", hd.Add())
-		rt.Get("/color/{color}/year/{year}", hd.SearchByColorAndYear())
-		rt.Get("/brand/{brand}/between/{start_year}/{end_year}", hd.SearchByBrand())
-		rt.Get("/average_speed/brand/{brand}", hd.GetAverageSpeedByBrand())
-		rt.Post("/batch", hd.AddMultiple())
-		rt.Put("/{id}/update_speed", hd.UpdateMaxSpeedById())
-		rt.Get("/fuel_type/{fuel_type}", hd.GetVehiclesByFuelType())
-		rt.Delete("/{id}", hd.DeleteById())
-		rt.Get("/transmission/{type}", hd.GetVehiclesByTransmission())
-		rt.Put("/{id}/update_fuel", hd.UpdateFuelTypeById())
-		rt.Get("/average_capacity/brand/{brand}", hd.GetAverageCapacityByBrand())
-		//
-		rt.Get("/dimensions", hd.GetVehiclesByDimensions())
-		rt.Get("/weight", hd.GetVehiclesByWeight())
-
-	})
+	rt.Route("/vehicles", vehicleRoutes(hd, md))
+	rt.Route("/depots", depotRoutes(hd, md))
+	rt.Route("/drivers", driverRoutes(hd, md))
 
 	// run server
 	err = http.ListenAndServe(a.serverAddress, rt)
 	return
+}
+
+// tenantServices is a struct that represents the services of a tenant
+type tenantServices struct {
+	// vehicles is the service of the vehicles
+	vehicles *service.VehicleDefault
+	// depots is the service of the depots that own the vehicles
+	depots *service.DepotDefault
+	// drivers is the service of the drivers assigned to the vehicles
+	drivers *service.DriverDefault
+	// maintenance is the service of the maintenance of the vehicles
+	maintenance *service.MaintenanceDefault
+	// fuel is the service of the fuel logs and the consumption of the vehicles
+	fuel *service.FuelDefault
+	// reservations is the service of the reservations of the vehicles
+	reservations *service.ReservationDefault
+}
+
+// tenantConfig is a struct that represents the configuration shared by the services of every tenant
+type tenantConfig struct {
+	// vehicles is the configuration of the service of the vehicles, the tenant, sequence and depots are set per tenant
+	vehicles service.ConfigVehicleDefault
+	// maintenanceRules are the service intervals
+	maintenanceRules []internal.MaintenanceRule
+}
+
+// newTenantServices is a function that returns the services of a tenant with its own vehicles, depots, drivers, repositories and sequence
+// - the depots, drivers, maintenance records, fuel logs and reservations are kept in memory, each tenant starts without any
+func newTenantServices(t internal.Tenant, cfg tenantConfig) (ts tenantServices, err error) {
+	// - loader, with the derived attributes computed for the loaded vehicles
+	db, err := loader.NewVehicleJSONFile(t.LoaderFilePath).Load()
+	if err != nil {
+		return
+	}
+	for id, v := range db {
+		v.Tenant = t.Id
+		v.Metrics = cfg.vehicles.Deriver.Derive(v)
+		db[id] = v
+	}
+	// - repository
+	rp := repository.NewVehicleMap(db)
+	// - sequence: never behind the loaded ids
+	sq, err := repository.NewVehicleSequenceFile(t.SequenceFilePath)
+	if err != nil {
+		return
+	}
+	var lastId int
+	for id := range db {
+		if id > lastId {
+			lastId = id
+		}
+	}
+	if err = sq.Advance(lastId); err != nil {
+		return
+	}
+	// - depots, drivers, maintenance records, fuel logs and reservations
+	dp := repository.NewDepotMap(nil)
+	dr := repository.NewDriverMap(nil)
+	mt := repository.NewMaintenanceMap()
+	fl := repository.NewFuelLogMap()
+	rs := repository.NewReservationMap()
+	// - services
+	cfg.vehicles.Tenant = t.Id
+	cfg.vehicles.Sequence = sq
+	cfg.vehicles.Depots = dp
+	ts.vehicles = service.NewVehicleDefault(rp, &cfg.vehicles)
+	ts.depots = service.NewDepotDefault(dp, t.Id)
+	ts.drivers = service.NewDriverDefault(dr, rp, t.Id)
+	ts.fuel = service.NewFuelDefault(fl, rp)
+	ts.reservations = service.NewReservationDefault(rs, rp)
+	if ts.maintenance, err = service.NewMaintenanceDefault(mt, rp, cfg.maintenanceRules); err != nil {
+		return
+	}
+	return
+}
+
+// handlers is a struct that represents the handlers of the endpoints
+type handlers struct {
+	// vehicles are the handlers of the vehicles
+	vehicles *handler.VehicleDefault
+	// depots are the handlers of the depots
+	depots *handler.DepotDefault
+	// drivers are the handlers of the drivers and their assignments
+	drivers *handler.DriverDefault
+	// maintenance are the handlers of the maintenance of the vehicles
+	maintenance *handler.MaintenanceDefault
+	// fuel are the handlers of the fuel logs of the vehicles
+	fuel *handler.FuelDefault
+	// reservations are the handlers of the reservations of the vehicles
+	reservations *handler.ReservationDefault
+}
+
+// middlewares is a struct that represents the middlewares of the endpoints
+type middlewares struct {
+	// auth authenticates the requests, POST, PUT and DELETE always need credentials
+	auth *handler.AuthMiddleware
+	// tenant resolves the tenant of the requests once authenticated
+	tenant *handler.TenantMiddleware
+	// rate limits the requests of each client by class, and the failed authentications of each IP address
+	rate *handler.RateLimitMiddleware
+	// idempotency replays the responses of the creation requests retried with the same key
+	idempotency *handler.IdempotencyMiddleware
+}
+
+// vehicleRoutes is a function that returns the registration of the endpoints of the vehicles
+// - the endpoints are grouped by the budget of their class of requests
+func vehicleRoutes(hd handlers, md middlewares) func(rt chi.Router) {
+	return func(rt chi.Router) {
+		rt.Use(md.rate.AuthFailures)
+		rt.Use(md.auth.Handler)
+		rt.Use(md.tenant.Handler)
+
+		// - reads
+		rt.Group(func(rt chi.Router) {
+			rt.Use(md.rate.Handler(internal.RateClassRead))
+			rt.Get("/", hd.vehicles.GetAll())
+			rt.Get("/{id}", hd.vehicles.GetById())
+			rt.Get("/color/{color}/year/{year}", hd.vehicles.SearchByColorAndYear())
+			rt.Get("/brand/{brand}/between/{start_year}/{end_year}", hd.vehicles.SearchByBrand())
+			rt.Get("/average_speed/brand/{brand}", hd.vehicles.GetAverageSpeedByBrand())
+			rt.Get("/fuel_type/{fuel_type}", hd.vehicles.GetVehiclesByFuelType())
+			rt.Get("/{id}/similar", hd.vehicles.GetSimilar())
+			rt.Get("/{id}/emissions", hd.vehicles.GetEmissions())
+			rt.Get("/{id}/quote", hd.vehicles.GetQuote())
+			rt.Get("/transmission/{type}", hd.vehicles.GetVehiclesByTransmission())
+			rt.Get("/registration/{registration}", hd.vehicles.GetByRegistration())
+			rt.Post("/registration/validate", hd.vehicles.CheckRegistration())
+			rt.Get("/vin/{vin}", hd.vehicles.GetByVIN())
+			rt.Get("/average_capacity/brand/{brand}", hd.vehicles.GetAverageCapacityByBrand())
+			rt.Get("/dimensions", hd.vehicles.GetVehiclesByDimensions())
+			rt.Get("/weight", hd.vehicles.GetVehiclesByWeight())
+			rt.Get("/stats", hd.vehicles.GetStats())
+			rt.Get("/histogram", hd.vehicles.GetHistogram())
+			rt.Get("/frequencies", hd.vehicles.GetFrequencies())
+			rt.Get("/search", hd.vehicles.Search())
+			rt.Get("/compare", hd.vehicles.Compare())
+			rt.Get("/units", hd.vehicles.GetUnits())
+			rt.Get("/emissions", hd.vehicles.GetEmissionsReport())
+			rt.Get("/{id}/assignments", hd.drivers.GetVehicleAssignments())
+			rt.Get("/{id}/maintenance", hd.maintenance.GetRecords())
+			rt.Get("/maintenance/due", hd.maintenance.GetDue())
+			rt.Get("/maintenance/costs", hd.maintenance.GetCosts())
+			rt.Get("/{id}/fuel_logs", hd.fuel.GetReport())
+			rt.Get("/stats/consumption", hd.fuel.GetStats())
+			rt.Get("/available", hd.reservations.GetAvailable())
+			rt.Get("/{id}/reservations", hd.reservations.GetVehicleReservations())
+			rt.Get("/{id}/reservations/{reservation_id}", hd.reservations.GetReservation())
+			rt.Get("/{id}/reservations.ics", hd.reservations.GetCalendar())
+		})
+
+		// - writes
+		rt.Group(func(rt chi.Router) {
+			rt.Use(md.rate.Handler(internal.RateClassWrite))
+			rt.With(md.idempotency.Handler).Post("/", hd.vehicles.Add())
+			rt.Put("/{id}/update_speed", hd.vehicles.UpdateMaxSpeedById())
+			rt.Delete("/{id}", hd.vehicles.DeleteById())
+			rt.Post("/{id}/restore", hd.vehicles.RestoreById())
+			rt.Put("/{id}/update_fuel", hd.vehicles.UpdateFuelTypeById())
+			rt.Put("/{id}/update_registration", hd.vehicles.UpdateRegistrationById())
+			rt.Put("/{id}/update_depot", hd.vehicles.UpdateDepotById())
+			rt.With(md.idempotency.Handler).Post("/{id}/assignment", hd.drivers.Assign())
+			rt.Delete("/{id}/assignment", hd.drivers.Unassign())
+			rt.With(md.idempotency.Handler).Post("/{id}/maintenance", hd.maintenance.AddRecord())
+			rt.With(md.idempotency.Handler).Post("/{id}/fuel_logs", hd.fuel.AddLog())
+			rt.With(md.idempotency.Handler).Post("/{id}/reservations", hd.reservations.Reserve())
+			rt.Delete("/{id}/reservations/{reservation_id}", hd.reservations.Cancel())
+		})
+
+		// - batches
+		rt.Group(func(rt chi.Router) {
+			rt.Use(md.rate.Handler(internal.RateClassBatch))
+			rt.With(md.idempotency.Handler).Post("/batch", hd.vehicles.AddMultiple())
+			rt.Post("/normalize", hd.vehicles.NormalizeAll())
+		})
+	}
+}
+
+// depotRoutes is a function that returns the registration of the endpoints of the depots
+// - the vehicles of a depot are listed by GET /vehicles?depot={id}
+func depotRoutes(hd handlers, md middlewares) func(rt chi.Router) {
+	return func(rt chi.Router) {
+		rt.Use(md.rate.AuthFailures)
+		rt.Use(md.auth.Handler)
+		rt.Use(md.tenant.Handler)
+
+		rt.With(md.rate.Handler(internal.RateClassRead)).Get("/", hd.depots.GetAll())
+		rt.With(md.rate.Handler(internal.RateClassRead)).Get("/{id}", hd.depots.GetById())
+		rt.With(md.rate.Handler(internal.RateClassWrite), md.idempotency.Handler).Post("/", hd.depots.Add())
+	}
+}
+
+// driverRoutes is a function that returns the registration of the endpoints of the drivers
+// - the assignments are created and ended on the endpoints of their vehicles
+func driverRoutes(hd handlers, md middlewares) func(rt chi.Router) {
+	return func(rt chi.Router) {
+		rt.Use(md.rate.AuthFailures)
+		rt.Use(md.auth.Handler)
+		rt.Use(md.tenant.Handler)
+
+		rt.With(md.rate.Handler(internal.RateClassRead)).Get("/", hd.drivers.GetAll())
+		rt.With(md.rate.Handler(internal.RateClassRead)).Get("/{id}", hd.drivers.GetById())
+		rt.With(md.rate.Handler(internal.RateClassRead)).Get("/{id}/assignments", hd.drivers.GetAssignments())
+		rt.With(md.rate.Handler(internal.RateClassWrite), md.idempotency.Handler).Post("/", hd.drivers.Add())
+	}
 }
\ No newline at end of file
@@ -0,0 +1,675 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/handler"
+	"app/internal/loader"
+	"app/internal/repository"
+	"app/internal/service"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// routeCase is a struct that represents a request to a route and the permission it needs
+type routeCase struct {
+	method     string
+	pattern    string
+	path       string
+	body       string
+	permission internal.Permission
+}
+
+// routeCases are the requests to every route of the vehicles, depots and drivers
+var routeCases = []routeCase{
+	{method: http.MethodGet, pattern: "/vehicles/", path: "/vehicles/", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodPost, pattern: "/vehicles/", path: "/vehicles/", body: `{"brand":"Fiat","model":"Uno","registration":"NEW-1","color":"red","year":2010,"passengers":5,"max_speed":150,"fuel_type":"gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}`, permission: internal.PermissionVehiclesCreate},
+	{method: http.MethodGet, pattern: "/vehicles/{id}", path: "/vehicles/1", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/color/{color}/year/{year}", path: "/vehicles/color/red/year/2010", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/brand/{brand}/between/{start_year}/{end_year}", path: "/vehicles/brand/Ford/between/2000/2020", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/average_speed/brand/{brand}", path: "/vehicles/average_speed/brand/Ford", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodPost, pattern: "/vehicles/batch", path: "/vehicles/batch", body: `[{"brand":"Fiat","model":"Uno","registration":"NEW-2","color":"red","year":2010,"passengers":5,"max_speed":150,"fuel_type":"gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}]`, permission: internal.PermissionVehiclesBatchImport},
+	{method: http.MethodPut, pattern: "/vehicles/{id}/update_speed", path: "/vehicles/1/update_speed", body: `{"max_speed":180}`, permission: internal.PermissionVehiclesUpdateSpeed},
+	{method: http.MethodGet, pattern: "/vehicles/fuel_type/{fuel_type}", path: "/vehicles/fuel_type/diesel", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodDelete, pattern: "/vehicles/{id}", path: "/vehicles/1", permission: internal.PermissionVehiclesDelete},
+	{method: http.MethodPost, pattern: "/vehicles/{id}/restore", path: "/vehicles/1/restore", permission: internal.PermissionVehiclesRestore},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/similar", path: "/vehicles/1/similar", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/emissions", path: "/vehicles/1/emissions", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/quote", path: "/vehicles/1/quote?from=2030-01-01&to=2030-01-03", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/transmission/{type}", path: "/vehicles/transmission/manual", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodPut, pattern: "/vehicles/{id}/update_fuel", path: "/vehicles/1/update_fuel", body: `{"fuel_type":"gasoline"}`, permission: internal.PermissionVehiclesUpdateFuel},
+	{method: http.MethodPut, pattern: "/vehicles/{id}/update_registration", path: "/vehicles/1/update_registration", body: `{"registration":"NEW-3"}`, permission: internal.PermissionVehiclesUpdateRegistration},
+	{method: http.MethodGet, pattern: "/vehicles/registration/{registration}", path: "/vehicles/registration/OLD-1", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodPost, pattern: "/vehicles/registration/validate", path: "/vehicles/registration/validate", body: `{"registration":"NEW-4"}`, permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/vin/{vin}", path: "/vehicles/vin/1HGCM82633A004352", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/average_capacity/brand/{brand}", path: "/vehicles/average_capacity/brand/Ford", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/dimensions", path: "/vehicles/dimensions?length=0-1000&width=0-1000", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/weight", path: "/vehicles/weight?min=0&max=5000", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/stats", path: "/vehicles/stats?field=max_speed", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/histogram", path: "/vehicles/histogram?field=max_speed&buckets=2", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/frequencies", path: "/vehicles/frequencies?field=brand", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/search", path: "/vehicles/search?q=ford", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/compare", path: "/vehicles/compare?ids=1,2", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodGet, pattern: "/vehicles/units", path: "/vehicles/units"},
+	{method: http.MethodGet, pattern: "/vehicles/emissions", path: "/vehicles/emissions", permission: internal.PermissionVehiclesRead},
+	{method: http.MethodPost, pattern: "/vehicles/normalize", path: "/vehicles/normalize?dry_run=true", permission: internal.PermissionVehiclesNormalize},
+	{method: http.MethodPut, pattern: "/vehicles/{id}/update_depot", path: "/vehicles/1/update_depot", body: `{"depot_id":1}`, permission: internal.PermissionVehiclesUpdateDepot},
+	{method: http.MethodPost, pattern: "/vehicles/{id}/assignment", path: "/vehicles/1/assignment", body: `{"driver_id":1}`, permission: internal.PermissionDriversAssign},
+	{method: http.MethodDelete, pattern: "/vehicles/{id}/assignment", path: "/vehicles/1/assignment", permission: internal.PermissionDriversAssign},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/assignments", path: "/vehicles/1/assignments", permission: internal.PermissionDriversRead},
+	{method: http.MethodGet, pattern: "/depots/", path: "/depots/", permission: internal.PermissionDepotsRead},
+	{method: http.MethodPost, pattern: "/depots/", path: "/depots/", body: `{"name":"North","city":"Leeds"}`, permission: internal.PermissionDepotsCreate},
+	{method: http.MethodGet, pattern: "/depots/{id}", path: "/depots/1", permission: internal.PermissionDepotsRead},
+	{method: http.MethodGet, pattern: "/drivers/", path: "/drivers/", permission: internal.PermissionDriversRead},
+	{method: http.MethodPost, pattern: "/drivers/", path: "/drivers/", body: `{"name":"John Roe","license_number":"LIC-2"}`, permission: internal.PermissionDriversCreate},
+	{method: http.MethodGet, pattern: "/drivers/{id}", path: "/drivers/1", permission: internal.PermissionDriversRead},
+	{method: http.MethodGet, pattern: "/drivers/{id}/assignments", path: "/drivers/1/assignments", permission: internal.PermissionDriversRead},
+	{method: http.MethodPost, pattern: "/vehicles/{id}/maintenance", path: "/vehicles/1/maintenance", body: `{"date":"2024-01-10","odometer":1000,"type":"inspection","cost":80}`, permission: internal.PermissionMaintenanceCreate},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/maintenance", path: "/vehicles/1/maintenance", permission: internal.PermissionMaintenanceRead},
+	{method: http.MethodGet, pattern: "/vehicles/maintenance/due", path: "/vehicles/maintenance/due?within=30d", permission: internal.PermissionMaintenanceRead},
+	{method: http.MethodGet, pattern: "/vehicles/maintenance/costs", path: "/vehicles/maintenance/costs?group_by=brand", permission: internal.PermissionMaintenanceRead},
+	{method: http.MethodPost, pattern: "/vehicles/{id}/fuel_logs", path: "/vehicles/1/fuel_logs", body: `{"date":"2024-01-10T08:00:00Z","odometer":1000,"amount":40,"cost":70}`, permission: internal.PermissionFuelCreate},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/fuel_logs", path: "/vehicles/1/fuel_logs?window=3", permission: internal.PermissionFuelRead},
+	{method: http.MethodGet, pattern: "/vehicles/stats/consumption", path: "/vehicles/stats/consumption?brand=Ford", permission: internal.PermissionFuelRead},
+	{method: http.MethodPost, pattern: "/vehicles/{id}/reservations", path: "/vehicles/1/reservations", body: `{"holder":"jane","from":"2999-01-10","to":"2999-01-11"}`, permission: internal.PermissionReservationsCreate},
+	{method: http.MethodDelete, pattern: "/vehicles/{id}/reservations/{reservation_id}", path: "/vehicles/1/reservations/1", permission: internal.PermissionReservationsCancel},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/reservations", path: "/vehicles/1/reservations", permission: internal.PermissionReservationsRead},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/reservations/{reservation_id}", path: "/vehicles/1/reservations/1", permission: internal.PermissionReservationsRead},
+	{method: http.MethodGet, pattern: "/vehicles/{id}/reservations.ics", path: "/vehicles/1/reservations.ics", permission: internal.PermissionReservationsRead},
+	{method: http.MethodGet, pattern: "/vehicles/available", path: "/vehicles/available?from=2999-01-10&to=2999-01-11&min_passengers=2", permission: internal.PermissionReservationsRead},
+}
+
+// rolePermissions are the permissions each role of docs/auth/policy.json is expected to have
+var rolePermissions = map[string][]internal.Permission{
+	"viewer": {internal.PermissionVehiclesRead, internal.PermissionDepotsRead, internal.PermissionDriversRead, internal.PermissionMaintenanceRead,
+		internal.PermissionFuelRead, internal.PermissionReservationsRead},
+	"fleet_manager": {internal.PermissionVehiclesRead, internal.PermissionDepotsRead, internal.PermissionDriversRead,
+		internal.PermissionMaintenanceRead, internal.PermissionVehiclesCreate, internal.PermissionVehiclesUpdateSpeed,
+		internal.PermissionVehiclesUpdateFuel, internal.PermissionVehiclesUpdateRegistration, internal.PermissionVehiclesUpdateDepot,
+		internal.PermissionDriversCreate, internal.PermissionDriversAssign, internal.PermissionMaintenanceCreate,
+		internal.PermissionFuelRead, internal.PermissionFuelCreate, internal.PermissionReservationsRead, internal.PermissionReservationsCreate,
+		internal.PermissionReservationsCancel},
+	"admin": {internal.PermissionVehiclesRead, internal.PermissionDepotsRead, internal.PermissionDriversRead,
+		internal.PermissionMaintenanceRead, internal.PermissionFuelRead, internal.PermissionFuelCreate, internal.PermissionReservationsRead,
+		internal.PermissionReservationsCreate, internal.PermissionReservationsCancel, internal.PermissionVehiclesCreate, internal.PermissionVehiclesUpdateSpeed,
+		internal.PermissionVehiclesUpdateFuel, internal.PermissionVehiclesUpdateRegistration, internal.PermissionVehiclesUpdateDepot,
+		internal.PermissionDriversCreate, internal.PermissionDriversAssign, internal.PermissionMaintenanceCreate, internal.PermissionVehiclesDelete, internal.PermissionVehiclesRestore,
+		internal.PermissionVehiclesBatchImport, internal.PermissionVehiclesNormalize, internal.PermissionDepotsCreate,
+		internal.PermissionTenantsSwitch, internal.PermissionVehiclesExplicitId},
+}
+
+// newTestRouter is a function that returns the router of the vehicles, depots and drivers with the policy of the docs
+// - the tenants are default and acme, each with a Ford of id 1 and its own sequence, depot 1 and driver 1
+// - the API key of each role is its name, unbound to a tenant, and acme-admin is an admin bound to acme
+// - the requests are limited by rl, nil for no limits
+func newTestRouter(t *testing.T, rl internal.RateLimiter) *chi.Mux {
+	t.Helper()
+	dbs := map[string]map[int]internal.Vehicle{
+		internal.DefaultTenant: {
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", Registration: "OLD-1", Color: "red", FabricationYear: 2010, Capacity: 5, MaxSpeed: 180, FuelType: "diesel", Transmission: "manual", Weight: 1300, Dimensions: internal.Dimensions{Height: 150, Length: 430, Width: 180}}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi", Model: "A4", Registration: "OLD-2", Color: "blue", FabricationYear: 2015, Capacity: 5, MaxSpeed: 220, FuelType: "gasoline", Transmission: "automatic", Weight: 1500, Dimensions: internal.Dimensions{Height: 140, Length: 470, Width: 185}}},
+		},
+		"acme": {
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Transit", Registration: "ACME-1", Color: "white", FabricationYear: 2018, Capacity: 3, MaxSpeed: 140, FuelType: "diesel", Transmission: "manual", Weight: 2500, Dimensions: internal.Dimensions{Height: 250, Length: 550, Width: 200}}},
+		},
+	}
+	services := make(map[string]internal.VehicleService)
+	depots := make(map[string]internal.DepotService)
+	drivers := make(map[string]internal.DriverService)
+	maintenance := make(map[string]internal.MaintenanceService)
+	fuel := make(map[string]internal.FuelService)
+	reservations := make(map[string]internal.ReservationService)
+	schedule, err := loader.NewMaintenanceRulesJSONFile("../../docs/maintenance/schedule.json").Load()
+	require.NoError(t, err)
+	for tenant, db := range dbs {
+		sq, err := repository.NewVehicleSequenceFile("")
+		require.NoError(t, err)
+		require.NoError(t, sq.Advance(len(db)))
+		for id, v := range db {
+			v.Tenant = tenant
+			db[id] = v
+		}
+		rp := repository.NewVehicleMap(db)
+		dp := repository.NewDepotMap(map[int]internal.Depot{1: {Id: 1, Tenant: tenant, Name: "Central", City: "London"}})
+		dr := repository.NewDriverMap(map[int]internal.Driver{1: {Id: 1, Tenant: tenant, Name: "Jane Doe", LicenseNumber: "LIC1"}})
+		services[tenant] = service.NewVehicleDefault(rp, &service.ConfigVehicleDefault{Tenant: tenant, Sequence: sq, Depots: dp})
+		depots[tenant] = service.NewDepotDefault(dp, tenant)
+		drivers[tenant] = service.NewDriverDefault(dr, rp, tenant)
+		fuel[tenant] = service.NewFuelDefault(repository.NewFuelLogMap(), rp)
+		reservations[tenant] = service.NewReservationDefault(repository.NewReservationMap(), rp)
+		maintenance[tenant], err = service.NewMaintenanceDefault(repository.NewMaintenanceMap(), rp, schedule)
+		require.NoError(t, err)
+	}
+	tp := service.NewTenantProvider(services, service.NewVehicleAuthorized)
+
+	var cfg internal.AuthConfig
+	for role := range rolePermissions {
+		sum := sha256.Sum256([]byte(role))
+		cfg.APIKeys = append(cfg.APIKeys, internal.APIKey{Id: role, Hash: hex.EncodeToString(sum[:]), Roles: []string{role}})
+	}
+	sum := sha256.Sum256([]byte("acme-admin"))
+	cfg.APIKeys = append(cfg.APIKeys, internal.APIKey{Id: "acme-admin", Hash: hex.EncodeToString(sum[:]), Roles: []string{"admin"}, Tenant: "acme"})
+	au, err := service.NewAuthDefault(cfg)
+	require.NoError(t, err)
+	policy, err := loader.NewPolicyJSONFile("../../docs/auth/policy.json").Load()
+	require.NoError(t, err)
+	az, err := service.NewPolicyDefault(policy)
+	require.NoError(t, err)
+
+	hd := handlers{
+		vehicles:     handler.NewVehicleDefault(service.NewAuthorizedProvider(tp, az, service.NewVehicleAuthorized)),
+		depots:       handler.NewDepotDefault(service.NewAuthorizedProvider(service.NewTenantProvider(depots, service.NewDepotAuthorized), az, service.NewDepotAuthorized)),
+		drivers:      handler.NewDriverDefault(service.NewAuthorizedProvider(service.NewTenantProvider(drivers, service.NewDriverAuthorized), az, service.NewDriverAuthorized)),
+		maintenance:  handler.NewMaintenanceDefault(service.NewAuthorizedProvider(service.NewTenantProvider(maintenance, service.NewMaintenanceAuthorized), az, service.NewMaintenanceAuthorized)),
+		fuel:         handler.NewFuelDefault(service.NewAuthorizedProvider(service.NewTenantProvider(fuel, service.NewFuelAuthorized), az, service.NewFuelAuthorized)),
+		reservations: handler.NewReservationDefault(service.NewAuthorizedProvider(service.NewTenantProvider(reservations, service.NewReservationAuthorized), az, service.NewReservationAuthorized)),
+	}
+	md := middlewares{
+		auth:        handler.NewAuthMiddleware(au, nil),
+		tenant:      handler.NewTenantMiddleware(tp, az),
+		rate:        handler.NewRateLimitMiddleware(rl),
+		idempotency: handler.NewIdempotencyMiddleware(repository.NewIdempotencyMap(time.Hour)),
+	}
+	rt := chi.NewRouter()
+	rt.Route("/vehicles", vehicleRoutes(hd, md))
+	rt.Route("/depots", depotRoutes(hd, md))
+	rt.Route("/drivers", driverRoutes(hd, md))
+	return rt
+}
+
+// serve is a function that sends a request to a router with the API key and the tenant header when not empty
+func serve(rt http.Handler, method, path, body, key, tenant string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	if tenant != "" {
+		req.Header.Set("X-Tenant-Id", tenant)
+	}
+	res := httptest.NewRecorder()
+	rt.ServeHTTP(res, req)
+	return res
+}
+
+// Tests for the authorization of the routes of the vehicles
+func TestVehicleRoutes_EveryRouteIsCovered(t *testing.T) {
+	// arrange
+	rt := newTestRouter(t, nil)
+	covered := make(map[string]bool)
+	for _, c := range routeCases {
+		covered[c.method+" "+c.pattern] = true
+	}
+
+	// act
+	var registered []string
+	err := chi.Walk(rt, func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		registered = append(registered, method+" "+route)
+		return nil
+	})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, registered, len(routeCases))
+	for _, route := range registered {
+		require.True(t, covered[route], "route %s has no authorization case", route)
+	}
+}
+
+func TestVehicleRoutes_Authorization(t *testing.T) {
+	for _, c := range routeCases {
+		for _, role := range []string{"", "viewer", "fleet_manager", "admin"} {
+			t.Run(c.method+" "+c.path+" as "+role, func(t *testing.T) {
+				// arrange
+				rt := newTestRouter(t, nil)
+
+				// act
+				res := serve(rt, c.method, c.path, c.body, role, "")
+
+				// assert
+				allowed := c.permission == ""
+				for _, p := range rolePermissions[role] {
+					allowed = allowed || p == c.permission
+				}
+				switch {
+				case role == "" && c.method != http.MethodGet:
+					require.Equal(t, http.StatusUnauthorized, res.Code)
+				case role == "" && c.method == http.MethodGet:
+					require.NotEqual(t, http.StatusForbidden, res.Code, res.Body.String())
+				case allowed:
+					require.NotEqual(t, http.StatusForbidden, res.Code, res.Body.String())
+					require.NotEqual(t, http.StatusUnauthorized, res.Code, res.Body.String())
+				default:
+					require.Equal(t, http.StatusForbidden, res.Code, res.Body.String())
+					require.Equal(t, "application/problem+json", res.Header().Get("Content-Type"))
+					var problem handler.ProblemJSON
+					require.NoError(t, json.NewDecoder(res.Body).Decode(&problem))
+					require.Equal(t, string(c.permission), problem.Permission)
+				}
+			})
+		}
+	}
+}
+
+// Tests for the isolation of the tenants
+func TestVehicleRoutes_Tenants(t *testing.T) {
+	// data is a function that decodes the data of a response
+	data := func(t *testing.T, res *httptest.ResponseRecorder, v any) {
+		t.Helper()
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &struct{ Data any }{Data: v}))
+	}
+
+	t.Run("case 1: aggregates scoped to the tenant of the header, only the cross-tenant principals choose it", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		var speedDefault, speedAcme float64
+		data(t, serve(rt, http.MethodGet, "/vehicles/average_speed/brand/Ford", "", "", ""), &speedDefault)
+		data(t, serve(rt, http.MethodGet, "/vehicles/average_speed/brand/Ford", "", "admin", "acme"), &speedAcme)
+		anonymous := serve(rt, http.MethodGet, "/vehicles/average_speed/brand/Ford", "", "", "acme")
+		viewer := serve(rt, http.MethodGet, "/vehicles/average_speed/brand/Ford", "", "viewer", "acme")
+		named := serve(rt, http.MethodGet, "/vehicles/average_speed/brand/Ford", "", "", internal.DefaultTenant)
+
+		// assert
+		require.Equal(t, 180.0, speedDefault)
+		require.Equal(t, 140.0, speedAcme)
+		require.Equal(t, http.StatusForbidden, anonymous.Code)
+		require.Equal(t, http.StatusForbidden, viewer.Code)
+		require.Equal(t, http.StatusOK, named.Code)
+	})
+
+	t.Run("case 2: principal bound to a tenant", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		var v handler.VehicleJSON
+		data(t, serve(rt, http.MethodGet, "/vehicles/1", "", "acme-admin", ""), &v)
+		res := serve(rt, http.MethodGet, "/vehicles/1", "", "acme-admin", internal.DefaultTenant)
+
+		// assert
+		require.Equal(t, "acme", v.Tenant)
+		require.Equal(t, "Transit", v.Model)
+		require.Equal(t, http.StatusForbidden, res.Code)
+	})
+
+	t.Run("case 3: unknown tenant", func(t *testing.T) {
+		// act
+		res := serve(newTestRouter(t, nil), http.MethodGet, "/vehicles/", "", "admin", "globex")
+
+		// assert
+		require.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("case 4: id space of each tenant", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		body := `{"brand":"Fiat","model":"Uno","registration":"NEW-1","color":"red","year":2010,"passengers":5,"max_speed":150,"fuel_type":"gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}`
+
+		// act
+		resAcme := serve(rt, http.MethodPost, "/vehicles/", body, "acme-admin", "")
+		resDefault := serve(rt, http.MethodPost, "/vehicles/", body, "admin", "")
+
+		// assert
+		require.Equal(t, http.StatusCreated, resAcme.Code, resAcme.Body.String())
+		require.Equal(t, "/vehicles/2", resAcme.Header().Get("Location"))
+		require.Equal(t, http.StatusCreated, resDefault.Code, resDefault.Body.String())
+		require.Equal(t, "/vehicles/3", resDefault.Header().Get("Location"))
+	})
+}
+
+// Tests for the depots and drivers of the vehicles
+// Tests for the vehicles added with their ids
+// Tests for the creation of the vehicles
+func TestVehicleRoutes_Add(t *testing.T) {
+	t.Run("case 1: the response is the stored vehicle", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		body := `{"brand":"fiat","model":"Uno","registration":"new 1","color":"RED","year":2010,"passengers":5,"max_speed":150,"fuel_type":"Gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}`
+
+		// act
+		created := serve(rt, http.MethodPost, "/vehicles/", body, "fleet_manager", "")
+		stored := serve(rt, http.MethodGet, created.Header().Get("Location"), "", "", "")
+
+		// assert
+		require.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+		require.Equal(t, http.StatusOK, stored.Code, stored.Body.String())
+		var got, want struct{ Data handler.VehicleJSON }
+		require.NoError(t, json.Unmarshal(created.Body.Bytes(), &got))
+		require.NoError(t, json.Unmarshal(stored.Body.Bytes(), &want))
+		require.NotZero(t, got.Data.ID)
+		require.NotEqual(t, "new 1", got.Data.Registration)
+		require.Equal(t, want.Data, got.Data)
+	})
+}
+
+func TestVehicleRoutes_ExplicitIds(t *testing.T) {
+	t.Run("case 1: only the principals allowed to choose the ids add vehicles with them", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		body := `{"id":10,"brand":"Fiat","model":"Uno","registration":"NEW-1","color":"red","year":2010,"passengers":5,"max_speed":150,"fuel_type":"gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}`
+
+		// act
+		denied := serve(rt, http.MethodPost, "/vehicles/", body, "fleet_manager", "")
+		created := serve(rt, http.MethodPost, "/vehicles/", body, "admin", "")
+
+		// assert
+		require.Equal(t, http.StatusForbidden, denied.Code)
+		var problem handler.ProblemJSON
+		require.NoError(t, json.NewDecoder(denied.Body).Decode(&problem))
+		require.Equal(t, string(internal.PermissionVehiclesExplicitId), problem.Permission)
+		require.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+		require.Equal(t, "/vehicles/10", created.Header().Get("Location"))
+	})
+
+	t.Run("case 2: explicit ids start at 1", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		vehicle := `{"id":%d,"brand":"Fiat","model":"Uno","registration":"NEW-%d","color":"red","year":2010,"passengers":5,"max_speed":150,"fuel_type":"gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}`
+
+		// act
+		negative := serve(rt, http.MethodPost, "/vehicles/", fmt.Sprintf(vehicle, -1, 1), "admin", "")
+		batch := serve(rt, http.MethodPost, "/vehicles/batch", "["+fmt.Sprintf(vehicle, -2, 2)+"]", "admin", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, negative.Code, negative.Body.String())
+		require.Contains(t, negative.Body.String(), internal.ErrInvalidVehicleId.Error())
+		require.Equal(t, http.StatusBadRequest, batch.Code, batch.Body.String())
+	})
+}
+
+// Tests for the soft deletes of the vehicles
+func TestVehicleRoutes_SoftDelete(t *testing.T) {
+	t.Run("case 1: deleted vehicles are hidden unless included and restored once", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		deleted := serve(rt, http.MethodDelete, "/vehicles/1", "", "admin", "")
+		hidden := serve(rt, http.MethodGet, "/vehicles/1", "", "", "")
+		included := serve(rt, http.MethodGet, "/vehicles/1?include_deleted=true", "", "", "")
+		restored := serve(rt, http.MethodPost, "/vehicles/1/restore", "", "admin", "")
+		again := serve(rt, http.MethodPost, "/vehicles/1/restore", "", "admin", "")
+		unknown := serve(rt, http.MethodPost, "/vehicles/9/restore", "", "admin", "")
+		visible := serve(rt, http.MethodGet, "/vehicles/1", "", "", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, deleted.Code, deleted.Body.String())
+		require.Equal(t, http.StatusNotFound, hidden.Code)
+		require.Equal(t, http.StatusOK, included.Code)
+		require.Equal(t, http.StatusOK, restored.Code, restored.Body.String())
+		require.Equal(t, http.StatusConflict, again.Code)
+		require.Equal(t, http.StatusNotFound, unknown.Code)
+		require.Equal(t, http.StatusOK, visible.Code)
+	})
+}
+
+func TestVehicleRoutes_Fleet(t *testing.T) {
+	// data is a function that decodes the data of a response
+	data := func(t *testing.T, res *httptest.ResponseRecorder, v any) {
+		t.Helper()
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &struct{ Data any }{Data: v}), res.Body.String())
+	}
+
+	t.Run("case 1: vehicles of a depot with a minimum capacity", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		require.Equal(t, http.StatusOK, serve(rt, http.MethodPut, "/vehicles/1/update_depot", `{"depot_id":1}`, "fleet_manager", "").Code)
+		require.Equal(t, http.StatusOK, serve(rt, http.MethodPut, "/vehicles/1/update_depot", `{"depot_id":1}`, "acme-admin", "").Code)
+
+		// act
+		var inDepot, roomy map[int]handler.VehicleJSON
+		data(t, serve(rt, http.MethodGet, "/vehicles/?depot=1", "", "", ""), &inDepot)
+		data(t, serve(rt, http.MethodGet, "/vehicles/?depot=1&min_passengers=5", "", "acme-admin", ""), &roomy)
+
+		// assert
+		require.Len(t, inDepot, 1)
+		require.Equal(t, 1, inDepot[1].DepotId)
+		require.Empty(t, roomy)
+	})
+
+	t.Run("case 2: unknown depot", func(t *testing.T) {
+		// act
+		res := serve(newTestRouter(t, nil), http.MethodPut, "/vehicles/1/update_depot", `{"depot_id":7}`, "fleet_manager", "")
+
+		// assert
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	})
+
+	t.Run("case 3: assignments and their history", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		assigned := serve(rt, http.MethodPost, "/vehicles/1/assignment", `{"driver_id":1}`, "fleet_manager", "")
+		busy := serve(rt, http.MethodPost, "/vehicles/2/assignment", `{"driver_id":1}`, "fleet_manager", "")
+		unassigned := serve(rt, http.MethodDelete, "/vehicles/1/assignment", "", "fleet_manager", "")
+		again := serve(rt, http.MethodDelete, "/vehicles/1/assignment", "", "fleet_manager", "")
+		reassigned := serve(rt, http.MethodPost, "/vehicles/2/assignment", `{"driver_id":1}`, "fleet_manager", "")
+		var history []handler.AssignmentJSON
+		data(t, serve(rt, http.MethodGet, "/drivers/1/assignments", "", "", ""), &history)
+
+		// assert
+		require.Equal(t, http.StatusCreated, assigned.Code, assigned.Body.String())
+		require.Equal(t, http.StatusConflict, busy.Code)
+		require.Equal(t, http.StatusOK, unassigned.Code)
+		require.Equal(t, http.StatusNotFound, again.Code)
+		require.Equal(t, http.StatusCreated, reassigned.Code)
+		require.Len(t, history, 2)
+		require.Equal(t, 1, history[0].VehicleId)
+		require.False(t, history[0].Current)
+		require.NotNil(t, history[0].To)
+		require.Equal(t, 2, history[1].VehicleId)
+		require.True(t, history[1].Current)
+	})
+
+	t.Run("case 4: drivers of each tenant", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		created := serve(rt, http.MethodPost, "/drivers/", `{"name":"John Roe","license_number":"lic-2"}`, "acme-admin", "")
+		var acme, other map[int]handler.DriverJSON
+		data(t, serve(rt, http.MethodGet, "/drivers/", "", "acme-admin", ""), &acme)
+		data(t, serve(rt, http.MethodGet, "/drivers/", "", "", ""), &other)
+
+		// assert
+		require.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+		require.Equal(t, "/drivers/2", created.Header().Get("Location"))
+		require.Equal(t, "LIC2", acme[2].LicenseNumber)
+		require.Equal(t, "acme", acme[2].Tenant)
+		require.Len(t, other, 1)
+	})
+}
+
+// Tests for the rate limits of the routes of the vehicles
+func TestVehicleRoutes_RateLimits(t *testing.T) {
+	// arrange
+	rl, err := repository.NewRateLimiterMap(internal.RateLimits{Budgets: map[internal.RateClass]internal.RateBudget{
+		internal.RateClassRead:  {PerSecond: 0.001, Burst: 2},
+		internal.RateClassBatch: {PerSecond: 0.001, Burst: 1},
+	}})
+	require.NoError(t, err)
+	rt := newTestRouter(t, rl)
+
+	t.Run("case 1: reads within and over the budget of the client", func(t *testing.T) {
+		// act
+		first := serve(rt, http.MethodGet, "/vehicles/1", "", "viewer", "")
+		second := serve(rt, http.MethodGet, "/vehicles/1", "", "viewer", "")
+		third := serve(rt, http.MethodGet, "/vehicles/1", "", "viewer", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, first.Code)
+		require.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+		require.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+		require.Equal(t, "0", second.Header().Get("RateLimit-Remaining"))
+		require.Equal(t, http.StatusTooManyRequests, third.Code)
+		require.NotEmpty(t, third.Header().Get("Retry-After"))
+	})
+
+	t.Run("case 2: budgets by client and by class", func(t *testing.T) {
+		// act
+		other := serve(rt, http.MethodGet, "/vehicles/1", "", "admin", "")
+		write := serve(rt, http.MethodPut, "/vehicles/1/update_speed", `{"max_speed":150}`, "viewer", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, other.Code)
+		require.Equal(t, http.StatusForbidden, write.Code)
+		require.Empty(t, write.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("case 3: failed authentications of an IP address are limited before authenticating", func(t *testing.T) {
+		// arrange
+		rl, err := repository.NewRateLimiterMap(internal.RateLimits{Budgets: map[internal.RateClass]internal.RateBudget{
+			internal.RateClassAuth: {PerSecond: 0.001, Burst: 2},
+		}})
+		require.NoError(t, err)
+		rt := newTestRouter(t, rl)
+
+		// act
+		valid := serve(rt, http.MethodGet, "/vehicles/1", "", "admin", "")
+		first := serve(rt, http.MethodGet, "/vehicles/1", "", "guess-1", "")
+		second := serve(rt, http.MethodPost, "/depots/", `{}`, "guess-2", "")
+		third := serve(rt, http.MethodGet, "/vehicles/1", "", "guess-3", "")
+		blocked := serve(rt, http.MethodGet, "/vehicles/1", "", "admin", "")
+
+		// assert: the successful authentications are not charged
+		require.Equal(t, http.StatusOK, valid.Code)
+		require.Equal(t, http.StatusUnauthorized, first.Code)
+		require.Equal(t, http.StatusUnauthorized, second.Code)
+		require.Equal(t, http.StatusTooManyRequests, third.Code)
+		require.NotEmpty(t, third.Header().Get("Retry-After"))
+		require.Equal(t, http.StatusTooManyRequests, blocked.Code)
+	})
+}
+
+// Tests for the idempotency keys of the creation routes
+func TestVehicleRoutes_Idempotency(t *testing.T) {
+	body := `{"brand":"Fiat","model":"Uno","registration":"NEW-1","color":"red","year":2010,"passengers":5,"max_speed":150,"fuel_type":"gasoline","transmission":"manual","weight":900,"height":140,"length":370,"width":160}`
+	// post is a function that sends a creation with an idempotency key
+	post := func(rt http.Handler, path, body, key, idempotencyKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+		res := httptest.NewRecorder()
+		rt.ServeHTTP(res, req)
+		return res
+	}
+
+	t.Run("case 1: a retry replays the first response without adding again", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		first := post(rt, "/vehicles/", body, "admin", "k-1")
+		retry := post(rt, "/vehicles/", body, "admin", "k-1")
+		var all map[int]handler.VehicleJSON
+		require.NoError(t, json.Unmarshal(serve(rt, http.MethodGet, "/vehicles/", "", "", "").Body.Bytes(), &struct{ Data any }{Data: &all}))
+
+		// assert
+		require.Equal(t, http.StatusCreated, first.Code, first.Body.String())
+		require.Equal(t, http.StatusCreated, retry.Code)
+		require.Equal(t, first.Body.String(), retry.Body.String())
+		require.Equal(t, first.Header().Get("Location"), retry.Header().Get("Location"))
+		require.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+		require.Len(t, all, 3)
+	})
+
+	t.Run("case 2: a key reused with another body", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		post(rt, "/vehicles/", body, "admin", "k-1")
+		res := post(rt, "/vehicles/", strings.Replace(body, "NEW-1", "NEW-2", 1), "admin", "k-1")
+
+		// assert
+		require.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	})
+
+	t.Run("case 3: keys scoped to the client", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		post(rt, "/vehicles/batch", "["+body+"]", "admin", "k-1")
+		res := post(rt, "/vehicles/batch", "["+body+"]", "acme-admin", "k-1")
+
+		// assert
+		require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+		require.Empty(t, res.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("case 4: bodies over the limit are rejected before being stored", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		large := `{"brand":"` + strings.Repeat("a", 8<<20) + `"}`
+
+		// act
+		res := post(rt, "/vehicles/", large, "admin", "k-large")
+		retry := post(rt, "/vehicles/", body, "admin", "k-large")
+
+		// assert
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+		require.Equal(t, http.StatusCreated, retry.Code, retry.Body.String())
+	})
+}
+
+// Tests for the reservations of the vehicles
+func TestReservationRoutes(t *testing.T) {
+	t.Run("case 1: the available vehicles are filtered and answered in the requested units", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+
+		// act
+		metric := serve(rt, http.MethodGet, "/vehicles/available?from=2999-01-10&to=2999-01-11&max_weight=3000", "", "fleet_manager", "")
+		imperial := serve(rt, http.MethodGet, "/vehicles/available?from=2999-01-10&to=2999-01-11&max_weight=3000&units=imperial", "", "fleet_manager", "")
+		invalid := serve(rt, http.MethodGet, "/vehicles/available?from=2999-01-10&to=2999-01-11&units=cubits", "", "fleet_manager", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, metric.Code, metric.Body.String())
+		require.Equal(t, http.StatusOK, imperial.Code, imperial.Body.String())
+		var all, light struct{ Data []handler.VehicleJSON }
+		require.NoError(t, json.Unmarshal(metric.Body.Bytes(), &all))
+		require.NoError(t, json.Unmarshal(imperial.Body.Bytes(), &light))
+		require.Len(t, all.Data, 2)
+		require.Len(t, light.Data, 1)
+		require.Equal(t, 1, light.Data[0].ID)
+		require.InDelta(t, 1300/0.45359237, light.Data[0].Weight, 0.01)
+		require.Equal(t, http.StatusBadRequest, invalid.Code)
+	})
+
+	t.Run("case 2: the response of a reservation is the stored one and its location identifies it", func(t *testing.T) {
+		// arrange
+		rt := newTestRouter(t, nil)
+		body := `{"holder":" jane ","from":"2999-01-10","to":"2999-01-11"}`
+
+		// act
+		created := serve(rt, http.MethodPost, "/vehicles/1/reservations", body, "fleet_manager", "")
+		stored := serve(rt, http.MethodGet, created.Header().Get("Location"), "", "fleet_manager", "")
+		other := serve(rt, http.MethodGet, strings.Replace(created.Header().Get("Location"), "/vehicles/1/", "/vehicles/2/", 1), "", "fleet_manager", "")
+
+		// assert
+		require.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+		require.Equal(t, http.StatusOK, stored.Code, stored.Body.String())
+		var got, want struct{ Data handler.ReservationJSON }
+		require.NoError(t, json.Unmarshal(created.Body.Bytes(), &got))
+		require.NoError(t, json.Unmarshal(stored.Body.Bytes(), &want))
+		require.Equal(t, fmt.Sprintf("/vehicles/1/reservations/%d", got.Data.ID), created.Header().Get("Location"))
+		require.NotNil(t, got.Data.CreatedAt)
+		require.Equal(t, "jane", got.Data.Holder)
+		require.Equal(t, want.Data, got.Data)
+		require.Equal(t, http.StatusNotFound, other.Code)
+	})
+}
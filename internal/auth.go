@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnauthenticated    = errors.New("Authentication required")
+	ErrInvalidCredentials = errors.New("Invalid credentials")
+	ErrInvalidAuthConfig  = errors.New("Invalid authentication config")
+)
+
+const (
+	// AuthMethodAPIKey is the method of the principals authenticated by an API key
+	AuthMethodAPIKey = "api_key"
+	// AuthMethodJWT is the method of the principals authenticated by a JSON Web Token
+	AuthMethodJWT = "jwt"
+)
+
+// Principal is a struct that represents the authenticated client of a request
+type Principal struct {
+	// Id is the id of the API key or the subject of the token
+	Id string
+	// Method is the way the principal was authenticated: api_key or jwt
+	Method string
+	// Roles are the roles granted to the principal
+	Roles []string
+	// Tenant is the tenant the principal is bound to, empty when it can choose any
+	Tenant string
+	// Claims are the claims of the token, empty for API keys
+	Claims map[string]any
+}
+
+// GrantKey is a method that returns the key of the principal in the grants of the policy: its method and id
+// - the ids of the API keys and the subjects of the tokens are different namespaces
+func (p Principal) GrantKey() string {
+	return p.Method + ":" + p.Id
+}
+
+// APIKey is a struct that represents an API key allowed to call the service
+type APIKey struct {
+	// Id is the id of the key, it never contains the key itself
+	Id string
+	// Hash is the SHA-256 of the key in hexadecimal
+	Hash string
+	// Roles are the roles granted to the key
+	Roles []string
+	// Tenant is the tenant the key is bound to, empty when it can choose any
+	Tenant string
+}
+
+// JWTKey is a struct that represents a key the tokens are verified against
+type JWTKey struct {
+	// Id is the id of the key, matched against the kid header of the tokens
+	Id string
+	// Algorithm is the algorithm of the signature: HS256 or RS256
+	Algorithm string
+	// Secret is the shared secret of the HS256 keys
+	Secret []byte
+	// PublicKey is the public key of the RS256 keys
+	PublicKey *rsa.PublicKey
+}
+
+// AuthConfig is a struct that represents the credentials accepted by the service
+type AuthConfig struct {
+	// APIKeys are the API keys
+	APIKeys []APIKey
+	// JWTKeys are the keys of the tokens
+	JWTKeys []JWTKey
+	// Issuer is the iss claim the tokens must have, any when empty
+	Issuer string
+	// Audience is the aud claim the tokens must contain, any when empty
+	Audience string
+	// RolesClaim is the claim with the roles of the tokens, roles by default
+	RolesClaim string
+	// TenantClaim is the claim with the tenant of the tokens, tenant by default
+	TenantClaim string
+	// Leeway is the clock skew tolerated on the time claims of the tokens
+	Leeway time.Duration
+}
+
+// AuthConfigLoader is an interface that represents the loader for the authentication config
+type AuthConfigLoader interface {
+	// Load is a method that loads the authentication config
+	Load() (c AuthConfig, err error)
+}
+
+// Authenticator is an interface that represents the verification of the credentials of the clients
+type Authenticator interface {
+	// AuthenticateAPIKey is a method that returns the principal of an API key
+	AuthenticateAPIKey(key string) (p Principal, err error)
+	// AuthenticateToken is a method that returns the principal of a JSON Web Token
+	AuthenticateToken(token string) (p Principal, err error)
+}
+
+// principalKey is the key of the principal in a context
+type principalKey struct{}
+
+// WithPrincipal is a function that returns a copy of a context carrying a principal
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom is a function that returns the principal of a context, if any
+func PrincipalFrom(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(Principal)
+	return
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrDepotNotFound      = errors.New("Depot not found")
+	ErrDepotAlreadyExists = errors.New("Depot already exists")
+	ErrInvalidDepot       = errors.New("Invalid depot")
+)
+
+// Depot is a struct that represents a depot of the fleet, the owner of its vehicles
+type Depot struct {
+	// Id is the unique identifier of the depot within its tenant
+	Id int
+	// Tenant is the id of the tenant that owns the depot
+	Tenant string
+	// Name is the name of the depot, unique within its tenant
+	Name string
+	// City is the city of the depot
+	City string
+}
+
+// DepotRepository is an interface that represents a depot repository
+type DepotRepository interface {
+	// FindAll is a method that returns a map of all depots
+	FindAll() (d map[int]Depot, err error)
+	// FindById is a method that returns a depot by id
+	FindById(id int) (d Depot, err error)
+	// Add is a method that adds a depot and returns its id
+	Add(d Depot) (id int, err error)
+}
+
+// DepotService is an interface that represents a depot service
+// - the vehicles of a depot are listed with the depot filter of the vehicles, e.g. ?depot=1&min_passengers=5
+type DepotService interface {
+	// FindAll is a method that returns a map of all depots
+	FindAll() (d map[int]Depot, err error)
+	// FindById is a method that returns a depot by id
+	FindById(id int) (d Depot, err error)
+	// Add is a method that adds a depot and returns its id
+	Add(d Depot) (id int, err error)
+}
+
+// DepotServiceProvider is an interface that represents the source of the depot service of each request
+type DepotServiceProvider interface {
+	// Service is a method that returns the depot service for a context
+	Service(ctx context.Context) DepotService
+}
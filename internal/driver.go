@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrDriverNotFound         = errors.New("Driver not found")
+	ErrDriverAlreadyExists    = errors.New("Driver already exists")
+	ErrInvalidDriver          = errors.New("Invalid driver")
+	ErrDriverAlreadyAssigned  = errors.New("Driver already assigned to a vehicle")
+	ErrVehicleAlreadyAssigned = errors.New("Vehicle already assigned to a driver")
+	ErrAssignmentNotFound     = errors.New("Vehicle not assigned to a driver")
+)
+
+// Driver is a struct that represents a driver of the fleet
+type Driver struct {
+	// Id is the unique identifier of the driver within its tenant
+	Id int
+	// Tenant is the id of the tenant that employs the driver
+	Tenant string
+	// Name is the full name of the driver
+	Name string
+	// LicenseNumber is the number of the driving license, unique within its tenant
+	LicenseNumber string
+}
+
+// NormalizeLicenseNumber is a function that returns a license number without spaces nor dashes and in upper case
+func NormalizeLicenseNumber(license string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(license))
+}
+
+// Assignment is a struct that represents a driver in charge of a vehicle for a period
+type Assignment struct {
+	// Id is the unique identifier of the assignment within its tenant
+	Id int
+	// VehicleId is the id of the assigned vehicle
+	VehicleId int
+	// DriverId is the id of the assigned driver
+	DriverId int
+	// From is the moment the driver was assigned
+	From time.Time
+	// To is the moment the driver was unassigned, nil while the assignment is current
+	To *time.Time
+}
+
+// IsCurrent is a method that reports if the assignment has not ended
+func (a Assignment) IsCurrent() bool {
+	return a.To == nil
+}
+
+// AssignmentQuery is a struct that represents the assignments to look for, zero ids match any
+type AssignmentQuery struct {
+	// VehicleId is the id of the vehicle of the assignments
+	VehicleId int
+	// DriverId is the id of the driver of the assignments
+	DriverId int
+}
+
+// DriverRepository is an interface that represents a driver repository and the history of their assignments
+type DriverRepository interface {
+	// FindAll is a method that returns a map of all drivers
+	FindAll() (d map[int]Driver, err error)
+	// FindById is a method that returns a driver by id
+	FindById(id int) (d Driver, err error)
+	// Add is a method that adds a driver and returns its id
+	Add(d Driver) (id int, err error)
+	// Assign is a method that opens an assignment, neither its vehicle nor its driver can have a current one
+	Assign(a Assignment) (id int, err error)
+	// Unassign is a method that ends the current assignment of a vehicle at a moment and returns it
+	Unassign(vehicleId int, at time.Time) (a Assignment, err error)
+	// Assignments is a method that returns the assignments matching a query, oldest first
+	Assignments(q AssignmentQuery) (a []Assignment, err error)
+}
+
+// DriverService is an interface that represents a driver service
+type DriverService interface {
+	// FindAll is a method that returns a map of all drivers
+	FindAll() (d map[int]Driver, err error)
+	// FindById is a method that returns a driver by id
+	FindById(id int) (d Driver, err error)
+	// Add is a method that adds a driver and returns its id
+	Add(d Driver) (id int, err error)
+	// Assign is a method that puts a driver in charge of a vehicle from now on
+	Assign(vehicleId int, driverId int) (a Assignment, err error)
+	// Unassign is a method that ends the current assignment of a vehicle now
+	Unassign(vehicleId int) (a Assignment, err error)
+	// VehicleAssignments is a method that returns the history of the assignments of a vehicle, oldest first
+	VehicleAssignments(vehicleId int) (a []Assignment, err error)
+	// DriverAssignments is a method that returns the history of the assignments of a driver, oldest first
+	DriverAssignments(driverId int) (a []Assignment, err error)
+}
+
+// DriverServiceProvider is an interface that represents the source of the driver service of each request
+type DriverServiceProvider interface {
+	// Service is a method that returns the driver service for a context
+	Service(ctx context.Context) DriverService
+}
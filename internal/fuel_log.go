@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFuelLog = errors.New("Invalid fuel log")
+)
+
+// FuelLog is a struct that represents a refuel, or a reading of the odometer when nothing is refueled
+// - every refuel fills the tank (or the battery) up, so its amount is what was used since the previous refuel
+type FuelLog struct {
+	// Id is the unique identifier of the log within its tenant
+	Id int
+	// VehicleId is the id of the vehicle
+	VehicleId int
+	// Date is the moment of the log
+	Date time.Time
+	// Odometer is the reading of the odometer in km
+	Odometer float64
+	// Amount is the amount refueled in the unit of the fuel type of the vehicle, 0 for a reading only
+	Amount float64
+	// Cost is the cost of the refuel
+	Cost float64
+}
+
+// FuelUnit is a function that returns the unit the amounts of a fuel type are measured in, kWh or l
+func FuelUnit(fuelType string) string {
+	if strings.EqualFold(fuelType, "electric") {
+		return "kWh"
+	}
+	return "l"
+}
+
+// FuelConsumption is a struct that represents a log and the consumption up to it
+type FuelConsumption struct {
+	// Log is the log
+	Log FuelLog
+	// Distance is the distance driven since the previous refuel in km, 0 for readings and the first refuel
+	Distance float64
+	// Consumption is the amount used every 100 km since the previous refuel, 0 for readings and the first refuel
+	Consumption float64
+	// Rolling is the amount used every 100 km over the last refuels of the window, 0 until the first consumption
+	Rolling float64
+}
+
+// FuelReport is a struct that represents the consumption of a vehicle
+type FuelReport struct {
+	// VehicleId is the id of the vehicle
+	VehicleId int
+	// Unit is the unit of the amounts, kWh or l
+	Unit string
+	// Logs are the logs with their consumption, oldest first
+	Logs []FuelConsumption
+	// Distance is the distance driven between the first and the last refuel in km
+	Distance float64
+	// Amount is the amount used between the first and the last refuel
+	Amount float64
+	// Consumption is the amount used every 100 km between the first and the last refuel, 0 without two refuels
+	Consumption float64
+}
+
+// FuelStatsQuery is a struct that represents the comparison of the consumption of the vehicles with their peers
+type FuelStatsQuery struct {
+	// Filter are the conditions the vehicles must meet to be compared
+	Filter VehicleFilter
+	// Threshold is the relative deviation from the median of the peers a vehicle is an outlier from, e.g. 0.25
+	Threshold float64
+}
+
+// FuelOutlier is a struct that represents a vehicle whose consumption deviates from its peers
+type FuelOutlier struct {
+	// VehicleId is the id of the vehicle
+	VehicleId int
+	// Consumption is the amount the vehicle uses every 100 km
+	Consumption float64
+	// Deviation is the relative deviation from the median of the peers, e.g. 0.3 for 30% more
+	Deviation float64
+}
+
+// FuelStats is a struct that represents the consumption of a group of peers, the vehicles of the same brand, model and fuel type
+type FuelStats struct {
+	// Stats are the statistics of the consumption, grouped by brand, model and fuel type
+	Stats VehicleStats
+	// Unit is the unit of the amounts, kWh or l
+	Unit string
+	// Outliers are the vehicles that deviate from the median the most first
+	Outliers []FuelOutlier
+}
+
+// FuelLogRepository is an interface that represents a fuel log repository
+type FuelLogRepository interface {
+	// FindAll is a method that returns every log, oldest first
+	FindAll() (l []FuelLog, err error)
+	// FindByVehicle is a method that returns the logs of a vehicle, oldest first
+	FindByVehicle(vehicleId int) (l []FuelLog, err error)
+	// Add is a method that adds a log and returns its id
+	Add(l FuelLog) (id int, err error)
+}
+
+// FuelService is an interface that represents a fuel service
+type FuelService interface {
+	// AddLog is a method that records a refuel or a reading of a vehicle and returns its id
+	AddLog(l FuelLog) (id int, err error)
+	// Report is a method that returns the consumption of a vehicle, the rolling average over the last window refuels
+	Report(vehicleId int, window int) (r FuelReport, err error)
+	// Stats is a method that compares the consumption of the vehicles with their peers
+	Stats(q FuelStatsQuery) (s []FuelStats, err error)
+}
+
+// FuelServiceProvider is an interface that represents the source of the fuel service of each request
+type FuelServiceProvider interface {
+	// Service is a method that returns the fuel service for a context
+	Service(ctx context.Context) FuelService
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// ConfigAuthMiddleware is a struct that represents the configuration for AuthMiddleware
+type ConfigAuthMiddleware struct {
+	// ProtectReads is a flag that also requires credentials on the GET and HEAD requests
+	ProtectReads bool
+}
+
+// NewAuthMiddleware is a function that returns a new instance of AuthMiddleware
+func NewAuthMiddleware(au internal.Authenticator, cfg *ConfigAuthMiddleware) *AuthMiddleware {
+	m := &AuthMiddleware{au: au}
+	if cfg != nil {
+		m.protectReads = cfg.ProtectReads
+	}
+	return m
+}
+
+// AuthMiddleware is a struct that authenticates the requests with an API key or a bearer token
+type AuthMiddleware struct {
+	// au is the authenticator of the credentials
+	au internal.Authenticator
+	// protectReads is a flag that also requires credentials on the read requests
+	protectReads bool
+}
+
+// Handler is a method that returns the middleware
+// - the requests that change data always need credentials, the reads only when protected
+// - credentials sent to an unprotected route are verified all the same, and their principal attached
+func (m *AuthMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		read := r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions
+
+		p, err := m.authenticate(r)
+		switch {
+		case err == nil:
+			r = r.WithContext(internal.WithPrincipal(r.Context(), p))
+		case errors.Is(err, internal.ErrUnauthenticated) && read && !m.protectReads:
+		default:
+			challenge := `Bearer realm="vehicles"`
+			if errors.Is(err, internal.ErrInvalidCredentials) {
+				challenge += `, error="invalid_token"`
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			response.Text(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate is a method that returns the principal of the credentials of a request
+// - X-API-Key header or Authorization: Bearer <token>
+func (m *AuthMiddleware) authenticate(r *http.Request) (p internal.Principal, err error) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return m.au.AuthenticateAPIKey(key)
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if authorization == "" {
+		return p, internal.ErrUnauthenticated
+	}
+	scheme, token, _ := strings.Cut(authorization, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return p, fmt.Errorf("%w: unsupported authorization scheme", internal.ErrInvalidCredentials)
+	}
+	return m.au.AuthenticateToken(strings.TrimSpace(token))
+}
+
+// ProblemJSON is a struct that represents a problem detail (RFC 9457) in JSON format
+type ProblemJSON struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Status     int    `json:"status"`
+	Detail     string `json:"detail"`
+	Permission string `json:"permission,omitempty"`
+}
+
+// forbidden is a function that writes a 403 problem naming the missing permission, reporting if the error was one
+func forbidden(w http.ResponseWriter, err error) bool {
+	var pe *internal.PermissionError
+	if !errors.As(err, &pe) {
+		return false
+	}
+
+	body := ProblemJSON{
+		Type:       "about:blank",
+		Title:      http.StatusText(http.StatusForbidden),
+		Status:     http.StatusForbidden,
+		Detail:     pe.Error(),
+		Permission: string(pe.Permission),
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(body)
+	return true
+}
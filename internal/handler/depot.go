@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// DepotJSON is a struct that represents a depot in JSON format
+type DepotJSON struct {
+	ID     int    `json:"id"`
+	Tenant string `json:"tenant,omitempty"`
+	Name   string `json:"name"`
+	City   string `json:"city"`
+}
+
+// serializeDepot is a function that converts a depot into its JSON representation
+func serializeDepot(d internal.Depot) DepotJSON {
+	return DepotJSON{
+		ID:     d.Id,
+		Tenant: d.Tenant,
+		Name:   d.Name,
+		City:   d.City,
+	}
+}
+
+// NewDepotDefault is a function that returns a new instance of DepotDefault
+func NewDepotDefault(sp internal.DepotServiceProvider) *DepotDefault {
+	return &DepotDefault{sp: sp}
+}
+
+// DepotDefault is a struct with methods that represent handlers for depots
+type DepotDefault struct {
+	// sp is the provider of the service that will be used by each request
+	sp internal.DepotServiceProvider
+}
+
+// sv is a method that returns the service of a request
+func (h *DepotDefault) sv(r *http.Request) internal.DepotService {
+	return h.sp.Service(r.Context())
+}
+
+// GetAll is a method that returns a handler for the route GET /depots
+// - the vehicles of a depot are listed by GET /vehicles?depot={id}
+func (h *DepotDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		d, err := h.sv(r).FindAll()
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.JSON(w, http.StatusInternalServerError, nil)
+			return
+		}
+
+		// response
+		data := make(map[int]DepotJSON, len(d))
+		for id, value := range d {
+			data[id] = serializeDepot(value)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetById is a method that returns a handler for the route GET /depots/{id}
+func (h *DepotDefault) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		d, err := h.sv(r).FindById(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "depot found successfully",
+			Data:    serializeDepot(d),
+		})
+	}
+}
+
+// Add is a method that returns a handler for the route POST /depots
+func (h *DepotDefault) Add() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body DepotJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		d := internal.Depot{Name: body.Name, City: body.City}
+
+		// process
+		id, err := h.sv(r).Add(d)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidDepot):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusConflict, err.Error())
+			}
+			return
+		}
+
+		// response
+		d.Id = id
+		w.Header().Set("Location", fmt.Sprintf("/depots/%d", id))
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "depot created successfully",
+			Data:    serializeDepot(d),
+		})
+	}
+}
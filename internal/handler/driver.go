@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// DriverJSON is a struct that represents a driver in JSON format
+type DriverJSON struct {
+	ID            int    `json:"id"`
+	Tenant        string `json:"tenant,omitempty"`
+	Name          string `json:"name"`
+	LicenseNumber string `json:"license_number"`
+}
+
+// serializeDriver is a function that converts a driver into its JSON representation
+func serializeDriver(d internal.Driver) DriverJSON {
+	return DriverJSON{
+		ID:            d.Id,
+		Tenant:        d.Tenant,
+		Name:          d.Name,
+		LicenseNumber: d.LicenseNumber,
+	}
+}
+
+// AssignmentJSON is a struct that represents an assignment in JSON format
+type AssignmentJSON struct {
+	ID        int        `json:"id"`
+	VehicleId int        `json:"vehicle_id"`
+	DriverId  int        `json:"driver_id"`
+	From      time.Time  `json:"from"`
+	To        *time.Time `json:"to,omitempty"`
+	Current   bool       `json:"current"`
+}
+
+// serializeAssignments is a function that converts assignments into their JSON representation
+func serializeAssignments(a ...internal.Assignment) []AssignmentJSON {
+	data := make([]AssignmentJSON, 0, len(a))
+	for _, value := range a {
+		data = append(data, AssignmentJSON{
+			ID:        value.Id,
+			VehicleId: value.VehicleId,
+			DriverId:  value.DriverId,
+			From:      value.From,
+			To:        value.To,
+			Current:   value.IsCurrent(),
+		})
+	}
+	return data
+}
+
+// NewDriverDefault is a function that returns a new instance of DriverDefault
+func NewDriverDefault(sp internal.DriverServiceProvider) *DriverDefault {
+	return &DriverDefault{sp: sp}
+}
+
+// DriverDefault is a struct with methods that represent handlers for drivers and their assignments to vehicles
+type DriverDefault struct {
+	// sp is the provider of the service that will be used by each request
+	sp internal.DriverServiceProvider
+}
+
+// sv is a method that returns the service of a request
+func (h *DriverDefault) sv(r *http.Request) internal.DriverService {
+	return h.sp.Service(r.Context())
+}
+
+// GetAll is a method that returns a handler for the route GET /drivers
+func (h *DriverDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// process
+		d, err := h.sv(r).FindAll()
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.JSON(w, http.StatusInternalServerError, nil)
+			return
+		}
+
+		// response
+		data := make(map[int]DriverJSON, len(d))
+		for id, value := range d {
+			data[id] = serializeDriver(value)
+		}
+		response.JSON(w, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// GetById is a method that returns a handler for the route GET /drivers/{id}
+func (h *DriverDefault) GetById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		d, err := h.sv(r).FindById(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "driver found successfully",
+			Data:    serializeDriver(d),
+		})
+	}
+}
+
+// Add is a method that returns a handler for the route POST /drivers
+func (h *DriverDefault) Add() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body DriverJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		d := internal.Driver{Name: strings.TrimSpace(body.Name), LicenseNumber: internal.NormalizeLicenseNumber(body.LicenseNumber)}
+
+		// process
+		id, err := h.sv(r).Add(d)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidDriver):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusConflict, err.Error())
+			}
+			return
+		}
+
+		// response
+		d.Id = id
+		w.Header().Set("Location", fmt.Sprintf("/drivers/%d", id))
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "driver created successfully",
+			Data:    serializeDriver(d),
+		})
+	}
+}
+
+// GetAssignments is a method that returns a handler for the route GET /drivers/{id}/assignments
+func (h *DriverDefault) GetAssignments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		a, err := h.sv(r).DriverAssignments(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "assignments found successfully",
+			Data:    serializeAssignments(a...),
+		})
+	}
+}
+
+// AssignJSON is a struct that represents the body of the route POST /vehicles/{id}/assignment
+type AssignJSON struct {
+	// DriverId is the id of the driver put in charge of the vehicle
+	DriverId int `json:"driver_id"`
+}
+
+// Assign is a method that returns a handler for the route POST /vehicles/{id}/assignment
+func (h *DriverDefault) Assign() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var body AssignJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.DriverId == 0 {
+			response.Text(w, http.StatusBadRequest, "missing driver_id")
+			return
+		}
+
+		// process
+		a, err := h.sv(r).Assign(id, body.DriverId)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrDriverNotFound):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, internal.ErrVehicleAlreadyAssigned), errors.Is(err, internal.ErrDriverAlreadyAssigned):
+				response.Text(w, http.StatusConflict, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "driver assigned successfully",
+			Data:    serializeAssignments(a)[0],
+		})
+	}
+}
+
+// Unassign is a method that returns a handler for the route DELETE /vehicles/{id}/assignment
+func (h *DriverDefault) Unassign() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		a, err := h.sv(r).Unassign(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "driver unassigned successfully",
+			Data:    serializeAssignments(a)[0],
+		})
+	}
+}
+
+// GetVehicleAssignments is a method that returns a handler for the route GET /vehicles/{id}/assignments
+func (h *DriverDefault) GetVehicleAssignments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		a, err := h.sv(r).VehicleAssignments(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "assignments found successfully",
+			Data:    serializeAssignments(a...),
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// FuelLogJSON is a struct that represents a fuel log in JSON format
+// - the date is a moment (RFC 3339) or a day (2006-01-02), the amount is 0 for a reading of the odometer only
+type FuelLogJSON struct {
+	ID        int     `json:"id"`
+	VehicleId int     `json:"vehicle_id"`
+	Date      string  `json:"date"`
+	Odometer  float64 `json:"odometer"`
+	Amount    float64 `json:"amount"`
+	Cost      float64 `json:"cost"`
+}
+
+// serializeFuelLog is a function that converts a fuel log into its JSON representation
+func serializeFuelLog(l internal.FuelLog) FuelLogJSON {
+	return FuelLogJSON{
+		ID:        l.Id,
+		VehicleId: l.VehicleId,
+		Date:      l.Date.Format(time.RFC3339),
+		Odometer:  l.Odometer,
+		Amount:    l.Amount,
+		Cost:      l.Cost,
+	}
+}
+
+// FuelConsumptionJSON is a struct that represents a fuel log and the consumption up to it in JSON format
+type FuelConsumptionJSON struct {
+	FuelLogJSON
+	Distance    float64 `json:"distance"`
+	Consumption float64 `json:"consumption"`
+	Rolling     float64 `json:"rolling"`
+}
+
+// FuelReportJSON is a struct that represents the consumption of a vehicle in JSON format
+type FuelReportJSON struct {
+	VehicleId   int                   `json:"vehicle_id"`
+	Unit        string                `json:"unit"`
+	Distance    float64               `json:"distance"`
+	Amount      float64               `json:"amount"`
+	Consumption float64               `json:"consumption"`
+	Logs        []FuelConsumptionJSON `json:"logs"`
+}
+
+// FuelOutlierJSON is a struct that represents a vehicle whose consumption deviates from its peers in JSON format
+type FuelOutlierJSON struct {
+	VehicleId   int     `json:"vehicle_id"`
+	Consumption float64 `json:"consumption"`
+	Deviation   float64 `json:"deviation"`
+}
+
+// FuelStatsJSON is a struct that represents the consumption of a group of peers in JSON format
+type FuelStatsJSON struct {
+	VehicleStatsJSON
+	Unit     string            `json:"unit"`
+	Outliers []FuelOutlierJSON `json:"outliers"`
+}
+
+// NewFuelDefault is a function that returns a new instance of FuelDefault
+func NewFuelDefault(sp internal.FuelServiceProvider) *FuelDefault {
+	return &FuelDefault{sp: sp}
+}
+
+// FuelDefault is a struct with methods that represent handlers for the fuel logs of the vehicles
+type FuelDefault struct {
+	// sp is the provider of the service that will be used by each request
+	sp internal.FuelServiceProvider
+}
+
+// sv is a method that returns the service of a request
+func (h *FuelDefault) sv(r *http.Request) internal.FuelService {
+	return h.sp.Service(r.Context())
+}
+
+// AddLog is a method that returns a handler for the route POST /vehicles/{id}/fuel_logs
+func (h *FuelDefault) AddLog() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var body FuelLogJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		log := internal.FuelLog{VehicleId: id, Odometer: body.Odometer, Amount: body.Amount, Cost: body.Cost}
+		if body.Date != "" {
+			if log.Date, err = readDate(body.Date); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid date")
+				return
+			}
+		}
+
+		// process
+		log.Id, err = h.sv(r).AddLog(log)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidFuelLog):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		w.Header().Set("Location", fmt.Sprintf("/vehicles/%d/fuel_logs", id))
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "fuel log created successfully",
+			Data:    serializeFuelLog(log),
+		})
+	}
+}
+
+// GetReport is a method that returns a handler for the route GET /vehicles/{id}/fuel_logs?window={5}
+// - the rolling average is over the last 5 refuels by default
+func (h *FuelDefault) GetReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		window := 5
+		if raw := r.URL.Query().Get("window"); raw != "" {
+			if window, err = strconv.Atoi(raw); err != nil || window < 1 {
+				response.Text(w, http.StatusBadRequest, "invalid window")
+				return
+			}
+		}
+
+		// process
+		report, err := h.sv(r).Report(id, window)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		data := FuelReportJSON{
+			VehicleId:   report.VehicleId,
+			Unit:        report.Unit,
+			Distance:    report.Distance,
+			Amount:      report.Amount,
+			Consumption: report.Consumption,
+			Logs:        make([]FuelConsumptionJSON, 0, len(report.Logs)),
+		}
+		for _, value := range report.Logs {
+			data.Logs = append(data.Logs, FuelConsumptionJSON{
+				FuelLogJSON: serializeFuelLog(value.Log),
+				Distance:    value.Distance,
+				Consumption: value.Consumption,
+				Rolling:     value.Rolling,
+			})
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "fuel logs found successfully",
+			Data:    data,
+		})
+	}
+}
+
+// GetStats is a method that returns a handler for the route GET /vehicles/stats/consumption
+// - ?threshold=0.25 plus the filters of readFilter, the vehicles deviating 25% from their peers by default
+func (h *FuelDefault) GetStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q := internal.FuelStatsQuery{Threshold: 0.25}
+		if raw := r.URL.Query().Get("threshold"); raw != "" {
+			var err error
+			if q.Threshold, err = strconv.ParseFloat(raw, 64); err != nil || q.Threshold <= 0 {
+				response.Text(w, http.StatusBadRequest, "invalid threshold")
+				return
+			}
+		}
+
+		filter, err := readFilter(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		q.Filter = filter
+
+		// process
+		stats, err := h.sv(r).Stats(q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.JSON(w, http.StatusInternalServerError, nil)
+			return
+		}
+
+		// response
+		data := make([]FuelStatsJSON, 0, len(stats))
+		for _, value := range stats {
+			st := FuelStatsJSON{
+				VehicleStatsJSON: VehicleStatsJSON{
+					Group:       value.Stats.Group,
+					Count:       value.Stats.Count,
+					Sum:         value.Stats.Sum,
+					Min:         value.Stats.Min,
+					Max:         value.Stats.Max,
+					Mean:        value.Stats.Mean,
+					Median:      value.Stats.Median,
+					StdDev:      value.Stats.StdDev,
+					Percentiles: map[string]float64{},
+				},
+				Unit:     value.Unit,
+				Outliers: make([]FuelOutlierJSON, 0, len(value.Outliers)),
+			}
+			for _, o := range value.Outliers {
+				st.Outliers = append(st.Outliers, FuelOutlierJSON(o))
+			}
+			data = append(data, st)
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "consumption stats computed successfully",
+			Data:    data,
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"app/internal"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// maxIdempotentBody is the size in bytes of the largest body of a request with an Idempotency-Key, batch imports included
+const maxIdempotentBody = 8 << 20
+
+// NewIdempotencyMiddleware is a function that returns a new instance of IdempotencyMiddleware
+func NewIdempotencyMiddleware(st internal.IdempotencyStore) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{st: st}
+}
+
+// IdempotencyMiddleware is a struct that replays the responses of the requests retried with the same Idempotency-Key
+// - the keys are scoped to the tenant and the client, so nobody can read the responses of the others
+// - the requests without the header are served as usual
+type IdempotencyMiddleware struct {
+	// st is the store of the responses
+	st internal.IdempotencyStore
+}
+
+// idempotencyRecorder is a struct that captures a response while it is written
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader is a method that captures the status code
+func (rc *idempotencyRecorder) WriteHeader(status int) {
+	rc.status = status
+	rc.ResponseWriter.WriteHeader(status)
+}
+
+// Write is a method that captures the body
+func (rc *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rc.status == 0 {
+		rc.status = http.StatusOK
+	}
+	rc.body.Write(b)
+	return rc.ResponseWriter.Write(b)
+}
+
+// Handler is a method that returns the middleware
+// - the first response of a key is stored, but the server errors so the request can be retried
+// - a replay gets the stored response with Idempotent-Replayed: true, a key reused with another body a 422
+// - the body is read to fingerprint the request, the ones over maxIdempotentBody get a 413
+func (m *IdempotencyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Idempotency-Key")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(header) > 255 {
+			response.Text(w, http.StatusBadRequest, "invalid Idempotency-Key: at most 255 characters")
+			return
+		}
+
+		// fingerprint of the request
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				response.Text(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body too large: at most %d bytes", tooLarge.Limit))
+				return
+			}
+			response.Text(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.New()
+		sum.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+		sum.Write(body)
+		fingerprint := hex.EncodeToString(sum.Sum(nil))
+
+		// key scoped to the tenant and the client
+		tenant, _ := internal.TenantFrom(r.Context())
+		key := tenant + "|" + clientKey(r) + "|" + header
+
+		stored, err := m.st.Begin(key, fingerprint)
+		switch {
+		case errors.Is(err, internal.ErrIdempotencyKeyReused):
+			response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		case errors.Is(err, internal.ErrIdempotencyKeyInProgress):
+			w.Header().Set("Retry-After", "1")
+			response.Text(w, http.StatusConflict, err.Error())
+			return
+		case err != nil:
+			log.Println("idempotency:", err)
+			response.Text(w, http.StatusInternalServerError, "idempotency store unavailable")
+			return
+		case stored != nil:
+			// the headers already set, e.g. the current budget, are not replayed
+			for name, values := range stored.Header {
+				if _, ok := w.Header()[name]; !ok {
+					w.Header()[name] = values
+				}
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.Status)
+			w.Write(stored.Body)
+			return
+		}
+
+		// first request of the key
+		rc := &idempotencyRecorder{ResponseWriter: w}
+		defer func() {
+			if rc.status == 0 || rc.status >= http.StatusInternalServerError {
+				if err := m.st.Release(key); err != nil {
+					log.Println("idempotency:", err)
+				}
+				return
+			}
+			err := m.st.Complete(key, internal.IdempotentResponse{
+				Status: rc.status,
+				Header: w.Header().Clone(),
+				Body:   rc.body.Bytes(),
+			})
+			if err != nil {
+				log.Println("idempotency:", err)
+			}
+		}()
+		next.ServeHTTP(rc, r)
+	})
+}
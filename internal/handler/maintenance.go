@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// MaintenanceRecordJSON is a struct that represents a maintenance record in JSON format
+// - the date is a day (2006-01-02) or a moment (RFC 3339)
+type MaintenanceRecordJSON struct {
+	ID        int     `json:"id"`
+	VehicleId int     `json:"vehicle_id"`
+	Date      string  `json:"date"`
+	Odometer  float64 `json:"odometer"`
+	Type      string  `json:"type"`
+	Cost      float64 `json:"cost"`
+	Notes     string  `json:"notes,omitempty"`
+}
+
+// serializeMaintenanceRecord is a function that converts a maintenance record into its JSON representation
+func serializeMaintenanceRecord(r internal.MaintenanceRecord) MaintenanceRecordJSON {
+	return MaintenanceRecordJSON{
+		ID:        r.Id,
+		VehicleId: r.VehicleId,
+		Date:      r.Date.Format(time.DateOnly),
+		Odometer:  r.Odometer,
+		Type:      r.Type,
+		Cost:      r.Cost,
+		Notes:     r.Notes,
+	}
+}
+
+// MaintenanceDueJSON is a struct that represents a due service in JSON format
+type MaintenanceDueJSON struct {
+	VehicleId    int       `json:"vehicle_id"`
+	Brand        string    `json:"brand"`
+	Type         string    `json:"type"`
+	LastDate     string    `json:"last_date"`
+	LastOdometer float64   `json:"last_odometer"`
+	Odometer     float64   `json:"odometer"`
+	DueDate      *string   `json:"due_date,omitempty"`
+	DueOdometer  *float64  `json:"due_odometer,omitempty"`
+	DueAt        time.Time `json:"due_at"`
+	Overdue      bool      `json:"overdue"`
+}
+
+// MaintenanceCostJSON is a struct that represents the costs of the services of a group in JSON format
+type MaintenanceCostJSON struct {
+	Group   string  `json:"group"`
+	Records int     `json:"records"`
+	Total   float64 `json:"total"`
+	Average float64 `json:"average"`
+}
+
+// readDate is a function that parses a day (2006-01-02) or a moment (RFC 3339)
+func readDate(raw string) (t time.Time, err error) {
+	if t, err = time.Parse(time.DateOnly, raw); err == nil {
+		return
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// NewMaintenanceDefault is a function that returns a new instance of MaintenanceDefault
+func NewMaintenanceDefault(sp internal.MaintenanceServiceProvider) *MaintenanceDefault {
+	return &MaintenanceDefault{sp: sp}
+}
+
+// MaintenanceDefault is a struct with methods that represent handlers for the maintenance of the vehicles
+type MaintenanceDefault struct {
+	// sp is the provider of the service that will be used by each request
+	sp internal.MaintenanceServiceProvider
+}
+
+// sv is a method that returns the service of a request
+func (h *MaintenanceDefault) sv(r *http.Request) internal.MaintenanceService {
+	return h.sp.Service(r.Context())
+}
+
+// AddRecord is a method that returns a handler for the route POST /vehicles/{id}/maintenance
+func (h *MaintenanceDefault) AddRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var body MaintenanceRecordJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		record := internal.MaintenanceRecord{VehicleId: id, Odometer: body.Odometer, Type: body.Type, Cost: body.Cost, Notes: body.Notes}
+		if body.Date != "" {
+			if record.Date, err = readDate(body.Date); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid date")
+				return
+			}
+		}
+
+		// process
+		record.Id, err = h.sv(r).AddRecord(record)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidMaintenanceRecord):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		record.Type = internal.NormalizeMaintenanceType(record.Type)
+		w.Header().Set("Location", fmt.Sprintf("/vehicles/%d/maintenance", id))
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "maintenance record created successfully",
+			Data:    serializeMaintenanceRecord(record),
+		})
+	}
+}
+
+// GetRecords is a method that returns a handler for the route GET /vehicles/{id}/maintenance
+func (h *MaintenanceDefault) GetRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		records, err := h.sv(r).Records(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		data := make([]MaintenanceRecordJSON, 0, len(records))
+		for _, value := range records {
+			data = append(data, serializeMaintenanceRecord(value))
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "maintenance records found successfully",
+			Data:    data,
+		})
+	}
+}
+
+// GetDue is a method that returns a handler for the route GET /vehicles/maintenance/due?within={30d}
+// - the services overdue and due within 30 days by default, soonest first
+func (h *MaintenanceDefault) GetDue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		within := 30 * 24 * time.Hour
+		if raw := r.URL.Query().Get("within"); raw != "" {
+			var err error
+			if within, err = internal.ParseDayDuration(raw); err != nil || within < 0 {
+				response.Text(w, http.StatusBadRequest, "invalid within")
+				return
+			}
+		}
+
+		// process
+		due, err := h.sv(r).Due(within)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.JSON(w, http.StatusInternalServerError, nil)
+			return
+		}
+
+		// response
+		data := make([]MaintenanceDueJSON, 0, len(due))
+		for _, value := range due {
+			d := MaintenanceDueJSON{
+				VehicleId:    value.VehicleId,
+				Brand:        value.Brand,
+				Type:         value.Type,
+				LastDate:     value.LastDate.Format(time.DateOnly),
+				LastOdometer: value.LastOdometer,
+				Odometer:     value.Odometer,
+				DueOdometer:  value.DueOdometer,
+				DueAt:        value.DueAt,
+				Overdue:      value.Overdue,
+			}
+			if value.DueDate != nil {
+				dueDate := value.DueDate.Format(time.DateOnly)
+				d.DueDate = &dueDate
+			}
+			data = append(data, d)
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "due maintenance found successfully",
+			Data:    data,
+		})
+	}
+}
+
+// GetCosts is a method that returns a handler for the route GET /vehicles/maintenance/costs?group_by={field}&from={day}&to={day}
+// - the costs are grouped by vehicle without group_by, the days are inclusive
+func (h *MaintenanceDefault) GetCosts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var q internal.MaintenanceCostQuery
+		if raw := r.URL.Query().Get("group_by"); raw != "" && raw != "vehicle" {
+			field, err := internal.ParseVehicleField(raw)
+			if err != nil || !field.IsCategorical() {
+				response.Text(w, http.StatusBadRequest, "invalid group_by")
+				return
+			}
+			q.GroupBy = field
+		}
+		for name, value := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+			if raw := r.URL.Query().Get(name); raw != "" {
+				var err error
+				if *value, err = readDate(raw); err != nil {
+					response.Text(w, http.StatusBadRequest, "invalid "+name)
+					return
+				}
+			}
+		}
+		if len(r.URL.Query().Get("to")) == len(time.DateOnly) {
+			// a day includes all of it
+			q.To = q.To.Add(24*time.Hour - time.Nanosecond)
+		}
+
+		// process
+		costs, err := h.sv(r).Costs(q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidVehicleField):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.JSON(w, http.StatusInternalServerError, nil)
+			}
+			return
+		}
+
+		// response
+		data := make([]MaintenanceCostJSON, 0, len(costs))
+		for _, value := range costs {
+			data = append(data, MaintenanceCostJSON(value))
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "maintenance costs found successfully",
+			Data:    data,
+		})
+	}
+}
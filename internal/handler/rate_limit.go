@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"app/internal"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// NewRateLimitMiddleware is a function that returns a new instance of RateLimitMiddleware
+func NewRateLimitMiddleware(rl internal.RateLimiter) *RateLimitMiddleware {
+	return &RateLimitMiddleware{rl: rl}
+}
+
+// RateLimitMiddleware is a struct that limits the requests of each client, after the authentication but for the failed ones
+// - the clients are their principal when authenticated, their IP address otherwise
+type RateLimitMiddleware struct {
+	// rl is the limiter of the budgets, nil disables the limits
+	rl internal.RateLimiter
+}
+
+// Handler is a method that returns the middleware for a class of requests
+// - the budget is reported in the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers
+// - the requests over the budget get a 429 with Retry-After, the limiter failures let the requests through
+func (m *RateLimitMiddleware) Handler(class internal.RateClass) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if m.rl == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d, err := m.rl.Allow(clientKey(r), class)
+			if err != nil {
+				log.Println("rate limit:", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if d.Limited {
+				w.Header().Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+				w.Header().Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+				w.Header().Set("RateLimit-Reset", ceilSeconds(d.Reset))
+			}
+			if !d.Allowed {
+				w.Header().Set("Retry-After", ceilSeconds(d.RetryAfter))
+				response.Text(w, http.StatusTooManyRequests, "rate limit exceeded for "+string(class)+" requests")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// statusRecorder is a struct that captures the status code of a response while it is written
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader is a method that captures the status code
+func (rc *statusRecorder) WriteHeader(status int) {
+	rc.status = status
+	rc.ResponseWriter.WriteHeader(status)
+}
+
+// AuthFailures is a method that returns the middleware that limits the failed authentications of each IP address
+// - it goes in front of the authentication: the responses with a 401 are charged to the auth budget of the IP address,
+// whose requests get a 429 with Retry-After without being authenticated once it is spent
+func (m *RateLimitMiddleware) AuthFailures(next http.Handler) http.Handler {
+	if m.rl == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := ipKey(r)
+		d, err := m.rl.Peek(key, internal.RateClassAuth)
+		if err != nil {
+			log.Println("rate limit:", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !d.Allowed {
+			w.Header().Set("Retry-After", ceilSeconds(d.RetryAfter))
+			response.Text(w, http.StatusTooManyRequests, "rate limit exceeded for failed authentications")
+			return
+		}
+
+		rc := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rc, r)
+		if rc.status == http.StatusUnauthorized {
+			if _, err := m.rl.Allow(key, internal.RateClassAuth); err != nil {
+				log.Println("rate limit:", err)
+			}
+		}
+	})
+}
+
+// clientKey is a function that returns the key of the client of a request
+func clientKey(r *http.Request) string {
+	if p, ok := internal.PrincipalFrom(r.Context()); ok {
+		return p.Method + ":" + p.Tenant + "/" + p.Id
+	}
+	return ipKey(r)
+}
+
+// ipKey is a function that returns the key of the IP address of a request
+// - the remote address is used as is, a proxy in front must be trusted explicitly to use forwarded addresses
+func ipKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ceilSeconds is a function that formats a duration as whole seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
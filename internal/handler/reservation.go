@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// ReservationJSON is a struct that represents a reservation in JSON format
+// - from and to are moments (RFC 3339) or days (2006-01-02)
+type ReservationJSON struct {
+	ID         int        `json:"id"`
+	VehicleId  int        `json:"vehicle_id"`
+	Holder     string     `json:"holder"`
+	Purpose    string     `json:"purpose,omitempty"`
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	CanceledAt *time.Time `json:"canceled_at,omitempty"`
+	Active     bool       `json:"active"`
+}
+
+// serializeReservation is a function that converts a reservation into its JSON representation
+func serializeReservation(r internal.Reservation) ReservationJSON {
+	data := ReservationJSON{
+		ID:         r.Id,
+		VehicleId:  r.VehicleId,
+		Holder:     r.Holder,
+		Purpose:    r.Purpose,
+		From:       r.From.Format(time.RFC3339),
+		To:         r.To.Format(time.RFC3339),
+		CanceledAt: r.CanceledAt,
+		Active:     r.IsActive(),
+	}
+	if !r.CreatedAt.IsZero() {
+		data.CreatedAt = &r.CreatedAt
+	}
+	return data
+}
+
+// readPeriod is a function that parses the moments (RFC 3339) or days (2006-01-02) of a period [from, to)
+// - a day of to includes all of it
+func readPeriod(rawFrom string, rawTo string) (from time.Time, to time.Time, err error) {
+	if from, err = readDate(rawFrom); err != nil {
+		return from, to, errors.New("invalid from")
+	}
+	if to, err = readDate(rawTo); err != nil {
+		return from, to, errors.New("invalid to")
+	}
+	if len(rawTo) == len(time.DateOnly) {
+		to = to.AddDate(0, 0, 1)
+	}
+	return
+}
+
+// icsEscaper escapes the text values of an iCalendar
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// icsTime is the format of the moments of an iCalendar, in UTC
+const icsTime = "20060102T150405Z"
+
+// newCalendar is a function that returns the reservations of a vehicle as an iCalendar (RFC 5545)
+// - the canceled reservations are kept as cancelled events so the subscribed calendars remove them
+func newCalendar(tenant string, vehicleId int, rs []internal.Reservation) string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		// lines are folded at 75 octets without splitting a character
+		l := fmt.Sprintf(format, args...)
+		for limit := 75; len(l) > limit; limit = 74 {
+			cut := limit
+			for cut > 0 && l[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			b.WriteString(l[:cut] + "\r\n ")
+			l = l[cut:]
+		}
+		b.WriteString(l + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//app//vehicle reservations//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s", icsEscaper.Replace(fmt.Sprintf("Vehicle %d reservations", vehicleId)))
+	for _, r := range rs {
+		stamp, status, sequence := r.CreatedAt, "CONFIRMED", 0
+		if !r.IsActive() {
+			stamp, status, sequence = *r.CanceledAt, "CANCELLED", 1
+		}
+		summary := "Reserved for " + r.Holder
+		if r.Purpose != "" {
+			summary += ": " + r.Purpose
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:%s", icsEscaper.Replace(fmt.Sprintf("reservation-%d@%s.vehicles", r.Id, tenant)))
+		line("DTSTAMP:%s", stamp.UTC().Format(icsTime))
+		line("DTSTART:%s", r.From.UTC().Format(icsTime))
+		line("DTEND:%s", r.To.UTC().Format(icsTime))
+		line("SUMMARY:%s", icsEscaper.Replace(summary))
+		line("STATUS:%s", status)
+		line("SEQUENCE:%d", sequence)
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return b.String()
+}
+
+// NewReservationDefault is a function that returns a new instance of ReservationDefault
+func NewReservationDefault(sp internal.ReservationServiceProvider) *ReservationDefault {
+	return &ReservationDefault{sp: sp}
+}
+
+// ReservationDefault is a struct with methods that represent handlers for the reservations of the vehicles
+type ReservationDefault struct {
+	// sp is the provider of the service that will be used by each request
+	sp internal.ReservationServiceProvider
+}
+
+// sv is a method that returns the service of a request
+func (h *ReservationDefault) sv(r *http.Request) internal.ReservationService {
+	return h.sp.Service(r.Context())
+}
+
+// Reserve is a method that returns a handler for the route POST /vehicles/{id}/reservations
+// - the holder is the principal of the request when it is not given
+func (h *ReservationDefault) Reserve() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var body ReservationJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		rs := internal.Reservation{VehicleId: id, Holder: body.Holder, Purpose: body.Purpose}
+		if rs.From, rs.To, err = readPeriod(body.From, body.To); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if p, ok := internal.PrincipalFrom(r.Context()); ok && strings.TrimSpace(rs.Holder) == "" {
+			rs.Holder = p.Id
+		}
+
+		// process
+		stored, err := h.sv(r).Reserve(rs)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidReservation):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, internal.ErrReservationConflict):
+				response.Text(w, http.StatusConflict, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		w.Header().Set("Location", fmt.Sprintf("/vehicles/%d/reservations/%d", id, stored.Id))
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "reservation created successfully",
+			Data:    serializeReservation(stored),
+		})
+	}
+}
+
+// Cancel is a method that returns a handler for the route DELETE /vehicles/{id}/reservations/{reservation_id}
+func (h *ReservationDefault) Cancel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		reservationId, err := strconv.Atoi(chi.URLParam(r, "reservation_id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid reservation id")
+			return
+		}
+
+		// process
+		rs, err := h.sv(r).Cancel(id, reservationId)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidReservation):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "reservation canceled successfully",
+			Data:    serializeReservation(rs),
+		})
+	}
+}
+
+// GetVehicleReservations is a method that returns a handler for the route GET /vehicles/{id}/reservations
+func (h *ReservationDefault) GetVehicleReservations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		rs, err := h.sv(r).VehicleReservations(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		data := make([]ReservationJSON, 0, len(rs))
+		for _, value := range rs {
+			data = append(data, serializeReservation(value))
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "reservations found successfully",
+			Data:    data,
+		})
+	}
+}
+
+// GetReservation is a method that returns a handler for the route GET /vehicles/{id}/reservations/{reservation_id}
+func (h *ReservationDefault) GetReservation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		reservationId, err := strconv.Atoi(chi.URLParam(r, "reservation_id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid reservation id")
+			return
+		}
+
+		// process
+		rs, err := h.sv(r).Reservation(id, reservationId)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "reservation found successfully",
+			Data:    serializeReservation(rs),
+		})
+	}
+}
+
+// GetCalendar is a method that returns a handler for the route GET /vehicles/{id}/reservations.ics
+func (h *ReservationDefault) GetCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		// process
+		rs, err := h.sv(r).VehicleReservations(id)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		tenant, _ := internal.TenantFrom(r.Context())
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="vehicle-%d.ics"`, id))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(newCalendar(tenant, id, rs)))
+	}
+}
+
+// GetAvailable is a method that returns a handler for the route GET /vehicles/available?from={from}&to={to}
+// - plus the filters of readFilter, e.g. ?min_passengers=5&transmission=automatic&fuel_type=diesel
+// - the filters and the vehicles are in the units of ?units, metric by default
+func (h *ReservationDefault) GetAvailable() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var q internal.AvailabilityQuery
+		var err error
+		if q.From, q.To, err = readPeriod(r.URL.Query().Get("from"), r.URL.Query().Get("to")); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if q.Filter, err = readFilter(r); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		vq, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		q.Filter = vq.Units.Filter(q.Filter)
+
+		// process
+		v, err := h.sv(r).Available(q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidReservation):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.JSON(w, http.StatusInternalServerError, nil)
+			}
+			return
+		}
+
+		// response
+		data := make([]VehicleJSON, 0, len(v))
+		for _, value := range v {
+			data = append(data, serializeVehicle(vq.Units.Vehicle(value)))
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "available vehicles found successfully",
+			Data:    data,
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"app/internal"
+	"net/http"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// NewTenantMiddleware is a function that returns a new instance of TenantMiddleware
+func NewTenantMiddleware(td internal.TenantDirectory, az internal.Authorizer) *TenantMiddleware {
+	return &TenantMiddleware{td: td, az: az}
+}
+
+// TenantMiddleware is a struct that resolves the tenant of the requests, after the authentication
+type TenantMiddleware struct {
+	// td is the directory of the known tenants
+	td internal.TenantDirectory
+	// az is the access control of the principals choosing a tenant with the X-Tenant-Id header
+	az internal.Authorizer
+}
+
+// Handler is a method that returns the middleware
+// - the principals bound to a tenant can only work on it, the X-Tenant-Id header must agree if sent
+// - the other requests work on the default tenant, only the principals allowed to switch tenants choose another one
+// with the X-Tenant-Id header
+func (m *TenantMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("X-Tenant-Id")
+		p, authenticated := internal.PrincipalFrom(r.Context())
+		switch {
+		case authenticated && p.Tenant != "":
+			if tenant != "" && tenant != p.Tenant {
+				response.Text(w, http.StatusForbidden, internal.ErrTenantMismatch.Error()+": "+tenant)
+				return
+			}
+			tenant = p.Tenant
+		case tenant != "" && tenant != internal.DefaultTenant:
+			var pr *internal.Principal
+			if authenticated {
+				pr = &p
+			}
+			if err := m.az.Authorize(pr, internal.PermissionTenantsSwitch); err != nil {
+				forbidden(w, err)
+				return
+			}
+		}
+		if tenant == "" && m.td.HasTenant(internal.DefaultTenant) {
+			tenant = internal.DefaultTenant
+		}
+
+		switch {
+		case tenant == "":
+			response.Text(w, http.StatusBadRequest, internal.ErrTenantRequired.Error())
+			return
+		case !m.td.HasTenant(tenant):
+			response.Text(w, http.StatusNotFound, internal.ErrTenantNotFound.Error()+": "+tenant)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(internal.WithTenant(r.Context(), tenant)))
+	})
+}
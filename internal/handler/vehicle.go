@@ -3,7 +3,7 @@ package handler
 import (
 	"app/internal"
 	"strings"
-	//"errors"
+	"errors"
 	"strconv"
 
 	// "app/platform/web"
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	//"net/url"
 
@@ -22,9 +23,13 @@ import (
 // VehicleJSON is a struct that represents a vehicle in JSON format
 type VehicleJSON struct {
 	ID              int     `json:"id"`
+	Tenant          string  `json:"tenant,omitempty"`
+	DepotId         int     `json:"depot_id,omitempty"`
 	Brand           string  `json:"brand"`
 	Model           string  `json:"model"`
 	Registration    string  `json:"registration"`
+	Country         string  `json:"country,omitempty"`
+	VIN             string  `json:"vin,omitempty"`
 	Color           string  `json:"color"`
 	FabricationYear int     `json:"year"`
 	Capacity        int     `json:"passengers"`
@@ -35,6 +40,101 @@ type VehicleJSON struct {
 	Height          float64 `json:"height"`
 	Length          float64 `json:"length"`
 	Width           float64 `json:"width"`
+	Metrics         VehicleMetricsJSON `json:"metrics"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// VehicleMetricsJSON is a struct that represents the derived attributes of a vehicle in JSON format
+type VehicleMetricsJSON struct {
+	Volume      float64 `json:"volume"`
+	CargoVolume float64 `json:"cargo_volume"`
+	Footprint   float64 `json:"footprint"`
+	Density     float64 `json:"density"`
+	SizeClass   string  `json:"size_class,omitempty"`
+}
+
+// serializeVehicle is a function that converts a vehicle into its JSON representation
+func serializeVehicle(v internal.Vehicle) VehicleJSON {
+	return VehicleJSON{
+		ID:              v.Id,
+		Tenant:          v.Tenant,
+		DepotId:         v.DepotId,
+		Brand:           v.Brand,
+		Model:           v.Model,
+		Registration:    v.Registration,
+		Country:         v.Country,
+		VIN:             v.VIN,
+		Color:           v.Color,
+		FabricationYear: v.FabricationYear,
+		Capacity:        v.Capacity,
+		MaxSpeed:        v.MaxSpeed,
+		FuelType:        v.FuelType,
+		Transmission:    v.Transmission,
+		Weight:          v.Weight,
+		Height:          v.Dimensions.Height,
+		Length:          v.Dimensions.Length,
+		Width:           v.Dimensions.Width,
+		Metrics: VehicleMetricsJSON{
+			Volume:      v.Metrics.Volume,
+			CargoVolume: v.Metrics.CargoVolume,
+			Footprint:   v.Metrics.Footprint,
+			Density:     v.Metrics.Density,
+			SizeClass:   v.Metrics.SizeClass,
+		},
+		DeletedAt:       v.DeletedAt,
+	}
+}
+
+// deserializeVehicle is a function that converts the JSON representation of a vehicle into a vehicle
+func deserializeVehicle(v VehicleJSON) internal.Vehicle {
+	return internal.Vehicle{
+		Id: v.ID,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           v.Brand,
+			Model:           v.Model,
+			Registration:    v.Registration,
+			Country:         v.Country,
+			VIN:             v.VIN,
+			Color:           v.Color,
+			FabricationYear: v.FabricationYear,
+			Capacity:        v.Capacity,
+			MaxSpeed:        v.MaxSpeed,
+			FuelType:        v.FuelType,
+			Transmission:    v.Transmission,
+			Weight:          v.Weight,
+			Dimensions: internal.Dimensions{
+				Height: v.Height,
+				Length: v.Length,
+				Width:  v.Width,
+			},
+		},
+	}
+}
+
+// readQuery is a function that builds the read query from the query string of the request
+func readQuery(r *http.Request) (q internal.VehicleQuery, err error) {
+	if raw := r.URL.Query().Get("include_deleted"); raw != "" {
+		q.IncludeDeleted, err = strconv.ParseBool(raw)
+		if err != nil {
+			err = fmt.Errorf("invalid include_deleted")
+			return
+		}
+	}
+
+	if raw := r.URL.Query().Get("as_of"); raw != "" {
+		q.AsOf, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			err = fmt.Errorf("invalid as_of")
+			return
+		}
+	}
+
+	if q.Units, err = internal.ParseUnitSystem(r.URL.Query().Get("units")); err != nil {
+		err = fmt.Errorf("invalid units")
+		return
+	}
+
+	return
 }
 
 type Message struct{
@@ -43,49 +143,102 @@ type Message struct{
 }
 
 // NewVehicleDefault is a function that returns a new instance of VehicleDefault
-func NewVehicleDefault(sv internal.VehicleService) *VehicleDefault {
-	return &VehicleDefault{sv: sv}
+func NewVehicleDefault(sp internal.VehicleServiceProvider) *VehicleDefault {
+	return &VehicleDefault{sp: sp}
 }
 
 // VehicleDefault is a struct with methods that represent handlers for vehicles
 type VehicleDefault struct {
-	// sv is the service that will be used by the handler
-	sv internal.VehicleService
+	// sp is the provider of the service that will be used by each request
+	sp internal.VehicleServiceProvider
+}
+
+// sv is a method that returns the service of a request
+func (h *VehicleDefault) sv(r *http.Request) internal.VehicleService {
+	return h.sp.Service(r.Context())
+}
+
+// readSort is a function that parses a comma separated list of fields, descending when prefixed by -
+func readSort(raw string) (keys []internal.SortKey, err error) {
+	if raw == "" {
+		return
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		var key internal.SortKey
+		name, key.Desc = strings.CutPrefix(strings.TrimSpace(name), "-")
+		if key.Field, err = internal.ParseVehicleField(name); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return
 }
 
-// GetAll is a method that returns a handler for the route GET /vehicles
+// GetAll is a method that returns a handler for the route GET /vehicles?{filters}&sort={field,-field}&offset={n}&limit={n}
+// - the vehicles are a map by id, or an ordered list with the total when sorted or paged
 func (h *VehicleDefault) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// request
-		// ...
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var l internal.VehicleListQuery
+		if l.Filter, err = readFilter(r); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if l.Sort, err = readSort(r.URL.Query().Get("sort")); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for name, value := range map[string]*int{"offset": &l.Offset, "limit": &l.Limit} {
+			if raw := r.URL.Query().Get(name); raw != "" {
+				if *value, err = strconv.Atoi(raw); err != nil {
+					response.Text(w, http.StatusBadRequest, "invalid "+name)
+					return
+				}
+			}
+		}
+		ordered := r.URL.Query().Has("sort") || r.URL.Query().Has("offset") || r.URL.Query().Has("limit")
 
 		// process
-		// - get all vehicles
-		v, err := h.sv.FindAll()
+		// - get the vehicles
+		v, total, err := h.sv(r).List(l, q)
 		if err != nil {
-			response.JSON(w, http.StatusInternalServerError, nil)
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidSort), errors.Is(err, internal.ErrInvalidVehicleField):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.JSON(w, http.StatusInternalServerError, nil)
+			}
 			return
 		}
 
 		// response
-		data := make(map[int]VehicleJSON)
-		for key, value := range v {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
+		if ordered {
+			data := make([]VehicleJSON, 0, len(v))
+			for _, value := range v {
+				data = append(data, serializeVehicle(value))
 			}
+			response.JSON(w, http.StatusOK, map[string]any{
+				"message": "success",
+				"data":    data,
+				"total":   total,
+			})
+			return
+		}
+
+		data := make(map[int]VehicleJSON)
+		for _, value := range v {
+			data[value.Id] = serializeVehicle(value)
 		}
 		response.JSON(w, http.StatusOK, map[string]any{
 			"message": "success",
@@ -94,6 +247,185 @@ func (h *VehicleDefault) GetAll() http.HandlerFunc {
 	}
 }
 
+// GetById is a method that returns a handler for the route GET /vehicles/{id}
+func (h *VehicleDefault) GetById() http.HandlerFunc{
+	return func(w http.ResponseWriter, r *http.Request){
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).FindById(id, q)
+
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			response.Text(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "vehicle found successfully",
+			Data:    serializeVehicle(v),
+		})
+
+	}
+}
+
+// GetByRegistration is a method that returns a handler for the route GET /vehicles/registration/{registration}
+func (h *VehicleDefault) GetByRegistration() http.HandlerFunc{
+	return func(w http.ResponseWriter, r *http.Request){
+
+		registration := chi.URLParam(r, "registration")
+
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).FindByRegistration(registration, q)
+
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+				case errors.Is(err, internal.ErrorRegistrationAmbiguous):
+					response.Text(w, http.StatusConflict, err.Error())
+				default:
+					response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "vehicle found successfully",
+			Data:    serializeVehicle(v),
+		})
+
+	}
+}
+
+// VINInfoJSON is a struct that represents the information encoded in a VIN in JSON format
+type VINInfoJSON struct {
+	WMI          string   `json:"wmi"`
+	Manufacturer string   `json:"manufacturer,omitempty"`
+	Country      string   `json:"country,omitempty"`
+	Brands       []string `json:"brands,omitempty"`
+	ModelYear    int      `json:"model_year,omitempty"`
+	Plant        string   `json:"plant,omitempty"`
+	Serial       string   `json:"serial,omitempty"`
+}
+
+// GetByVIN is a method that returns a handler for the route GET /vehicles/vin/{vin}
+func (h *VehicleDefault) GetByVIN() http.HandlerFunc{
+	return func(w http.ResponseWriter, r *http.Request){
+
+		vin := chi.URLParam(r, "vin")
+
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, info, err := h.sv(r).FindByVIN(vin, q)
+
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+				case errors.Is(err, internal.ErrorVINAmbiguous):
+					response.Text(w, http.StatusConflict, err.Error())
+				default:
+					response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "vehicle found successfully",
+			Data: map[string]any{
+				"vehicle": serializeVehicle(v),
+				"vin": VINInfoJSON{
+					WMI:          info.WMI,
+					Manufacturer: info.Manufacturer,
+					Country:      info.Country,
+					Brands:       info.Brands,
+					ModelYear:    info.ModelYear,
+					Plant:        info.Plant,
+					Serial:       info.Serial,
+				},
+			},
+		})
+
+	}
+}
+
+// RegistrationCheckJSON is a struct that represents the result of a registration check in JSON format
+type RegistrationCheckJSON struct {
+	Valid        bool   `json:"valid"`
+	Country      string `json:"country,omitempty"`
+	Registration string `json:"registration"`
+	Format       string `json:"format,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// CheckRegistration is a method that returns a handler for the route POST /vehicles/registration/validate
+func (h *VehicleDefault) CheckRegistration() http.HandlerFunc{
+	return func(w http.ResponseWriter, r *http.Request){
+
+		var body struct {
+			Country      string `json:"country"`
+			Registration string `json:"registration"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		normalized, format, err := h.sv(r).CheckRegistration(body.Country, body.Registration)
+
+		switch {
+			case err == nil:
+			case forbidden(w, err):
+				return
+			case errors.Is(err, internal.ErrInvalidRegistration), errors.Is(err, internal.ErrInvalidRegistrationFormat):
+				response.JSON(w, http.StatusOK, &Message{
+					Message: "registration is not valid",
+					Data:    RegistrationCheckJSON{Country: body.Country, Registration: normalized, Reason: err.Error()},
+				})
+				return
+			default:
+				response.Text(w, http.StatusBadRequest, err.Error())
+				return
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "registration is valid",
+			Data:    RegistrationCheckJSON{Valid: true, Country: body.Country, Registration: normalized, Format: format},
+		})
+
+	}
+}
+
+// Add is a method that returns a handler for the route POST /vehicles
 func (h *VehicleDefault) Add() http.HandlerFunc{
 	return func(w http.ResponseWriter, r *http.Request) {
 		bytes, err := io.ReadAll(r.Body)
@@ -103,6 +435,11 @@ func (h *VehicleDefault) Add() http.HandlerFunc{
 			
 			return
 		}
+
+		if bytes, err = canonicalizeBody(bytes); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		
 		var bodyMap map[string]any
 		
@@ -126,63 +463,44 @@ func (h *VehicleDefault) Add() http.HandlerFunc{
 			return
 		}
 
-		vehicle := internal.Vehicle{
-			Id : body.ID,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           body.Brand,
-				Model:           body.Model,
-				Registration:    body.Registration,
-				Color:           body.Color,
-				FabricationYear: body.FabricationYear,
-				Capacity:        body.Capacity,
-				MaxSpeed:        body.MaxSpeed,
-				FuelType:        body.FuelType,
-				Transmission:    body.Transmission,
-				Weight:          body.Weight,
-				Dimensions: internal.Dimensions{
-					Height: body.Height,
-					Length: body.Length,
-					Width:  body.Width,
-				},
-			},
-		}
+		vehicle := deserializeVehicle(body)
 
-		if err := h.sv.Add(vehicle); err != nil {
+		stored, err := h.sv(r).Add(vehicle)
 
-			response.Text(w, http.StatusConflict, err.Error())
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+
+			switch {
+				case errors.Is(err, internal.ErrInvalidRegistration),
+					errors.Is(err, internal.ErrInvalidRegistrationFormat), errors.Is(err, internal.ErrUnknownRegistrationCountry),
+					errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch), errors.Is(err, internal.ErrInvalidVehicleId):
+					response.Text(w, http.StatusBadRequest, err.Error())
+				default:
+					response.Text(w, http.StatusConflict, err.Error())
+			}
 
 			return
 
 		}
 
-		data := VehicleJSON{
-			ID:              vehicle.Id,
-			Brand:           vehicle.Brand,
-			Model:           vehicle.Model,
-			Registration:    vehicle.Registration,
-			Color:           vehicle.Color,
-			FabricationYear: vehicle.FabricationYear,
-			Capacity:        vehicle.Capacity,
-			MaxSpeed:        vehicle.MaxSpeed,
-			FuelType:        vehicle.FuelType,
-			Transmission:    vehicle.Transmission,
-			Weight:          vehicle.Weight,
-			Height:          vehicle.Dimensions.Height,
-			Length:          vehicle.Dimensions.Length,
-			Width:           vehicle.Dimensions.Width,
-		}
+		data := serializeVehicle(stored)
 
-		response.JSON(w, http.StatusOK, &Message{
-			Message: "movie created successfully",
+		w.Header().Set("Location", fmt.Sprintf("/vehicles/%d", stored.Id))
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "vehicle created successfully",
 			Data:    data,
 		})
 
 	}
 }
 
+// ValidateKeyExistance is a function that checks the body has every attribute of a vehicle
+// - the id is optional, the service allocates it when missing
 func ValidateKeyExistance(body map[string]any) error {
 
-	keys := []string{"id", "brand", "model", "registration", "color", "year", "passengers", "max_speed", "fuel_type", "transmission", "weight", "height", "width"}
+	keys := []string{"brand", "model", "registration", "color", "year", "passengers", "max_speed", "fuel_type", "transmission", "weight", "height", "width"}
 
 	for _, key := range keys {
 
@@ -206,9 +524,19 @@ func (h *VehicleDefault) SearchByColorAndYear() http.HandlerFunc{
 			return
 		}
 
-		v, err := h.sv.SearchByColorAndYear(color, year)
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).SearchByColorAndYear(color, year, q)
 
 		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusConflict, err.Error())
 			return
 		}
@@ -217,22 +545,7 @@ func (h *VehicleDefault) SearchByColorAndYear() http.HandlerFunc{
 
 		for _, value := range v{
 
-			vehicles = append(vehicles, VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Dimensions.Height,
-				Length:          value.Dimensions.Length,
-				Width:           value.Dimensions.Width,
-			})
+			vehicles = append(vehicles, serializeVehicle(value))
 			
 		}
 
@@ -256,9 +569,19 @@ func (h *VehicleDefault) SearchByBrand() http.HandlerFunc{
 			return
 		}
 
-		v, err := h.sv.SearchByBrand(brand, start, end)
+		q, err := readQuery(r)
 
 		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).SearchByBrand(brand, start, end, q)
+
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -266,22 +589,7 @@ func (h *VehicleDefault) SearchByBrand() http.HandlerFunc{
 		vehicles := []VehicleJSON{}
 
 		for _, value := range v{
-			vehicles = append(vehicles, VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Dimensions.Height,
-				Length:          value.Dimensions.Length,
-				Width:           value.Dimensions.Width,
-			})
+			vehicles = append(vehicles, serializeVehicle(value))
 		}
 
 		response.JSON(w, http.StatusOK, &Message{
@@ -298,9 +606,19 @@ func (h *VehicleDefault) GetAverageSpeedByBrand() http.HandlerFunc{
 
 		brand := chi.URLParam(r, "brand")
 
-		speed, err := h.sv.GetAverageSpeedByBrand(brand)
+		q, err := readQuery(r)
 
 		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		speed, err := h.sv(r).GetAverageSpeedByBrand(brand, q)
+
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -323,6 +641,11 @@ func (h *VehicleDefault) AddMultiple() http.HandlerFunc{
 			response.Text(w, http.StatusBadRequest, "invalid request body // read")
 			return
 		}
+
+		if bytes, err = canonicalizeBody(bytes); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		
 		var body []VehicleJSON
 
@@ -350,38 +673,35 @@ func (h *VehicleDefault) AddMultiple() http.HandlerFunc{
 
 		for _, value := range body{
 
-			vehicles = append(vehicles, internal.Vehicle{
-				Id:              value.ID,
-				VehicleAttributes: internal.VehicleAttributes{
-					Brand:           value.Brand,
-					Model:           value.Model,
-					Registration:    value.Registration,
-					Color:           value.Color,
-					FabricationYear: value.FabricationYear,
-					Capacity:        value.Capacity,
-					MaxSpeed:        value.MaxSpeed,
-					FuelType:        value.FuelType,
-					Transmission:    value.Transmission,
-					Weight:          value.Weight,
-					Dimensions: internal.Dimensions{
-						Height: value.Height,
-						Length: value.Length,
-						Width:  value.Width,
-					},
-				},
-			})
+			vehicles = append(vehicles, deserializeVehicle(value))
 
 		}
 
-		err = h.sv.AddMultiple(vehicles)
+		ids, err := h.sv(r).AddMultiple(vehicles)
 
 		if err != nil {
-			response.Text(w, http.StatusInternalServerError, err.Error())
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+				case errors.Is(err, internal.ErrInvalidRegistration),
+					errors.Is(err, internal.ErrInvalidRegistrationFormat), errors.Is(err, internal.ErrUnknownRegistrationCountry),
+					errors.Is(err, internal.ErrInvalidVIN), errors.Is(err, internal.ErrVINMismatch), errors.Is(err, internal.ErrInvalidVehicleId):
+					response.Text(w, http.StatusBadRequest, err.Error())
+				case errors.Is(err, internal.ErrorVehicleAlreadyExists), errors.Is(err, internal.ErrorRegistrationAlreadyExists),
+					errors.Is(err, internal.ErrorVINAlreadyExists):
+					response.Text(w, http.StatusConflict, err.Error())
+				default:
+					response.Text(w, http.StatusInternalServerError, err.Error())
+			}
 			return
 		}
 
 
-		response.Text(w, http.StatusCreated, "vehicles added successfully")
+		response.JSON(w, http.StatusCreated, &Message{
+			Message: "vehicles added successfully",
+			Data:    ids,
+		})
 
 	}
 }
@@ -401,6 +721,11 @@ func (h *VehicleDefault) UpdateMaxSpeedById() http.HandlerFunc{
 			response.Text(w, http.StatusBadRequest, "invalid body")
 			return
 		}
+
+		if bytes, err = canonicalizeBody(bytes); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		
 		var body map[string]float64
 
@@ -421,7 +746,10 @@ func (h *VehicleDefault) UpdateMaxSpeedById() http.HandlerFunc{
 			return
 		}
 		
-		if err := h.sv.UpdateMaxSpeedById(id, speed); err != nil{
+		if err := h.sv(r).UpdateMaxSpeedById(id, speed); err != nil{
+			if forbidden(w, err) {
+				return
+			}
 			switch err{
 				case internal.ErrInvalidSpeed:
 					response.Text(w, http.StatusBadRequest, err.Error())
@@ -442,9 +770,19 @@ func (h *VehicleDefault) GetVehiclesByFuelType() http.HandlerFunc{
 
 		fuel := chi.URLParam(r, "fuel_type")
 
-		v, err := h.sv.GetVehiclesByFuelType(fuel)
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).GetVehiclesByFuelType(fuel, q)
 
 		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -452,22 +790,7 @@ func (h *VehicleDefault) GetVehiclesByFuelType() http.HandlerFunc{
 		vehicles := []VehicleJSON{}
 
 		for _, value := range v{
-			vehicles = append(vehicles, VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			})
+			vehicles = append(vehicles, serializeVehicle(value))
 		}
 
 		response.JSON(w, http.StatusOK, &Message{
@@ -488,7 +811,10 @@ func (h *VehicleDefault) DeleteById() http.HandlerFunc{
 			return
 		}
 
-		if err := h.sv.DeleteById(id); err != nil {
+		if err := h.sv(r).DeleteById(id); err != nil {
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -498,14 +824,54 @@ func (h *VehicleDefault) DeleteById() http.HandlerFunc{
 	}
 }
 
+// RestoreById is a method that returns a handler for the route POST /vehicles/{id}/restore
+func (h *VehicleDefault) RestoreById() http.HandlerFunc{
+	return func(w http.ResponseWriter, r *http.Request){
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		if err := h.sv(r).RestoreById(id); err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+				case errors.Is(err, internal.ErrorVehicleNotDeleted):
+					response.Text(w, http.StatusConflict, err.Error())
+					return
+				default:
+					response.Text(w, http.StatusNotFound, err.Error())
+					return
+			}
+		}
+
+		response.Text(w, http.StatusOK, "vehicle restored successfully")
+
+	}
+}
+
 func (h *VehicleDefault) GetVehiclesByTransmission() http.HandlerFunc{
 	return func(w http.ResponseWriter, r *http.Request){
 
 		transmission := chi.URLParam(r, "type")
 
-		v, err := h.sv.GetVehiclesByTransmission(transmission)
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).GetVehiclesByTransmission(transmission, q)
 
 		if err != nil{
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -513,22 +879,7 @@ func (h *VehicleDefault) GetVehiclesByTransmission() http.HandlerFunc{
 		vehicles := []VehicleJSON{}
 
 		for _, value := range v{
-			vehicles = append(vehicles, VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			})
+			vehicles = append(vehicles, serializeVehicle(value))
 		}
 
 		response.JSON(w, http.StatusOK, &Message{
@@ -569,7 +920,10 @@ func (h *VehicleDefault) UpdateFuelTypeById() http.HandlerFunc{
 
 		fuel := body["fuel_type"]
 
-		if err := h.sv.UpdateFuelTypeById(id, fuel); err != nil{
+		if err := h.sv(r).UpdateFuelTypeById(id, fuel); err != nil{
+			if forbidden(w, err) {
+				return
+			}
 
 			switch err{
 				case internal.ErrInvalidFuelType:
@@ -587,14 +941,80 @@ func (h *VehicleDefault) UpdateFuelTypeById() http.HandlerFunc{
 	}
 }
 
+// UpdateRegistrationById is a method that returns a handler for the route PUT /vehicles/{id}/update_registration
+func (h *VehicleDefault) UpdateRegistrationById() http.HandlerFunc{
+	return func(w http.ResponseWriter, r *http.Request){
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+		if err != nil{
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		bytes, err := io.ReadAll(r.Body)
+
+		if err != nil{
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		var body map[string]string
+
+		if err := json.Unmarshal(bytes, &body); err != nil{
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		registration, ok := body["registration"]
+
+		if !ok{
+			response.Text(w, http.StatusBadRequest, "missing registration")
+			return
+		}
+
+		if err := h.sv(r).UpdateRegistrationById(id, registration); err != nil{
+			if forbidden(w, err) {
+				return
+			}
+
+			switch{
+				case errors.Is(err, internal.ErrInvalidRegistration), errors.Is(err, internal.ErrInvalidRegistrationFormat),
+					errors.Is(err, internal.ErrUnknownRegistrationCountry):
+					response.Text(w, http.StatusBadRequest, err.Error())
+					return
+				case errors.Is(err, internal.ErrorRegistrationAlreadyExists):
+					response.Text(w, http.StatusConflict, err.Error())
+					return
+				default:
+					response.Text(w, http.StatusNotFound, err.Error())
+					return
+			}
+
+		}
+
+		response.Text(w, http.StatusOK, "registration updated successfully")
+
+	}
+}
+
 func (h *VehicleDefault) GetAverageCapacityByBrand() http.HandlerFunc{
 	return func (w http.ResponseWriter, r *http.Request){
 
 		brand := chi.URLParam(r, "brand")
 
-		capacity, err := h.sv.GetAverageCapacityByBrand(brand)
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		capacity, err := h.sv(r).GetAverageCapacityByBrand(brand, q)
 
 		if err != nil{
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -660,9 +1080,19 @@ func (h *VehicleDefault) GetVehiclesByDimensions() http.HandlerFunc{
 			return
 		}
 
-		v, err := h.sv.GetVehiclesByDimensions(minLF, maxLF, minWF, maxWF)
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).GetVehiclesByDimensions(minLF, maxLF, minWF, maxWF, q)
 
 		if err != nil{
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -696,9 +1126,19 @@ func (h *VehicleDefault) GetVehiclesByWeight() http.HandlerFunc{
 		println(maxWF)
 		
 
-		v, err := h.sv.GetVehiclesByWeight(minWF, maxWF)
+		q, err := readQuery(r)
+
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		v, err := h.sv(r).GetVehiclesByWeight(minWF, maxWF, q)
 
 		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
 			response.Text(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -707,22 +1147,7 @@ func (h *VehicleDefault) GetVehiclesByWeight() http.HandlerFunc{
 
 		for _, value := range v {
 
-			vehicles = append(vehicles, VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			})
+			vehicles = append(vehicles, serializeVehicle(value))
 		}
 
 		response.JSON(w, http.StatusOK, &Message{
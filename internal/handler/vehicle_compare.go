@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// ComparisonRowJSON is a struct that represents an attribute of the compared vehicles in JSON format
+type ComparisonRowJSON struct {
+	Name        string     `json:"name"`
+	Derived     bool       `json:"derived,omitempty"`
+	Unit        string     `json:"unit,omitempty"`
+	Values      []any      `json:"values"`
+	Preference  string     `json:"preference,omitempty"`
+	Best        []int      `json:"best,omitempty"`
+	Worst       []int      `json:"worst,omitempty"`
+	Differences []*float64 `json:"differences_pct,omitempty"`
+}
+
+// VehicleComparisonJSON is a struct that represents a side-by-side comparison of vehicles in JSON format
+type VehicleComparisonJSON struct {
+	Vehicles []VehicleJSON       `json:"vehicles"`
+	Rows     []ComparisonRowJSON `json:"rows"`
+}
+
+// comparisonCell is a cell of a rendered comparison table
+type comparisonCell struct {
+	Text  string
+	Best  bool
+	Worst bool
+}
+
+// comparisonTable is a rendered comparison table
+type comparisonTable struct {
+	Header []string
+	Rows   [][]comparisonCell
+}
+
+// newComparisonTable is a function that lays out a comparison as a table, one column per vehicle
+// - numeric cells are rounded to 2 decimals
+// - numeric cells of the vehicles after the first one carry the percentage difference with it
+func newComparisonTable(c internal.VehicleComparison) (t comparisonTable) {
+	t.Header = []string{"attribute"}
+	for _, v := range c.Vehicles {
+		t.Header = append(t.Header, fmt.Sprintf("#%d %s %s", v.Id, v.Brand, v.Model))
+	}
+
+	for _, row := range c.Rows {
+		name := row.Name
+		if row.Unit != "" {
+			name += " (" + row.Unit + ")"
+		}
+		if row.Derived {
+			name += " (derived)"
+		}
+		cells := []comparisonCell{{Text: name}}
+
+		for i, v := range c.Vehicles {
+			if !row.Numeric {
+				cells = append(cells, comparisonCell{Text: row.Values[i]})
+				continue
+			}
+
+			text := strconv.FormatFloat(math.Round(row.Numbers[i]*100)/100, 'f', -1, 64)
+			if i > 0 && row.Differences[i] != nil {
+				text += fmt.Sprintf(" (%+.2f%%)", *row.Differences[i])
+			}
+			cells = append(cells, comparisonCell{
+				Text:  text,
+				Best:  containsId(row.Best, v.Id),
+				Worst: containsId(row.Worst, v.Id),
+			})
+		}
+		t.Rows = append(t.Rows, cells)
+	}
+
+	return
+}
+
+// containsId is a function that reports if an id is in a list
+func containsId(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// markdown is a method that renders the table in Markdown, the best values in bold and the worst in italics
+func (t comparisonTable) markdown() string {
+	var sb strings.Builder
+	escape := strings.NewReplacer("|", `\|`)
+
+	sb.WriteString("|")
+	for _, header := range t.Header {
+		sb.WriteString(" " + escape.Replace(header) + " |")
+	}
+	sb.WriteString("\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(t.Header)) + "\n")
+	for _, row := range t.Rows {
+		sb.WriteString("|")
+		for _, cell := range row {
+			text := escape.Replace(cell.Text)
+			switch {
+			case cell.Best:
+				text = "**" + text + "**"
+			case cell.Worst:
+				text = "_" + text + "_"
+			}
+			sb.WriteString(" " + text + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// comparisonHTML is the template of the comparison table in HTML, ready to be pasted in a report
+var comparisonHTML = template.Must(template.New("comparison").Parse(`<table class="vehicle-comparison">
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range .}}<td{{if .Best}} class="best" style="font-weight:bold;color:#1a7f37"{{else if .Worst}} class="worst" style="color:#cf222e"{{end}}>{{.Text}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+`))
+
+// Compare is a method that returns a handler for the route GET /vehicles/compare?ids={id,...}&format={json|markdown|html}
+func (h *VehicleDefault) Compare() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var ids []int
+		for _, raw := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid ids")
+				return
+			}
+			ids = append(ids, id)
+		}
+
+		format := r.URL.Query().Get("format")
+		switch format {
+		case "", "json", "markdown", "html":
+		default:
+			response.Text(w, http.StatusBadRequest, "invalid format")
+			return
+		}
+
+		// process
+		c, err := h.sv(r).Compare(ids, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidComparison):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		switch format {
+		case "markdown":
+			w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(newComparisonTable(c).markdown()))
+			return
+		case "html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			comparisonHTML.Execute(w, newComparisonTable(c))
+			return
+		}
+
+		data := VehicleComparisonJSON{Vehicles: make([]VehicleJSON, 0, len(c.Vehicles))}
+		for _, v := range c.Vehicles {
+			data.Vehicles = append(data.Vehicles, serializeVehicle(v))
+		}
+		for _, row := range c.Rows {
+			rowJSON := ComparisonRowJSON{
+				Name:        row.Name,
+				Derived:     row.Derived,
+				Unit:        row.Unit,
+				Preference:  string(row.Preference),
+				Best:        row.Best,
+				Worst:       row.Worst,
+				Differences: row.Differences,
+			}
+			if row.Numeric {
+				for _, n := range row.Numbers {
+					rowJSON.Values = append(rowJSON.Values, n)
+				}
+			} else {
+				for _, value := range row.Values {
+					rowJSON.Values = append(rowJSON.Values, value)
+				}
+			}
+			data.Rows = append(data.Rows, rowJSON)
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "vehicles compared successfully",
+			Data:    data,
+		})
+	}
+}
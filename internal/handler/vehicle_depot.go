@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// UpdateDepotByIdJSON is a struct that represents the body of the route PUT /vehicles/{id}/update_depot
+type UpdateDepotByIdJSON struct {
+	// DepotId is the id of the new depot of the vehicle, 0 takes it out of any depot
+	DepotId *int `json:"depot_id"`
+}
+
+// UpdateDepotById is a method that returns a handler for the route PUT /vehicles/{id}/update_depot
+func (h *VehicleDefault) UpdateDepotById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var body UpdateDepotByIdJSON
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if body.DepotId == nil {
+			response.Text(w, http.StatusBadRequest, "missing depot_id")
+			return
+		}
+
+		// process
+		if err := h.sv(r).UpdateDepotById(id, *body.DepotId); err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrDepotNotFound):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.Text(w, http.StatusOK, "depot updated successfully")
+	}
+}
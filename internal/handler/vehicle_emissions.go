@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// VehicleEmissionsJSON is a struct that represents the estimated emissions of a vehicle in JSON format
+type VehicleEmissionsJSON struct {
+	Vehicle             VehicleJSON `json:"vehicle"`
+	Unit                string      `json:"unit"`
+	ConsumptionPer100Km float64     `json:"consumption_per_100km"`
+	KmPerYear           float64     `json:"km_per_year"`
+	FuelPerYear         float64     `json:"fuel_per_year"`
+	CO2PerKm            float64     `json:"co2_g_per_km"`
+	CO2PerYear          float64     `json:"co2_kg_per_year"`
+	CostPerKm           float64     `json:"cost_per_km"`
+	CostPerYear         float64     `json:"cost_per_year"`
+	Currency            string      `json:"currency"`
+}
+
+// EmissionsGroupJSON is a struct that represents the emissions of a group of vehicles in JSON format
+type EmissionsGroupJSON struct {
+	Group        map[internal.VehicleField]string `json:"group"`
+	Count        int                              `json:"count"`
+	CO2PerYear   float64                          `json:"co2_kg_per_year"`
+	MeanCO2PerKm float64                          `json:"mean_co2_g_per_km"`
+	CostPerYear  float64                          `json:"cost_per_year"`
+}
+
+// readKmPerYear is a function that parses the distance driven every year, 0 when not set
+func readKmPerYear(r *http.Request) (kmPerYear float64, err error) {
+	if raw := r.URL.Query().Get("km_per_year"); raw != "" {
+		if kmPerYear, err = strconv.ParseFloat(raw, 64); err != nil {
+			return 0, errors.New("invalid km_per_year")
+		}
+	}
+	return
+}
+
+// GetEmissions is a method that returns a handler for the route GET /vehicles/{id}/emissions?km_per_year={km}
+func (h *VehicleDefault) GetEmissions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		kmPerYear, err := readKmPerYear(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		e, err := h.sv(r).GetEmissions(id, kmPerYear, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidEmissionsQuery):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, internal.ErrNoEmissionFactor):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "emissions estimated successfully",
+			Data: VehicleEmissionsJSON{
+				Vehicle:             serializeVehicle(e.Vehicle),
+				Unit:                e.Unit,
+				ConsumptionPer100Km: e.ConsumptionPer100Km,
+				KmPerYear:           e.KmPerYear,
+				FuelPerYear:         e.FuelPerYear,
+				CO2PerKm:            e.CO2PerKm,
+				CO2PerYear:          e.CO2PerYear,
+				CostPerKm:           e.CostPerKm,
+				CostPerYear:         e.CostPerYear,
+				Currency:            e.Currency,
+			},
+		})
+	}
+}
+
+// GetEmissionsReport is a method that returns a handler for the route
+// GET /vehicles/emissions?km_per_year={km}&group_by=brand,fuel_type plus the filters of readFilter
+func (h *VehicleDefault) GetEmissionsReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var rq internal.EmissionsReportQuery
+		if rq.KmPerYear, err = readKmPerYear(r); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if rq.GroupBy, err = readFields(r.URL.Query().Get("group_by")); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if rq.Filter, err = readFilter(r); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		groups, skipped, err := h.sv(r).GetEmissionsReport(rq, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidEmissionsQuery), errors.Is(err, internal.ErrInvalidVehicleField):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, internal.ErrNoEmissionFactor):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]EmissionsGroupJSON, 0, len(groups))
+		for _, g := range groups {
+			data = append(data, EmissionsGroupJSON{
+				Group:        g.Group,
+				Count:        g.Count,
+				CO2PerYear:   g.CO2PerYear,
+				MeanCO2PerKm: g.MeanCO2PerKm,
+				CostPerYear:  g.CostPerYear,
+			})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "emissions report computed successfully",
+			Data: map[string]any{
+				"groups":  data,
+				"skipped": skipped,
+			},
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// NormalizationChangeJSON is a struct that represents a value changed by a normalization in JSON format
+type NormalizationChangeJSON struct {
+	Id    int    `json:"id"`
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// NormalizationReportJSON is a struct that represents the report of a normalization in JSON format
+type NormalizationReportJSON struct {
+	DryRun  bool                      `json:"dry_run"`
+	Count   int                       `json:"count"`
+	Changes []NormalizationChangeJSON `json:"changes"`
+}
+
+// NormalizeAll is a method that returns a handler for the route POST /vehicles/normalize?dry_run={bool}
+func (h *VehicleDefault) NormalizeAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var dryRun bool
+		if raw := r.URL.Query().Get("dry_run"); raw != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(raw); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid dry_run")
+				return
+			}
+		}
+
+		// process
+		changes, err := h.sv(r).NormalizeAll(dryRun)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrorRegistrationAlreadyExists), errors.Is(err, internal.ErrorVINAlreadyExists):
+				response.Text(w, http.StatusConflict, err.Error())
+			default:
+				response.Text(w, http.StatusInternalServerError, "internal server error")
+			}
+			return
+		}
+
+		// response
+		data := NormalizationReportJSON{DryRun: dryRun, Count: len(changes), Changes: make([]NormalizationChangeJSON, 0, len(changes))}
+		for _, ch := range changes {
+			data.Changes = append(data.Changes, NormalizationChangeJSON{Id: ch.Id, Field: string(ch.Field), From: ch.From, To: ch.To})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "vehicles normalized successfully",
+			Data:    data,
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// QuoteLineJSON is a struct that represents an item of a quote in JSON format, the discounts are negative amounts
+type QuoteLineJSON struct {
+	Kind        string  `json:"kind"`
+	Description string  `json:"description"`
+	Days        int     `json:"days"`
+	Daily       float64 `json:"daily,omitempty"`
+	Percent     float64 `json:"percent,omitempty"`
+	Amount      float64 `json:"amount"`
+}
+
+// VehicleQuoteJSON is a struct that represents the price of the rental of a vehicle in JSON format
+type VehicleQuoteJSON struct {
+	Vehicle  VehicleJSON     `json:"vehicle"`
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Days     int             `json:"days"`
+	Daily    float64         `json:"daily"`
+	Lines    []QuoteLineJSON `json:"lines"`
+	Subtotal float64         `json:"subtotal"`
+	Discount float64         `json:"discount"`
+	Total    float64         `json:"total"`
+	Currency string          `json:"currency"`
+}
+
+// GetQuote is a method that returns a handler for the route GET /vehicles/{id}/quote?from={from}&to={to}
+// - from and to are moments (RFC 3339) or days (2006-01-02), a day of to includes all of it
+func (h *VehicleDefault) GetQuote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		from, to, err := readPeriod(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		qt, err := h.sv(r).GetQuote(id, from, to, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidQuoteQuery):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			case errors.Is(err, internal.ErrNoBaseRate):
+				response.Text(w, http.StatusUnprocessableEntity, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		lines := make([]QuoteLineJSON, 0, len(qt.Lines))
+		for _, l := range qt.Lines {
+			lines = append(lines, QuoteLineJSON{
+				Kind:        l.Kind,
+				Description: l.Description,
+				Days:        l.Days,
+				Daily:       l.Daily,
+				Percent:     l.Percent,
+				Amount:      l.Amount,
+			})
+		}
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "quote computed successfully",
+			Data: VehicleQuoteJSON{
+				Vehicle:  serializeVehicle(qt.Vehicle),
+				From:     qt.From.Format(time.RFC3339),
+				To:       qt.To.Format(time.RFC3339),
+				Days:     qt.Days,
+				Daily:    qt.Daily,
+				Lines:    lines,
+				Subtotal: qt.Subtotal,
+				Discount: qt.Discount,
+				Total:    qt.Total,
+				Currency: qt.Currency,
+			},
+		})
+	}
+}
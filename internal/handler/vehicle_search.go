@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// VehicleSearchResultJSON is a struct that represents a vehicle found by a text search in JSON format
+type VehicleSearchResultJSON struct {
+	Score   float64     `json:"score"`
+	Vehicle VehicleJSON `json:"vehicle"`
+}
+
+// Search is a method that returns a handler for the route GET /vehicles/search?q={text}&limit={limit}
+func (h *VehicleDefault) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var limit int
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			if limit, err = strconv.Atoi(raw); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid limit")
+				return
+			}
+		}
+
+		// process
+		results, err := h.sv(r).Search(r.URL.Query().Get("q"), limit, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidSearch):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]VehicleSearchResultJSON, 0, len(results))
+		for _, result := range results {
+			data = append(data, VehicleSearchResultJSON{Score: result.Score, Vehicle: serializeVehicle(result.Vehicle)})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "vehicles found successfully",
+			Data:    data,
+		})
+	}
+}
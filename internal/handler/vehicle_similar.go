@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+	"github.com/go-chi/chi/v5"
+)
+
+// SimilarVehicleJSON is a struct that represents a vehicle close to a reference one in JSON format
+type SimilarVehicleJSON struct {
+	Distance float64     `json:"distance"`
+	Vehicle  VehicleJSON `json:"vehicle"`
+}
+
+// readWeights is a function that parses a comma separated list of field:weight pairs
+func readWeights(raw string) (weights map[internal.VehicleField]float64, err error) {
+	if raw == "" {
+		return
+	}
+
+	weights = make(map[internal.VehicleField]float64)
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid weight %s", pair)
+		}
+		field, err := internal.ParseVehicleField(name)
+		if err != nil {
+			return nil, err
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight %s", pair)
+		}
+		weights[field] = weight
+	}
+
+	return
+}
+
+// GetSimilar is a method that returns a handler for the route
+// GET /vehicles/{id}/similar?k={k}&weights={field:weight,...}&{filters}
+func (h *VehicleDefault) GetSimilar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		sm := internal.VehicleSimilarQuery{Id: id}
+		if raw := r.URL.Query().Get("k"); raw != "" {
+			if sm.K, err = strconv.Atoi(raw); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid k")
+				return
+			}
+		}
+		if sm.Weights, err = readWeights(r.URL.Query().Get("weights")); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if sm.Filter, err = readFilter(r); err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		similar, err := h.sv(r).GetSimilar(sm, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidSimilarity):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]SimilarVehicleJSON, 0, len(similar))
+		for _, s := range similar {
+			data = append(data, SimilarVehicleJSON{Distance: s.Distance, Vehicle: serializeVehicle(s.Vehicle)})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "similar vehicles found successfully",
+			Data:    data,
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"app/internal"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// readFilter is a function that builds the filter from the query string of the request
+// - categorical fields are compared for equality: ?brand=Ford&fuel_type=diesel
+// - numeric fields are compared by range: ?min_year=1990&max_weight=150
+func readFilter(r *http.Request) (f internal.VehicleFilter, err error) {
+	f.Equals = make(map[internal.VehicleField]string)
+	f.Ranges = make(map[internal.VehicleField]internal.Range)
+
+	for key, values := range r.URL.Query() {
+		value := values[0]
+
+		// equality
+		if field := internal.VehicleField(key); field.IsCategorical() {
+			f.Equals[field] = value
+			continue
+		}
+
+		// range
+		bound, name, ok := strings.Cut(key, "_")
+		if !ok || (bound != "min" && bound != "max") {
+			continue
+		}
+		field, err := internal.ParseVehicleField(name)
+		if err != nil || !field.IsNumeric() {
+			continue
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return f, fmt.Errorf("invalid %s", key)
+		}
+		rg := f.Ranges[field]
+		if bound == "min" {
+			rg.Min = &number
+		} else {
+			rg.Max = &number
+		}
+		f.Ranges[field] = rg
+	}
+
+	return
+}
+
+// readFields is a function that parses a comma separated list of fields
+func readFields(raw string) (fields []internal.VehicleField, err error) {
+	if raw == "" {
+		return
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		field, err := internal.ParseVehicleField(name)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	return
+}
+
+// VehicleStatsJSON is a struct that represents the statistics of a group of vehicles in JSON format
+type VehicleStatsJSON struct {
+	Group       map[internal.VehicleField]string `json:"group"`
+	Count       int                              `json:"count"`
+	Sum         float64                          `json:"sum"`
+	Min         float64                          `json:"min"`
+	Max         float64                          `json:"max"`
+	Mean        float64                          `json:"mean"`
+	Median      float64                          `json:"median"`
+	StdDev      float64                          `json:"stddev"`
+	Percentiles map[string]float64               `json:"percentiles"`
+}
+
+// GetStats is a method that returns a handler for the route GET /vehicles/stats
+// - ?field=max_speed&group_by=brand,fuel_type&percentiles=50,90 plus the filters of readFilter
+func (h *VehicleDefault) GetStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		field, err := internal.ParseVehicleField(r.URL.Query().Get("field"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		groupBy, err := readFields(r.URL.Query().Get("group_by"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var percentiles []float64
+		if raw := r.URL.Query().Get("percentiles"); raw != "" {
+			for _, value := range strings.Split(raw, ",") {
+				p, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					response.Text(w, http.StatusBadRequest, "invalid percentiles")
+					return
+				}
+				percentiles = append(percentiles, p)
+			}
+		}
+
+		filter, err := readFilter(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		stats, err := h.sv(r).GetStats(internal.VehicleStatsQuery{
+			Field:       field,
+			GroupBy:     groupBy,
+			Filter:      filter,
+			Percentiles: percentiles,
+		}, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidVehicleField), errors.Is(err, internal.ErrInvalidPercentile):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]VehicleStatsJSON, 0, len(stats))
+		for _, st := range stats {
+			ps := make(map[string]float64, len(st.Percentiles))
+			for p, value := range st.Percentiles {
+				ps["p"+strconv.FormatFloat(p, 'f', -1, 64)] = value
+			}
+
+			data = append(data, VehicleStatsJSON{
+				Group:       st.Group,
+				Count:       st.Count,
+				Sum:         st.Sum,
+				Min:         st.Min,
+				Max:         st.Max,
+				Mean:        st.Mean,
+				Median:      st.Median,
+				StdDev:      st.StdDev,
+				Percentiles: ps,
+			})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "stats computed successfully",
+			Data:    data,
+		})
+	}
+}
+
+// HistogramBucketJSON is a struct that represents a bucket of a histogram in JSON format
+type HistogramBucketJSON struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// GetHistogram is a method that returns a handler for the route GET /vehicles/histogram
+// - ?field=weight&buckets=10 or ?field=year&edges=1960,1980,2000,2020 plus the filters of readFilter
+func (h *VehicleDefault) GetHistogram() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		field, err := internal.ParseVehicleField(r.URL.Query().Get("field"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var buckets int
+		if raw := r.URL.Query().Get("buckets"); raw != "" {
+			if buckets, err = strconv.Atoi(raw); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid buckets")
+				return
+			}
+		}
+
+		var edges []float64
+		if raw := r.URL.Query().Get("edges"); raw != "" {
+			for _, value := range strings.Split(raw, ",") {
+				edge, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					response.Text(w, http.StatusBadRequest, "invalid edges")
+					return
+				}
+				edges = append(edges, edge)
+			}
+		}
+
+		filter, err := readFilter(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		hs, err := h.sv(r).GetHistogram(internal.VehicleHistogramQuery{
+			Field:   field,
+			Buckets: buckets,
+			Edges:   edges,
+			Filter:  filter,
+		}, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidVehicleField), errors.Is(err, internal.ErrInvalidBuckets):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]HistogramBucketJSON, 0, len(hs))
+		for _, b := range hs {
+			data = append(data, HistogramBucketJSON{Min: b.Min, Max: b.Max, Count: b.Count})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "histogram computed successfully",
+			Data:    data,
+		})
+	}
+}
+
+// FrequencyJSON is a struct that represents the frequency of a value in JSON format
+type FrequencyJSON struct {
+	Value string  `json:"value"`
+	Count int     `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// GetFrequencies is a method that returns a handler for the route GET /vehicles/frequencies
+// - ?field=brand&top=5 plus the filters of readFilter
+func (h *VehicleDefault) GetFrequencies() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		q, err := readQuery(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		field, err := internal.ParseVehicleField(r.URL.Query().Get("field"))
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var top int
+		if raw := r.URL.Query().Get("top"); raw != "" {
+			if top, err = strconv.Atoi(raw); err != nil {
+				response.Text(w, http.StatusBadRequest, "invalid top")
+				return
+			}
+		}
+
+		filter, err := readFilter(r)
+		if err != nil {
+			response.Text(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// process
+		freq, total, err := h.sv(r).GetFrequencies(internal.VehicleFrequencyQuery{
+			Field:  field,
+			Top:    top,
+			Filter: filter,
+		}, q)
+		if err != nil {
+			if forbidden(w, err) {
+				return
+			}
+			switch {
+			case errors.Is(err, internal.ErrInvalidVehicleField):
+				response.Text(w, http.StatusBadRequest, err.Error())
+			default:
+				response.Text(w, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		data := make([]FrequencyJSON, 0, len(freq))
+		for _, f := range freq {
+			data = append(data, FrequencyJSON{Value: f.Value, Count: f.Count, Share: float64(f.Count) / float64(total)})
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "frequencies computed successfully",
+			Data:    data,
+		})
+	}
+}
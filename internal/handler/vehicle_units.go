@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bootcamp-go/web/response"
+)
+
+// measureFields are the fields of a vehicle body that accept a value tagged with a unit
+var measureFields = []internal.VehicleField{
+	internal.FieldMaxSpeed, internal.FieldWeight, internal.FieldHeight, internal.FieldLength, internal.FieldWidth,
+}
+
+// readMeasure is a function that converts a measure of a body to the canonical unit of its field
+// - a number is already in the canonical unit
+// - a string holds a number and a unit, e.g. "120 mph" or "6ft"
+// - an object holds a value and a unit, e.g. {"value": 120, "unit": "mph"}
+func readMeasure(field internal.VehicleField, raw any) (value float64, err error) {
+	switch measure := raw.(type) {
+	case float64:
+		return measure, nil
+	case string:
+		measure = strings.TrimSpace(measure)
+		i := strings.IndexFunc(measure, func(r rune) bool {
+			return !strings.ContainsRune("0123456789.+-eE", r)
+		})
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid %s: a unit is expected", field)
+		}
+		if value, err = strconv.ParseFloat(measure[:i], 64); err != nil {
+			return 0, fmt.Errorf("invalid %s", field)
+		}
+		return internal.ConvertToCanonical(field, value, measure[i:])
+	case map[string]any:
+		number, ok := measure["value"].(float64)
+		unit, okUnit := measure["unit"].(string)
+		if !ok || !okUnit {
+			return 0, fmt.Errorf("invalid %s: value and unit are expected", field)
+		}
+		return internal.ConvertToCanonical(field, number, unit)
+	}
+	return 0, fmt.Errorf("invalid %s", field)
+}
+
+// canonicalizeMeasures is a function that replaces the measures tagged with a unit of a body
+// by their value in the canonical unit
+func canonicalizeMeasures(body map[string]any) (err error) {
+	for _, field := range measureFields {
+		raw, ok := body[string(field)]
+		if !ok {
+			continue
+		}
+		if body[string(field)], err = readMeasure(field, raw); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// canonicalizeBody is a function that rewrites a JSON body, an object or an array of objects,
+// with its measures in the canonical units
+func canonicalizeBody(bytes []byte) (canonical []byte, err error) {
+	var body any
+	if err = json.Unmarshal(bytes, &body); err != nil {
+		return
+	}
+
+	switch b := body.(type) {
+	case map[string]any:
+		err = canonicalizeMeasures(b)
+	case []any:
+		for _, item := range b {
+			if object, ok := item.(map[string]any); ok {
+				if err = canonicalizeMeasures(object); err != nil {
+					break
+				}
+			}
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(body)
+}
+
+// GetUnits is a method that returns a handler for the route GET /vehicles/units
+// with the unit of each measure in each unit system
+func (h *VehicleDefault) GetUnits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := make(map[internal.UnitSystem]map[internal.VehicleField]string)
+		for _, u := range []internal.UnitSystem{internal.UnitsMetric, internal.UnitsImperial} {
+			data[u] = make(map[internal.VehicleField]string)
+			for _, field := range measureFields {
+				data[u][field] = u.Unit(field)
+			}
+		}
+
+		response.JSON(w, http.StatusOK, &Message{
+			Message: "units found successfully",
+			Data:    data,
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("Idempotency key reused with another request")
+	ErrIdempotencyKeyInProgress = errors.New("Idempotency key in progress")
+)
+
+// IdempotentResponse is a struct that represents the response stored for an idempotency key
+type IdempotentResponse struct {
+	// Status is the status code
+	Status int
+	// Header are the headers
+	Header map[string][]string
+	// Body is the body
+	Body []byte
+}
+
+// IdempotencyStore is an interface that represents the responses of the requests sent with an idempotency key
+// - a key is begun by the first request, completed with its response or released if it failed
+type IdempotencyStore interface {
+	// Begin is a method that reserves a key for a request fingerprint, or returns the stored response of the key
+	// - ErrIdempotencyKeyReused when the key was used with another fingerprint
+	// - ErrIdempotencyKeyInProgress when the first request of the key is still running
+	Begin(key string, fingerprint string) (stored *IdempotentResponse, err error)
+	// Complete is a method that stores the response of a begun key for the ttl of the store
+	Complete(key string, r IdempotentResponse) (err error)
+	// Release is a method that forgets a begun key, so the request can be retried
+	Release(key string) (err error)
+}
+
+// IdempotencyEntry is a struct that represents the state of an idempotency key
+type IdempotencyEntry struct {
+	// Fingerprint is the fingerprint of the first request of the key
+	Fingerprint string
+	// Response is the stored response, nil while in progress
+	Response *IdempotentResponse
+	// ExpiresAt is the moment the key is forgotten
+	ExpiresAt time.Time
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"app/internal"
+	"log"
+	"time"
+)
+
+// NewVehiclePurge is a function that returns a new instance of VehiclePurge
+func NewVehiclePurge(sv internal.VehicleService, retention time.Duration, interval time.Duration) *VehiclePurge {
+	return &VehiclePurge{
+		sv:        sv,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+// VehiclePurge is a struct that represents the job that hard deletes the soft deleted vehicles
+type VehiclePurge struct {
+	// sv is the service that will be used by the job
+	sv internal.VehicleService
+	// retention is the time a soft deleted vehicle is kept before being purged
+	retention time.Duration
+	// interval is the time between two purges
+	interval time.Duration
+}
+
+// Run is a method that purges the vehicles on every interval until stop is closed
+func (j *VehiclePurge) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			purged, err := j.sv.PurgeDeleted(j.retention)
+			if err != nil {
+				log.Println("purge vehicles:", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("purge vehicles: %d vehicles purged", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package loader
+
+import (
+	"app/internal"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// NewAuthConfigJSONFile is a function that returns a new instance of AuthConfigJSONFile
+func NewAuthConfigJSONFile(path string) *AuthConfigJSONFile {
+	return &AuthConfigJSONFile{
+		path:   path,
+		getenv: os.Getenv,
+	}
+}
+
+// AuthConfigJSONFile is a struct that implements the AuthConfigLoader interface
+// - secrets are never in the file: HS256 secrets are read from environment variables
+// - the RS256 public key files are relative to the config file
+type AuthConfigJSONFile struct {
+	// path is the path to the file that contains the authentication config in JSON format
+	path string
+	// getenv is the function that reads the environment variables
+	getenv func(key string) string
+}
+
+// APIKeyJSON is a struct that represents an API key in JSON format
+type APIKeyJSON struct {
+	Id     string   `json:"id"`
+	Hash   string   `json:"sha256"`
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant"`
+}
+
+// JWTKeyJSON is a struct that represents a key of the tokens in JSON format
+type JWTKeyJSON struct {
+	Id            string `json:"kid"`
+	Algorithm     string `json:"alg"`
+	SecretEnv     string `json:"secret_env"`
+	PublicKeyFile string `json:"public_key_file"`
+}
+
+// AuthConfigJSON is a struct that represents the authentication config in JSON format
+type AuthConfigJSON struct {
+	APIKeys     []APIKeyJSON `json:"api_keys"`
+	JWTKeys     []JWTKeyJSON `json:"jwt_keys"`
+	Issuer      string       `json:"issuer"`
+	Audience    string       `json:"audience"`
+	RolesClaim  string       `json:"roles_claim"`
+	TenantClaim string       `json:"tenant_claim"`
+	Leeway      string       `json:"leeway"`
+}
+
+// Load is a method that loads the authentication config
+// - HS256 keys whose environment variable is not set are disabled
+func (l *AuthConfigJSONFile) Load() (c internal.AuthConfig, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var cfgJSON AuthConfigJSON
+	err = json.NewDecoder(file).Decode(&cfgJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize config
+	c.Issuer = cfgJSON.Issuer
+	c.Audience = cfgJSON.Audience
+	c.RolesClaim = cfgJSON.RolesClaim
+	c.TenantClaim = cfgJSON.TenantClaim
+	if cfgJSON.Leeway != "" {
+		if c.Leeway, err = time.ParseDuration(cfgJSON.Leeway); err != nil {
+			return c, fmt.Errorf("%w: leeway: %v", internal.ErrInvalidAuthConfig, err)
+		}
+	}
+	for _, k := range cfgJSON.APIKeys {
+		c.APIKeys = append(c.APIKeys, internal.APIKey{
+			Id:     k.Id,
+			Hash:   k.Hash,
+			Roles:  k.Roles,
+			Tenant: k.Tenant,
+		})
+	}
+	for _, k := range cfgJSON.JWTKeys {
+		key := internal.JWTKey{
+			Id:        k.Id,
+			Algorithm: k.Algorithm,
+		}
+		switch {
+		case k.SecretEnv != "":
+			secret := l.getenv(k.SecretEnv)
+			if secret == "" {
+				continue
+			}
+			key.Secret = []byte(secret)
+		case k.PublicKeyFile != "":
+			if key.PublicKey, err = l.readPublicKey(k.PublicKeyFile); err != nil {
+				return c, fmt.Errorf("%w: key %s: %v", internal.ErrInvalidAuthConfig, k.Id, err)
+			}
+		}
+		c.JWTKeys = append(c.JWTKeys, key)
+	}
+
+	return
+}
+
+// readPublicKey is a method that reads a RSA public key in PEM format
+func (l *AuthConfigJSONFile) readPublicKey(path string) (key *rsa.PublicKey, err error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(l.path), path)
+	}
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	block, _ := pem.Decode(bytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a RSA public key", path)
+		}
+		return key, nil
+	}
+}
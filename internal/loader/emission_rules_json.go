@@ -0,0 +1,70 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+)
+
+// NewEmissionRulesJSONFile is a function that returns a new instance of EmissionRulesJSONFile
+func NewEmissionRulesJSONFile(path string) *EmissionRulesJSONFile {
+	return &EmissionRulesJSONFile{
+		path: path,
+	}
+}
+
+// EmissionRulesJSONFile is a struct that implements the EmissionRulesLoader interface
+type EmissionRulesJSONFile struct {
+	// path is the path to the file that contains the emission rules in JSON format
+	path string
+}
+
+// EmissionFactorJSON is a struct that represents an emission factor in JSON format
+type EmissionFactorJSON struct {
+	FuelType     string  `json:"fuel_type"`
+	Unit         string  `json:"unit"`
+	Base         float64 `json:"base"`
+	PerTonne     float64 `json:"per_tonne"`
+	PerKmh       float64 `json:"per_kmh"`
+	CO2PerUnit   float64 `json:"co2_per_unit"`
+	PricePerUnit float64 `json:"price_per_unit"`
+}
+
+// EmissionRulesJSON is a struct that represents the emission rules in JSON format
+type EmissionRulesJSON struct {
+	Currency string               `json:"currency"`
+	Factors  []EmissionFactorJSON `json:"factors"`
+}
+
+// Load is a method that loads the emission rules
+func (l *EmissionRulesJSONFile) Load() (r internal.EmissionRules, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var rulesJSON EmissionRulesJSON
+	err = json.NewDecoder(file).Decode(&rulesJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize rules
+	r.Currency = rulesJSON.Currency
+	for _, f := range rulesJSON.Factors {
+		r.Factors = append(r.Factors, internal.EmissionFactor{
+			FuelType:     f.FuelType,
+			Unit:         f.Unit,
+			Base:         f.Base,
+			PerTonne:     f.PerTonne,
+			PerKmh:       f.PerKmh,
+			CO2PerUnit:   f.CO2PerUnit,
+			PricePerUnit: f.PricePerUnit,
+		})
+	}
+
+	return
+}
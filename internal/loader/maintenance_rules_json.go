@@ -0,0 +1,71 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewMaintenanceRulesJSONFile is a function that returns a new instance of MaintenanceRulesJSONFile
+func NewMaintenanceRulesJSONFile(path string) *MaintenanceRulesJSONFile {
+	return &MaintenanceRulesJSONFile{
+		path: path,
+	}
+}
+
+// MaintenanceRulesJSONFile is a struct that implements the MaintenanceRulesLoader interface
+type MaintenanceRulesJSONFile struct {
+	// path is the path to the file that contains the maintenance rules in JSON format
+	path string
+}
+
+// MaintenanceRuleJSON is a struct that represents a maintenance rule in JSON format
+// - every is a duration that can be in days, e.g. 365d
+type MaintenanceRuleJSON struct {
+	Type     string  `json:"type"`
+	Brand    string  `json:"brand"`
+	FuelType string  `json:"fuel_type"`
+	Every    string  `json:"every"`
+	EveryKm  float64 `json:"every_km"`
+}
+
+// MaintenanceRulesJSON is a struct that represents the maintenance rules in JSON format
+type MaintenanceRulesJSON struct {
+	Rules []MaintenanceRuleJSON `json:"rules"`
+}
+
+// Load is a method that loads the maintenance rules
+func (l *MaintenanceRulesJSONFile) Load() (r []internal.MaintenanceRule, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var rulesJSON MaintenanceRulesJSON
+	err = json.NewDecoder(file).Decode(&rulesJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize rules
+	for _, rl := range rulesJSON.Rules {
+		rule := internal.MaintenanceRule{
+			Type:     rl.Type,
+			Brand:    rl.Brand,
+			FuelType: rl.FuelType,
+			EveryKm:  rl.EveryKm,
+		}
+		if rl.Every != "" {
+			if rule.Every, err = internal.ParseDayDuration(rl.Every); err != nil {
+				return nil, fmt.Errorf("%w: %s: every: %v", internal.ErrInvalidMaintenanceRules, rl.Type, err)
+			}
+		}
+		r = append(r, rule)
+	}
+
+	return
+}
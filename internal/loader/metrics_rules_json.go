@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewMetricsRulesJSONFile is a function that returns a new instance of MetricsRulesJSONFile
+func NewMetricsRulesJSONFile(path string) *MetricsRulesJSONFile {
+	return &MetricsRulesJSONFile{
+		path: path,
+	}
+}
+
+// MetricsRulesJSONFile is a struct that implements the MetricsRulesLoader interface
+type MetricsRulesJSONFile struct {
+	// path is the path to the file that contains the metrics rules in JSON format
+	path string
+}
+
+// SizeClassJSON is a struct that represents a size class in JSON format
+// - min and max are the bounds of the numeric fields, derived or not
+type SizeClassJSON struct {
+	Name string             `json:"name"`
+	Min  map[string]float64 `json:"min"`
+	Max  map[string]float64 `json:"max"`
+}
+
+// MetricsRulesJSON is a struct that represents the metrics rules in JSON format
+type MetricsRulesJSON struct {
+	SeatVolume  float64         `json:"seat_volume"`
+	SizeClasses []SizeClassJSON `json:"size_classes"`
+}
+
+// Load is a method that loads the metrics rules
+func (l *MetricsRulesJSONFile) Load() (r internal.MetricsRules, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var rulesJSON MetricsRulesJSON
+	err = json.NewDecoder(file).Decode(&rulesJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize rules
+	r.SeatVolume = rulesJSON.SeatVolume
+	for _, sc := range rulesJSON.SizeClasses {
+		class := internal.SizeClass{Name: sc.Name, Filter: internal.VehicleFilter{Ranges: make(map[internal.VehicleField]internal.Range)}}
+		if err = readBounds(class.Filter.Ranges, sc.Min, true); err != nil {
+			return r, fmt.Errorf("size class %s: %w", sc.Name, err)
+		}
+		if err = readBounds(class.Filter.Ranges, sc.Max, false); err != nil {
+			return r, fmt.Errorf("size class %s: %w", sc.Name, err)
+		}
+		r.SizeClasses = append(r.SizeClasses, class)
+	}
+
+	return
+}
+
+// readBounds is a function that sets the lower or the upper bounds of the ranges of the fields
+func readBounds(ranges map[internal.VehicleField]internal.Range, bounds map[string]float64, isMin bool) (err error) {
+	for name, value := range bounds {
+		field, err := internal.ParseVehicleField(name)
+		if err != nil {
+			return err
+		}
+
+		value := value
+		rg := ranges[field]
+		if isMin {
+			rg.Min = &value
+		} else {
+			rg.Max = &value
+		}
+		ranges[field] = rg
+	}
+
+	return
+}
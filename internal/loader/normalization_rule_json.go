@@ -0,0 +1,55 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+)
+
+// NewNormalizationRuleJSONFile is a function that returns a new instance of NormalizationRuleJSONFile
+func NewNormalizationRuleJSONFile(path string) *NormalizationRuleJSONFile {
+	return &NormalizationRuleJSONFile{
+		path: path,
+	}
+}
+
+// NormalizationRuleJSONFile is a struct that implements the NormalizationRuleLoader interface
+type NormalizationRuleJSONFile struct {
+	// path is the path to the file that contains the normalization rules in JSON format
+	path string
+}
+
+// NormalizationRuleJSON is a struct that represents a normalization rule in JSON format
+type NormalizationRuleJSON struct {
+	Field    string              `json:"field"`
+	Case     string              `json:"case"`
+	Synonyms map[string][]string `json:"synonyms"`
+}
+
+// Load is a method that loads the normalization rules
+func (l *NormalizationRuleJSONFile) Load() (r []internal.NormalizationRule, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var rulesJSON []NormalizationRuleJSON
+	err = json.NewDecoder(file).Decode(&rulesJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize rules
+	for _, rl := range rulesJSON {
+		r = append(r, internal.NormalizationRule{
+			Field:    internal.VehicleField(rl.Field),
+			Case:     rl.Case,
+			Synonyms: rl.Synonyms,
+		})
+	}
+
+	return
+}
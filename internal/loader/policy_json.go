@@ -0,0 +1,66 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+)
+
+// NewPolicyJSONFile is a function that returns a new instance of PolicyJSONFile
+func NewPolicyJSONFile(path string) *PolicyJSONFile {
+	return &PolicyJSONFile{
+		path: path,
+	}
+}
+
+// PolicyJSONFile is a struct that implements the PolicyLoader interface
+type PolicyJSONFile struct {
+	// path is the path to the file that contains the policy in JSON format
+	path string
+}
+
+// RoleJSON is a struct that represents a role in JSON format
+type RoleJSON struct {
+	Inherits    []string `json:"inherits"`
+	Permissions []string `json:"permissions"`
+}
+
+// PolicyJSON is a struct that represents the policy in JSON format
+type PolicyJSON struct {
+	Roles          map[string]RoleJSON `json:"roles"`
+	Grants         map[string][]string `json:"grants"`
+	AnonymousRoles []string            `json:"anonymous_roles"`
+}
+
+// Load is a method that loads the policy
+func (l *PolicyJSONFile) Load() (p internal.Policy, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var policyJSON PolicyJSON
+	err = json.NewDecoder(file).Decode(&policyJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize policy
+	p.Grants = policyJSON.Grants
+	p.AnonymousRoles = policyJSON.AnonymousRoles
+	for name, r := range policyJSON.Roles {
+		role := internal.Role{
+			Name:     name,
+			Inherits: r.Inherits,
+		}
+		for _, permission := range r.Permissions {
+			role.Permissions = append(role.Permissions, internal.Permission(permission))
+		}
+		p.Roles = append(p.Roles, role)
+	}
+
+	return
+}
@@ -0,0 +1,112 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewPricingRulesJSONFile is a function that returns a new instance of PricingRulesJSONFile
+func NewPricingRulesJSONFile(path string) *PricingRulesJSONFile {
+	return &PricingRulesJSONFile{
+		path: path,
+	}
+}
+
+// PricingRulesJSONFile is a struct that implements the PricingRulesLoader interface
+type PricingRulesJSONFile struct {
+	// path is the path to the file that contains the pricing rules in JSON format
+	path string
+}
+
+// BaseRateJSON is a struct that represents a base rate in JSON format
+type BaseRateJSON struct {
+	Brand     string  `json:"brand"`
+	SizeClass string  `json:"size_class"`
+	Daily     float64 `json:"daily"`
+}
+
+// SurchargeJSON is a struct that represents a surcharge in JSON format
+// - equals are the values of the categorical fields, min and max the bounds of the numeric fields
+type SurchargeJSON struct {
+	Name   string             `json:"name"`
+	Equals map[string]string  `json:"equals"`
+	Min    map[string]float64 `json:"min"`
+	Max    map[string]float64 `json:"max"`
+	Daily  float64            `json:"daily"`
+}
+
+// SeasonalDiscountJSON is a struct that represents a seasonal discount in JSON format
+type SeasonalDiscountJSON struct {
+	Name    string  `json:"name"`
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Percent float64 `json:"percent"`
+}
+
+// DurationDiscountJSON is a struct that represents a duration discount in JSON format
+type DurationDiscountJSON struct {
+	MinDays int     `json:"min_days"`
+	Percent float64 `json:"percent"`
+}
+
+// PricingRulesJSON is a struct that represents the pricing rules in JSON format
+type PricingRulesJSON struct {
+	Currency   string                 `json:"currency"`
+	BaseRates  []BaseRateJSON         `json:"base_rates"`
+	Surcharges []SurchargeJSON        `json:"surcharges"`
+	Seasons    []SeasonalDiscountJSON `json:"seasons"`
+	Durations  []DurationDiscountJSON `json:"durations"`
+}
+
+// Load is a method that loads the pricing rules
+func (l *PricingRulesJSONFile) Load() (r internal.PricingRules, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var rulesJSON PricingRulesJSON
+	err = json.NewDecoder(file).Decode(&rulesJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize rules
+	r.Currency = rulesJSON.Currency
+	for _, b := range rulesJSON.BaseRates {
+		r.BaseRates = append(r.BaseRates, internal.BaseRate{Brand: b.Brand, SizeClass: b.SizeClass, Daily: b.Daily})
+	}
+	for _, s := range rulesJSON.Surcharges {
+		surcharge := internal.Surcharge{Name: s.Name, Daily: s.Daily, When: internal.VehicleFilter{
+			Equals: make(map[internal.VehicleField]string),
+			Ranges: make(map[internal.VehicleField]internal.Range),
+		}}
+		for name, value := range s.Equals {
+			field, err := internal.ParseVehicleField(name)
+			if err != nil {
+				return r, fmt.Errorf("surcharge %s: %w", s.Name, err)
+			}
+			surcharge.When.Equals[field] = value
+		}
+		if err = readBounds(surcharge.When.Ranges, s.Min, true); err != nil {
+			return r, fmt.Errorf("surcharge %s: %w", s.Name, err)
+		}
+		if err = readBounds(surcharge.When.Ranges, s.Max, false); err != nil {
+			return r, fmt.Errorf("surcharge %s: %w", s.Name, err)
+		}
+		r.Surcharges = append(r.Surcharges, surcharge)
+	}
+	for _, s := range rulesJSON.Seasons {
+		r.Seasons = append(r.Seasons, internal.SeasonalDiscount{Name: s.Name, From: s.From, To: s.To, Percent: s.Percent})
+	}
+	for _, d := range rulesJSON.Durations {
+		r.Durations = append(r.Durations, internal.DurationDiscount{MinDays: d.MinDays, Percent: d.Percent})
+	}
+
+	return
+}
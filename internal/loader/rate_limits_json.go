@@ -0,0 +1,63 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// NewRateLimitsJSONFile is a function that returns a new instance of RateLimitsJSONFile
+func NewRateLimitsJSONFile(path string) *RateLimitsJSONFile {
+	return &RateLimitsJSONFile{
+		path: path,
+	}
+}
+
+// RateLimitsJSONFile is a struct that implements the RateLimitsLoader interface
+type RateLimitsJSONFile struct {
+	// path is the path to the file that contains the rate limits in JSON format
+	path string
+}
+
+// RateBudgetJSON is a struct that represents the budget of a class of requests in JSON format
+type RateBudgetJSON struct {
+	PerSecond  float64 `json:"per_second"`
+	Burst      int     `json:"burst"`
+	DailyQuota int     `json:"daily_quota"`
+}
+
+// Load is a method that loads the rate limits
+func (l *RateLimitsJSONFile) Load() (r internal.RateLimits, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var budgetsJSON map[string]RateBudgetJSON
+	err = json.NewDecoder(file).Decode(&budgetsJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize limits
+	r.Budgets = make(map[internal.RateClass]internal.RateBudget, len(budgetsJSON))
+	for name, b := range budgetsJSON {
+		class := internal.RateClass(name)
+		switch class {
+		case internal.RateClassRead, internal.RateClassWrite, internal.RateClassBatch, internal.RateClassAuth:
+		default:
+			return r, fmt.Errorf("%w: unknown class %s", internal.ErrInvalidRateLimits, name)
+		}
+		r.Budgets[class] = internal.RateBudget{
+			PerSecond:  b.PerSecond,
+			Burst:      b.Burst,
+			DailyQuota: b.DailyQuota,
+		}
+	}
+
+	return
+}
@@ -0,0 +1,55 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+)
+
+// NewRegistrationRuleJSONFile is a function that returns a new instance of RegistrationRuleJSONFile
+func NewRegistrationRuleJSONFile(path string) *RegistrationRuleJSONFile {
+	return &RegistrationRuleJSONFile{
+		path: path,
+	}
+}
+
+// RegistrationRuleJSONFile is a struct that implements the RegistrationRuleLoader interface
+type RegistrationRuleJSONFile struct {
+	// path is the path to the file that contains the registration rules in JSON format
+	path string
+}
+
+// RegistrationRuleJSON is a struct that represents a registration rule in JSON format
+type RegistrationRuleJSON struct {
+	Country string `json:"country"`
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// Load is a method that loads the registration rules
+func (l *RegistrationRuleJSONFile) Load() (r []internal.RegistrationRule, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var rulesJSON []RegistrationRuleJSON
+	err = json.NewDecoder(file).Decode(&rulesJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize rules
+	for _, rl := range rulesJSON {
+		r = append(r, internal.RegistrationRule{
+			Country: rl.Country,
+			Name:    rl.Name,
+			Pattern: rl.Pattern,
+		})
+	}
+
+	return
+}
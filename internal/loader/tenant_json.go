@@ -0,0 +1,55 @@
+package loader
+
+import (
+	"app/internal"
+	"encoding/json"
+	"os"
+)
+
+// NewTenantJSONFile is a function that returns a new instance of TenantJSONFile
+func NewTenantJSONFile(path string) *TenantJSONFile {
+	return &TenantJSONFile{
+		path: path,
+	}
+}
+
+// TenantJSONFile is a struct that implements the TenantLoader interface
+type TenantJSONFile struct {
+	// path is the path to the file that contains the tenants in JSON format
+	path string
+}
+
+// TenantJSON is a struct that represents a tenant in JSON format
+type TenantJSON struct {
+	Id               string `json:"id"`
+	LoaderFilePath   string `json:"vehicles_file"`
+	SequenceFilePath string `json:"sequence_file"`
+}
+
+// Load is a method that loads the tenants
+func (l *TenantJSONFile) Load() (t []internal.Tenant, err error) {
+	// open file
+	file, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// decode file
+	var tenantsJSON []TenantJSON
+	err = json.NewDecoder(file).Decode(&tenantsJSON)
+	if err != nil {
+		return
+	}
+
+	// serialize tenants
+	for _, tn := range tenantsJSON {
+		t = append(t, internal.Tenant{
+			Id:               tn.Id,
+			LoaderFilePath:   tn.LoaderFilePath,
+			SequenceFilePath: tn.SequenceFilePath,
+		})
+	}
+
+	return
+}
@@ -25,6 +25,8 @@ type VehicleJSON struct {
 	Brand           string  `json:"brand"`
 	Model           string  `json:"model"`
 	Registration    string  `json:"registration"`
+	Country         string  `json:"country"`
+	VIN             string  `json:"vin"`
 	Color           string  `json:"color"`
 	FabricationYear int     `json:"year"`
 	Capacity        int     `json:"passengers"`
@@ -62,6 +64,8 @@ func (l *VehicleJSONFile) Load() (v map[int]internal.Vehicle, err error) {
 				Brand:           vh.Brand,
 				Model:           vh.Model,
 				Registration:    vh.Registration,
+				Country:         vh.Country,
+				VIN:             vh.VIN,
 				Color:           vh.Color,
 				FabricationYear: vh.FabricationYear,
 				Capacity:        vh.Capacity,
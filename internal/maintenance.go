@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMaintenanceRecord = errors.New("Invalid maintenance record")
+	ErrInvalidMaintenanceRules  = errors.New("Invalid maintenance rules")
+)
+
+// MaintenanceRecord is a struct that represents a service done to a vehicle
+type MaintenanceRecord struct {
+	// Id is the unique identifier of the record within its tenant
+	Id int
+	// VehicleId is the id of the serviced vehicle
+	VehicleId int
+	// Date is the day the service was done
+	Date time.Time
+	// Odometer is the reading of the odometer at the service in km
+	Odometer float64
+	// Type is the type of service, e.g. oil_change or inspection
+	Type string
+	// Cost is the cost of the service
+	Cost float64
+	// Notes are free notes about the service
+	Notes string
+}
+
+// NormalizeMaintenanceType is a function that returns a type of service in lower snake case
+func NormalizeMaintenanceType(t string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(t, "-", " "))), "_")
+}
+
+// ParseDayDuration is a function that parses a duration that can also be in days, e.g. 30d, 12h or 1h30m
+func ParseDayDuration(s string) (d time.Duration, err error) {
+	if days, ok := strings.CutSuffix(strings.TrimSpace(s), "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// MaintenanceRule is a struct that represents how often a type of service is due
+// - the service is due when either interval is reached, a rule without intervals exempts its vehicles
+// - the rule matching the most of brand and fuel type is the one applied to a vehicle, brand first
+type MaintenanceRule struct {
+	// Type is the type of service
+	Type string
+	// Brand is the brand of the vehicles of the rule, empty for any
+	Brand string
+	// FuelType is the fuel type of the vehicles of the rule, empty for any
+	FuelType string
+	// Every is the time between two services, 0 for no time limit
+	Every time.Duration
+	// EveryKm is the distance between two services in km, 0 for no distance limit
+	EveryKm float64
+}
+
+// Matches is a method that reports if the rule applies to a vehicle
+func (r MaintenanceRule) Matches(v Vehicle) bool {
+	return (r.Brand == "" || strings.EqualFold(r.Brand, v.Brand)) && (r.FuelType == "" || strings.EqualFold(r.FuelType, v.FuelType))
+}
+
+// Specificity is a method that returns how specific the rule is, the higher the more
+func (r MaintenanceRule) Specificity() (s int) {
+	if r.Brand != "" {
+		s += 2
+	}
+	if r.FuelType != "" {
+		s++
+	}
+	return
+}
+
+// MaintenanceRulesLoader is an interface that represents the loader for the maintenance rules
+type MaintenanceRulesLoader interface {
+	// Load is a method that loads the maintenance rules
+	Load() (r []MaintenanceRule, err error)
+}
+
+// MaintenanceDue is a struct that represents a service a vehicle is due for
+type MaintenanceDue struct {
+	// VehicleId is the id of the vehicle
+	VehicleId int
+	// Brand is the brand of the vehicle
+	Brand string
+	// Type is the type of service
+	Type string
+	// LastDate is the day of the last service of the type, or of the first record of the vehicle if never done
+	LastDate time.Time
+	// LastOdometer is the odometer of the last service of the type, or of the first record of the vehicle if never done
+	LastOdometer float64
+	// Odometer is the last known odometer of the vehicle in km
+	Odometer float64
+	// DueDate is the day the service is due by time, nil without time limit
+	DueDate *time.Time
+	// DueOdometer is the odometer the service is due at, nil without distance limit
+	DueOdometer *float64
+	// DueAt is the earliest moment the service is due, projecting the distance with the usage of the vehicle
+	DueAt time.Time
+	// Overdue is a flag that reports if the service is already due
+	Overdue bool
+}
+
+// MaintenanceCostQuery is a struct that represents how the costs of the services are summarized
+type MaintenanceCostQuery struct {
+	// GroupBy is the categorical field the costs are grouped by, empty groups them by vehicle
+	GroupBy VehicleField
+	// From is the first day of the services, zero for no lower bound
+	From time.Time
+	// To is the last day of the services, zero for no upper bound
+	To time.Time
+}
+
+// MaintenanceCost is a struct that represents the costs of the services of a group
+type MaintenanceCost struct {
+	// Group is the value of the grouping field, or the vehicle id
+	Group string
+	// Records is the number of services
+	Records int
+	// Total is the sum of the costs
+	Total float64
+	// Average is the average cost of a service
+	Average float64
+}
+
+// MaintenanceRepository is an interface that represents a maintenance record repository
+type MaintenanceRepository interface {
+	// FindAll is a method that returns every record, oldest first
+	FindAll() (r []MaintenanceRecord, err error)
+	// FindByVehicle is a method that returns the records of a vehicle, oldest first
+	FindByVehicle(vehicleId int) (r []MaintenanceRecord, err error)
+	// Add is a method that adds a record and returns its id
+	Add(r MaintenanceRecord) (id int, err error)
+}
+
+// MaintenanceService is an interface that represents a maintenance service
+type MaintenanceService interface {
+	// AddRecord is a method that records a service done to a vehicle and returns its id
+	AddRecord(r MaintenanceRecord) (id int, err error)
+	// Records is a method that returns the services of a vehicle, oldest first
+	Records(vehicleId int) (r []MaintenanceRecord, err error)
+	// Due is a method that returns the services overdue or due within a time, soonest first
+	Due(within time.Duration) (d []MaintenanceDue, err error)
+	// Costs is a method that summarizes the costs of the services by group, most expensive first
+	Costs(q MaintenanceCostQuery) (c []MaintenanceCost, err error)
+}
+
+// MaintenanceServiceProvider is an interface that represents the source of the maintenance service of each request
+type MaintenanceServiceProvider interface {
+	// Service is a method that returns the maintenance service for a context
+	Service(ctx context.Context) MaintenanceService
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRateLimits = errors.New("Invalid rate limits")
+)
+
+// RateClass is the kind of request a budget applies to
+type RateClass string
+
+const (
+	RateClassRead  RateClass = "read"
+	RateClassWrite RateClass = "write"
+	RateClassBatch RateClass = "batch"
+	// RateClassAuth is the class of the failed authentications of an IP address, charged before the principal is known
+	RateClassAuth RateClass = "auth"
+)
+
+// RateBudget is a struct that represents the token bucket and the daily quota of a class of requests
+type RateBudget struct {
+	// PerSecond is the number of tokens added to the bucket every second
+	PerSecond float64
+	// Burst is the capacity of the bucket, the requests that can be made at once
+	Burst int
+	// DailyQuota is the number of requests allowed every UTC day, 0 for no quota
+	DailyQuota int
+}
+
+// RateLimits is a struct that represents the budgets of each class of requests, a class without budget is not limited
+type RateLimits struct {
+	// Budgets is a map of the budget of each class
+	Budgets map[RateClass]RateBudget
+}
+
+// RateLimitsLoader is an interface that represents the loader for the rate limits
+type RateLimitsLoader interface {
+	// Load is a method that loads the rate limits
+	Load() (l RateLimits, err error)
+}
+
+// RateDecision is a struct that represents the outcome of a request against its budget
+// - the limit, remaining and reset are those of the most restrictive of the bucket and the quota
+type RateDecision struct {
+	// Allowed reports if the request can be served
+	Allowed bool
+	// Limited reports if the class has a budget, the other fields are empty otherwise
+	Limited bool
+	// Limit is the number of requests of the window
+	Limit int
+	// Remaining is the number of requests left in the window
+	Remaining int
+	// Reset is the time until the window is full again
+	Reset time.Duration
+	// RetryAfter is the time until the request can be retried, when not allowed
+	RetryAfter time.Duration
+}
+
+// RateLimiter is an interface that represents the budgets of the clients, e.g. in memory or in a shared store
+type RateLimiter interface {
+	// Allow is a method that takes a request of a class from the budget of a client key
+	Allow(key string, class RateClass) (d RateDecision, err error)
+	// Peek is a method that reports if a request of a class would be allowed, without taking it from the budget
+	Peek(key string, class RateClass) (d RateDecision, err error)
+}
@@ -0,0 +1,31 @@
+package internal
+
+import "errors"
+
+var (
+	ErrUnknownRegistrationCountry = errors.New("Unknown registration country")
+	ErrInvalidRegistrationFormat  = errors.New("Invalid registration format")
+)
+
+// RegistrationRule is a struct that represents an accepted registration format of a country
+type RegistrationRule struct {
+	// Country is the country code the rule applies to (e.g. ES, UK, US-CA)
+	Country string
+	// Name is the name of the format
+	Name string
+	// Pattern is the regular expression a normalized registration must match
+	Pattern string
+}
+
+// RegistrationRuleLoader is an interface that represents the loader for registration rules
+type RegistrationRuleLoader interface {
+	// Load is a method that loads the registration rules
+	Load() (r []RegistrationRule, err error)
+}
+
+// RegistrationValidator is an interface that represents a validator of registrations by country
+type RegistrationValidator interface {
+	// Validate is a method that checks a normalized registration against the formats of a country
+	// and returns the name of the matching format
+	Validate(country string, registration string) (format string, err error)
+}
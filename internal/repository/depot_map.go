@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NewDepotMap is a function that returns a new instance of DepotMap
+func NewDepotMap(db map[int]internal.Depot) *DepotMap {
+	// default config / values
+	defaultDb := make(map[int]internal.Depot)
+	var lastId int
+	for id, d := range db {
+		defaultDb[id] = d
+		if id > lastId {
+			lastId = id
+		}
+	}
+
+	return &DepotMap{
+		db:     defaultDb,
+		lastId: lastId,
+	}
+}
+
+// DepotMap is a struct that implements the DepotRepository interface in memory
+type DepotMap struct {
+	// mu is the mutex that guards the depots
+	mu sync.RWMutex
+	// db is a map of depots by id
+	db map[int]internal.Depot
+	// lastId is the last id given to a depot
+	lastId int
+}
+
+// FindAll is a method that returns a map of all depots
+func (r *DepotMap) FindAll() (d map[int]internal.Depot, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d = make(map[int]internal.Depot, len(r.db))
+	for id, value := range r.db {
+		d[id] = value
+	}
+	return
+}
+
+// FindById is a method that returns a depot by id
+func (r *DepotMap) FindById(id int) (d internal.Depot, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.db[id]
+	if !ok {
+		return d, fmt.Errorf("%w: %d", internal.ErrDepotNotFound, id)
+	}
+	return
+}
+
+// Add is a method that adds a depot with the next id, its name must be unique regardless of the case
+func (r *DepotMap) Add(d internal.Depot) (id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, value := range r.db {
+		if strings.EqualFold(value.Name, d.Name) {
+			return 0, fmt.Errorf("%w: %s", internal.ErrDepotAlreadyExists, d.Name)
+		}
+	}
+
+	r.lastId++
+	d.Id = r.lastId
+	r.db[d.Id] = d
+	return d.Id, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"app/internal"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// NewDriverMap is a function that returns a new instance of DriverMap
+func NewDriverMap(db map[int]internal.Driver) *DriverMap {
+	// default config / values
+	defaultDb := make(map[int]internal.Driver)
+	var lastId int
+	for id, d := range db {
+		defaultDb[id] = d
+		if id > lastId {
+			lastId = id
+		}
+	}
+
+	return &DriverMap{
+		db:       defaultDb,
+		lastId:   lastId,
+		vehicles: make(map[int]int),
+		drivers:  make(map[int]int),
+	}
+}
+
+// DriverMap is a struct that implements the DriverRepository interface in memory
+// - the assignments are never removed, the ended ones are the history
+type DriverMap struct {
+	// mu is the mutex that guards the drivers and their assignments
+	mu sync.RWMutex
+	// db is a map of drivers by id
+	db map[int]internal.Driver
+	// lastId is the last id given to a driver
+	lastId int
+	// assignments are the assignments in the order they were opened, the id of each one is its position + 1
+	assignments []internal.Assignment
+	// vehicles is a map of the index of the current assignment of each vehicle
+	vehicles map[int]int
+	// drivers is a map of the index of the current assignment of each driver
+	drivers map[int]int
+}
+
+// FindAll is a method that returns a map of all drivers
+func (r *DriverMap) FindAll() (d map[int]internal.Driver, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d = make(map[int]internal.Driver, len(r.db))
+	for id, value := range r.db {
+		d[id] = value
+	}
+	return
+}
+
+// FindById is a method that returns a driver by id
+func (r *DriverMap) FindById(id int) (d internal.Driver, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.db[id]
+	if !ok {
+		return d, fmt.Errorf("%w: %d", internal.ErrDriverNotFound, id)
+	}
+	return
+}
+
+// Add is a method that adds a driver with the next id, its license number must be unique
+func (r *DriverMap) Add(d internal.Driver) (id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, value := range r.db {
+		if value.LicenseNumber == d.LicenseNumber {
+			return 0, fmt.Errorf("%w: license %s", internal.ErrDriverAlreadyExists, d.LicenseNumber)
+		}
+	}
+
+	r.lastId++
+	d.Id = r.lastId
+	r.db[d.Id] = d
+	return d.Id, nil
+}
+
+// Assign is a method that opens an assignment, neither its vehicle nor its driver can have a current one
+func (r *DriverMap) Assign(a internal.Assignment) (id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.db[a.DriverId]; !ok {
+		return 0, fmt.Errorf("%w: %d", internal.ErrDriverNotFound, a.DriverId)
+	}
+	if i, ok := r.vehicles[a.VehicleId]; ok {
+		return 0, fmt.Errorf("%w: driver %d", internal.ErrVehicleAlreadyAssigned, r.assignments[i].DriverId)
+	}
+	if i, ok := r.drivers[a.DriverId]; ok {
+		return 0, fmt.Errorf("%w: vehicle %d", internal.ErrDriverAlreadyAssigned, r.assignments[i].VehicleId)
+	}
+
+	a.Id = len(r.assignments) + 1
+	a.To = nil
+	r.assignments = append(r.assignments, a)
+	r.vehicles[a.VehicleId] = a.Id - 1
+	r.drivers[a.DriverId] = a.Id - 1
+	return a.Id, nil
+}
+
+// Unassign is a method that ends the current assignment of a vehicle at a moment and returns it
+func (r *DriverMap) Unassign(vehicleId int, at time.Time) (a internal.Assignment, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.vehicles[vehicleId]
+	if !ok {
+		return a, fmt.Errorf("%w: %d", internal.ErrAssignmentNotFound, vehicleId)
+	}
+
+	r.assignments[i].To = &at
+	delete(r.vehicles, vehicleId)
+	delete(r.drivers, r.assignments[i].DriverId)
+	return r.assignments[i], nil
+}
+
+// Assignments is a method that returns the assignments matching a query, oldest first
+func (r *DriverMap) Assignments(q internal.AssignmentQuery) (a []internal.Assignment, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a = make([]internal.Assignment, 0)
+	for _, value := range r.assignments {
+		if (q.VehicleId == 0 || value.VehicleId == q.VehicleId) && (q.DriverId == 0 || value.DriverId == q.DriverId) {
+			a = append(a, value)
+		}
+	}
+	return
+}
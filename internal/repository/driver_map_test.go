@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"app/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for DriverMap
+func TestDriverMap_Assign(t *testing.T) {
+	// newRepository is a function that returns a repository with the drivers 1 and 2
+	newRepository := func() *DriverMap {
+		return NewDriverMap(map[int]internal.Driver{
+			1: {Id: 1, Name: "Jane Doe", LicenseNumber: "LIC1"},
+			2: {Id: 2, Name: "John Roe", LicenseNumber: "LIC2"},
+		})
+	}
+	from := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("case 1: one current assignment by vehicle and by driver", func(t *testing.T) {
+		// arrange
+		rp := newRepository()
+
+		// act
+		id, err := rp.Assign(internal.Assignment{VehicleId: 10, DriverId: 1, From: from})
+		_, errVehicle := rp.Assign(internal.Assignment{VehicleId: 10, DriverId: 2, From: from})
+		_, errDriver := rp.Assign(internal.Assignment{VehicleId: 11, DriverId: 1, From: from})
+		_, errUnknown := rp.Assign(internal.Assignment{VehicleId: 11, DriverId: 3, From: from})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, id)
+		require.ErrorIs(t, errVehicle, internal.ErrVehicleAlreadyAssigned)
+		require.ErrorIs(t, errDriver, internal.ErrDriverAlreadyAssigned)
+		require.ErrorIs(t, errUnknown, internal.ErrDriverNotFound)
+	})
+
+	t.Run("case 2: unassigned ones are kept as history", func(t *testing.T) {
+		// arrange
+		rp := newRepository()
+		to := from.Add(8 * time.Hour)
+		rp.Assign(internal.Assignment{VehicleId: 10, DriverId: 1, From: from})
+
+		// act
+		ended, err := rp.Unassign(10, to)
+		_, errAgain := rp.Unassign(10, to)
+		_, errAssign := rp.Assign(internal.Assignment{VehicleId: 11, DriverId: 1, From: to})
+		history, _ := rp.Assignments(internal.AssignmentQuery{DriverId: 1})
+		vehicle, _ := rp.Assignments(internal.AssignmentQuery{VehicleId: 10})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, &to, ended.To)
+		require.ErrorIs(t, errAgain, internal.ErrAssignmentNotFound)
+		require.NoError(t, errAssign)
+		require.Len(t, history, 2)
+		require.False(t, history[0].IsCurrent())
+		require.True(t, history[1].IsCurrent())
+		require.Len(t, vehicle, 1)
+	})
+}
+
+func TestDriverMap_Add(t *testing.T) {
+	// arrange
+	rp := NewDriverMap(map[int]internal.Driver{4: {Id: 4, Name: "Jane Doe", LicenseNumber: "LIC1"}})
+
+	// act
+	id, err := rp.Add(internal.Driver{Name: "John Roe", LicenseNumber: "LIC2"})
+	_, errRepeated := rp.Add(internal.Driver{Name: "Jane Smith", LicenseNumber: "LIC1"})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 5, id)
+	require.ErrorIs(t, errRepeated, internal.ErrDriverAlreadyExists)
+}
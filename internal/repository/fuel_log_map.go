@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"app/internal"
+	"sort"
+	"sync"
+)
+
+// NewFuelLogMap is a function that returns a new instance of FuelLogMap
+func NewFuelLogMap() *FuelLogMap {
+	return &FuelLogMap{
+		vehicles: make(map[int][]int),
+	}
+}
+
+// FuelLogMap is a struct that implements the FuelLogRepository interface in memory
+type FuelLogMap struct {
+	// mu is the mutex that guards the logs
+	mu sync.RWMutex
+	// logs are the logs in the order they were added, the id of each one is its position + 1
+	logs []internal.FuelLog
+	// vehicles is a map of the positions of the logs of each vehicle, oldest first
+	vehicles map[int][]int
+}
+
+// FindAll is a method that returns every log, oldest first
+func (r *FuelLogMap) FindAll() (logs []internal.FuelLog, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	logs = make([]internal.FuelLog, len(r.logs))
+	copy(logs, r.logs)
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Date.Before(logs[j].Date)
+	})
+	return
+}
+
+// FindByVehicle is a method that returns the logs of a vehicle, oldest first
+func (r *FuelLogMap) FindByVehicle(vehicleId int) (logs []internal.FuelLog, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	logs = make([]internal.FuelLog, 0, len(r.vehicles[vehicleId]))
+	for _, i := range r.vehicles[vehicleId] {
+		logs = append(logs, r.logs[i])
+	}
+	return
+}
+
+// Add is a method that adds a log and returns its id
+// - the logs of each vehicle are kept by date, the ones of the same moment by odometer
+func (r *FuelLogMap) Add(log internal.FuelLog) (id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log.Id = len(r.logs) + 1
+	r.logs = append(r.logs, log)
+
+	positions := append(r.vehicles[log.VehicleId], log.Id-1)
+	sort.SliceStable(positions, func(i, j int) bool {
+		a, b := r.logs[positions[i]], r.logs[positions[j]]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.Odometer < b.Odometer
+	})
+	r.vehicles[log.VehicleId] = positions
+
+	return log.Id, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"app/internal"
+	"sync"
+	"time"
+)
+
+// NewIdempotencyMap is a function that returns a new instance of IdempotencyMap
+func NewIdempotencyMap(ttl time.Duration) *IdempotencyMap {
+	return &IdempotencyMap{
+		ttl:     ttl,
+		entries: make(map[string]*internal.IdempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// IdempotencyMap is a struct that implements the IdempotencyStore interface in memory
+// - the keys in progress expire after the ttl as well, so a crashed request does not lock its key forever
+type IdempotencyMap struct {
+	// mu is the mutex that guards the entries
+	mu sync.Mutex
+	// ttl is the time a key is kept
+	ttl time.Duration
+	// entries is a map of the entry of each key
+	entries map[string]*internal.IdempotencyEntry
+	// swept is the moment the expired keys were last forgotten
+	swept time.Time
+	// now is the clock of the expirations
+	now func() time.Time
+}
+
+// Begin is a method that reserves a key for a request fingerprint, or returns the stored response of the key
+func (s *IdempotencyMap) Begin(key string, fingerprint string) (stored *internal.IdempotentResponse, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.swept) > time.Minute {
+		s.sweep(now)
+	}
+
+	e, ok := s.entries[key]
+	if ok && !now.Before(e.ExpiresAt) {
+		delete(s.entries, key)
+		ok = false
+	}
+	switch {
+	case !ok:
+		s.entries[key] = &internal.IdempotencyEntry{Fingerprint: fingerprint, ExpiresAt: now.Add(s.ttl)}
+		return nil, nil
+	case e.Fingerprint != fingerprint:
+		return nil, internal.ErrIdempotencyKeyReused
+	case e.Response == nil:
+		return nil, internal.ErrIdempotencyKeyInProgress
+	}
+	return e.Response, nil
+}
+
+// Complete is a method that stores the response of a begun key for the ttl of the store
+func (s *IdempotencyMap) Complete(key string, r internal.IdempotentResponse) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok {
+		e.Response = &r
+		e.ExpiresAt = s.now().Add(s.ttl)
+	}
+	return
+}
+
+// Release is a method that forgets a begun key, so the request can be retried
+func (s *IdempotencyMap) Release(key string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.Response == nil {
+		delete(s.entries, key)
+	}
+	return
+}
+
+// sweep is a method that forgets the expired keys
+func (s *IdempotencyMap) sweep(now time.Time) {
+	s.swept = now
+	for key, e := range s.entries {
+		if !now.Before(e.ExpiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"app/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for IdempotencyMap
+func TestIdempotencyMap(t *testing.T) {
+	// newStore is a function that returns a store whose clock is moved by the returned function
+	newStore := func() (*IdempotencyMap, func(d time.Duration)) {
+		st := NewIdempotencyMap(time.Hour)
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		st.now = func() time.Time { return now }
+		return st, func(d time.Duration) { now = now.Add(d) }
+	}
+
+	t.Run("case 1: begin, in progress, then the stored response", func(t *testing.T) {
+		// arrange
+		st, _ := newStore()
+
+		// act
+		first, errFirst := st.Begin("k", "f")
+		_, errRunning := st.Begin("k", "f")
+		require.NoError(t, st.Complete("k", internal.IdempotentResponse{Status: 201, Body: []byte("ok")}))
+		replay, errReplay := st.Begin("k", "f")
+
+		// assert
+		require.NoError(t, errFirst)
+		require.Nil(t, first)
+		require.ErrorIs(t, errRunning, internal.ErrIdempotencyKeyInProgress)
+		require.NoError(t, errReplay)
+		require.Equal(t, &internal.IdempotentResponse{Status: 201, Body: []byte("ok")}, replay)
+	})
+
+	t.Run("case 2: key reused with another fingerprint", func(t *testing.T) {
+		// arrange
+		st, _ := newStore()
+		st.Begin("k", "f")
+
+		// act
+		_, err := st.Begin("k", "other")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("case 3: released and expired keys can be begun again", func(t *testing.T) {
+		// arrange
+		st, advance := newStore()
+		st.Begin("released", "f")
+		st.Begin("expired", "f")
+		st.Complete("expired", internal.IdempotentResponse{Status: 201})
+
+		// act
+		require.NoError(t, st.Release("released"))
+		released, errReleased := st.Begin("released", "other")
+		advance(time.Hour)
+		expired, errExpired := st.Begin("expired", "other")
+
+		// assert
+		require.NoError(t, errReleased)
+		require.Nil(t, released)
+		require.NoError(t, errExpired)
+		require.Nil(t, expired)
+	})
+}
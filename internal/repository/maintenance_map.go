@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"app/internal"
+	"sort"
+	"sync"
+)
+
+// NewMaintenanceMap is a function that returns a new instance of MaintenanceMap
+func NewMaintenanceMap() *MaintenanceMap {
+	return &MaintenanceMap{
+		vehicles: make(map[int][]int),
+	}
+}
+
+// MaintenanceMap is a struct that implements the MaintenanceRepository interface in memory
+type MaintenanceMap struct {
+	// mu is the mutex that guards the records
+	mu sync.RWMutex
+	// records are the records in the order they were added, the id of each one is its position + 1
+	records []internal.MaintenanceRecord
+	// vehicles is a map of the positions of the records of each vehicle, oldest first
+	vehicles map[int][]int
+}
+
+// FindAll is a method that returns every record, oldest first
+func (r *MaintenanceMap) FindAll() (records []internal.MaintenanceRecord, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records = make([]internal.MaintenanceRecord, len(r.records))
+	copy(records, r.records)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Date.Before(records[j].Date)
+	})
+	return
+}
+
+// FindByVehicle is a method that returns the records of a vehicle, oldest first
+func (r *MaintenanceMap) FindByVehicle(vehicleId int) (records []internal.MaintenanceRecord, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records = make([]internal.MaintenanceRecord, 0, len(r.vehicles[vehicleId]))
+	for _, i := range r.vehicles[vehicleId] {
+		records = append(records, r.records[i])
+	}
+	return
+}
+
+// Add is a method that adds a record and returns its id
+// - the records of each vehicle are kept by date, the ones of the same day by odometer
+func (r *MaintenanceMap) Add(record internal.MaintenanceRecord) (id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record.Id = len(r.records) + 1
+	r.records = append(r.records, record)
+
+	positions := append(r.vehicles[record.VehicleId], record.Id-1)
+	sort.SliceStable(positions, func(i, j int) bool {
+		a, b := r.records[positions[i]], r.records[positions[j]]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.Odometer < b.Odometer
+	})
+	r.vehicles[record.VehicleId] = positions
+
+	return record.Id, nil
+}
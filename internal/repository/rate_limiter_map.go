@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"app/internal"
+	"container/list"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateKeys is the number of buckets kept at most, the least recently used one is evicted to make room
+	// - an evicted client starts again with a full budget and a new quota
+	maxRateKeys = 10000
+	// rateSweepInterval is the time between two sweeps of the buckets that are as good as new
+	rateSweepInterval = time.Minute
+)
+
+// NewRateLimiterMap is a function that returns a new instance of RateLimiterMap
+func NewRateLimiterMap(limits internal.RateLimits) (rl *RateLimiterMap, err error) {
+	for class, b := range limits.Budgets {
+		if b.PerSecond <= 0 || b.Burst < 1 || b.DailyQuota < 0 {
+			return nil, fmt.Errorf("%w: %s: per second and burst must be positive", internal.ErrInvalidRateLimits, class)
+		}
+	}
+
+	rl = &RateLimiterMap{
+		budgets: limits.Budgets,
+		buckets: make(map[rateKey]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+	return
+}
+
+// rateKey is the key of the bucket of a client for a class of requests
+type rateKey struct {
+	client string
+	class  internal.RateClass
+}
+
+// rateBucket is a struct that represents the state of a token bucket and its daily quota
+type rateBucket struct {
+	// key is the client and the class of the bucket
+	key rateKey
+	// tokens are the tokens left at last
+	tokens float64
+	// last is the moment the tokens were computed
+	last time.Time
+	// day is the UTC day of the used quota
+	day time.Time
+	// used is the number of requests made in the day
+	used int
+}
+
+// RateLimiterMap is a struct that implements the RateLimiter interface in memory
+// - the budgets are per instance, a shared store is needed to limit several instances together
+type RateLimiterMap struct {
+	// mu is the mutex that guards the buckets
+	mu sync.Mutex
+	// budgets is a map of the budget of each class
+	budgets map[internal.RateClass]internal.RateBudget
+	// buckets is a map of the element of the bucket of each client and class in lru
+	buckets map[rateKey]*list.Element
+	// lru is the list of the buckets, the most recently used first
+	lru *list.List
+	// swept is the moment of the last sweep
+	swept time.Time
+	// now is the clock of the buckets
+	now func() time.Time
+}
+
+// Allow is a method that takes a request of a class from the budget of a client key
+func (r *RateLimiterMap) Allow(key string, class internal.RateClass) (d internal.RateDecision, err error) {
+	return r.decide(key, class, true)
+}
+
+// Peek is a method that reports if a request of a class would be allowed, without taking it from the budget
+func (r *RateLimiterMap) Peek(key string, class internal.RateClass) (d internal.RateDecision, err error) {
+	return r.decide(key, class, false)
+}
+
+// decide is a method that refills the budget of a client key and takes a request of a class from it if take is set
+func (r *RateLimiterMap) decide(key string, class internal.RateClass, take bool) (d internal.RateDecision, err error) {
+	budget, ok := r.budgets[class]
+	if !ok {
+		d.Allowed = true
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	today := now.UTC().Truncate(24 * time.Hour)
+	if now.Sub(r.swept) >= rateSweepInterval {
+		r.sweep(now, today)
+		r.swept = now
+	}
+
+	// refill
+	k := rateKey{client: key, class: class}
+	e, ok := r.buckets[k]
+	if ok {
+		r.lru.MoveToFront(e)
+	} else {
+		if r.lru.Len() >= maxRateKeys {
+			r.evict(r.lru.Back())
+		}
+		e = r.lru.PushFront(&rateBucket{key: k, tokens: float64(budget.Burst), last: now, day: today})
+		r.buckets[k] = e
+	}
+	b := e.Value.(*rateBucket)
+	b.tokens = math.Min(float64(budget.Burst), b.tokens+now.Sub(b.last).Seconds()*budget.PerSecond)
+	b.last = now
+	if !b.day.Equal(today) {
+		b.day, b.used = today, 0
+	}
+
+	// take
+	quotaLeft := budget.DailyQuota == 0 || b.used < budget.DailyQuota
+	d.Limited = true
+	d.Allowed = b.tokens >= 1 && quotaLeft
+	if d.Allowed && take {
+		b.tokens--
+		b.used++
+	}
+
+	// report the most restrictive window
+	d.Limit = budget.Burst
+	d.Remaining = int(b.tokens)
+	d.Reset = seconds((float64(budget.Burst) - b.tokens) / budget.PerSecond)
+	if !d.Allowed {
+		d.RetryAfter = seconds((1 - b.tokens) / budget.PerSecond)
+	}
+	if budget.DailyQuota != 0 && budget.DailyQuota-b.used < d.Remaining {
+		d.Limit = budget.DailyQuota
+		d.Remaining = budget.DailyQuota - b.used
+		d.Reset = today.Add(24 * time.Hour).Sub(now)
+		if !quotaLeft {
+			d.RetryAfter = d.Reset
+		}
+	}
+
+	return
+}
+
+// sweep is a method that forgets the clients whose bucket is full and whose quota is of another day
+func (r *RateLimiterMap) sweep(now time.Time, today time.Time) {
+	for e := r.lru.Back(); e != nil; {
+		prev, b := e.Prev(), e.Value.(*rateBucket)
+		budget := r.budgets[b.key.class]
+		full := b.tokens+now.Sub(b.last).Seconds()*budget.PerSecond >= float64(budget.Burst)
+		if full && (budget.DailyQuota == 0 || !b.day.Equal(today)) {
+			r.evict(e)
+		}
+		e = prev
+	}
+}
+
+// evict is a method that forgets the bucket of an element of lru
+func (r *RateLimiterMap) evict(e *list.Element) {
+	delete(r.buckets, e.Value.(*rateBucket).key)
+	r.lru.Remove(e)
+}
+
+// seconds is a function that returns a duration of seconds, never negative
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
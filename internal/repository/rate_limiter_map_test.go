@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"app/internal"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for RateLimiterMap
+func TestRateLimiterMap_Allow(t *testing.T) {
+	// newLimiter is a function that returns a limiter whose clock is moved by the returned function
+	newLimiter := func(t *testing.T, budget internal.RateBudget) (*RateLimiterMap, func(d time.Duration)) {
+		rl, err := NewRateLimiterMap(internal.RateLimits{Budgets: map[internal.RateClass]internal.RateBudget{internal.RateClassWrite: budget}})
+		require.NoError(t, err)
+		now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+		rl.now = func() time.Time { return now }
+		return rl, func(d time.Duration) { now = now.Add(d) }
+	}
+
+	t.Run("case 1: burst, then refill at the rate", func(t *testing.T) {
+		// arrange
+		rl, advance := newLimiter(t, internal.RateBudget{PerSecond: 1, Burst: 3})
+
+		// act
+		var allowed int
+		for i := 0; i < 5; i++ {
+			if d, _ := rl.Allow("ip:1.2.3.4", internal.RateClassWrite); d.Allowed {
+				allowed++
+			}
+		}
+		denied, _ := rl.Allow("ip:1.2.3.4", internal.RateClassWrite)
+		advance(1500 * time.Millisecond)
+		refilled, _ := rl.Allow("ip:1.2.3.4", internal.RateClassWrite)
+
+		// assert
+		require.Equal(t, 3, allowed)
+		require.False(t, denied.Allowed)
+		require.Equal(t, time.Second, denied.RetryAfter)
+		require.Equal(t, 3*time.Second, denied.Reset)
+		require.True(t, refilled.Allowed)
+		require.Equal(t, 0, refilled.Remaining)
+	})
+
+	t.Run("case 2: daily quota until the next UTC day", func(t *testing.T) {
+		// arrange
+		rl, advance := newLimiter(t, internal.RateBudget{PerSecond: 100, Burst: 100, DailyQuota: 2})
+
+		// act
+		first, _ := rl.Allow("api_key:/importer", internal.RateClassWrite)
+		rl.Allow("api_key:/importer", internal.RateClassWrite)
+		exhausted, _ := rl.Allow("api_key:/importer", internal.RateClassWrite)
+		advance(time.Hour)
+		nextDay, _ := rl.Allow("api_key:/importer", internal.RateClassWrite)
+
+		// assert
+		require.Equal(t, 2, first.Limit)
+		require.Equal(t, 1, first.Remaining)
+		require.False(t, exhausted.Allowed)
+		require.Equal(t, time.Hour, exhausted.RetryAfter)
+		require.True(t, nextDay.Allowed)
+	})
+
+	t.Run("case 3: clients and classes have their own budgets", func(t *testing.T) {
+		// arrange
+		rl, _ := newLimiter(t, internal.RateBudget{PerSecond: 1, Burst: 1})
+
+		// act
+		a, _ := rl.Allow("ip:1.1.1.1", internal.RateClassWrite)
+		b, _ := rl.Allow("ip:2.2.2.2", internal.RateClassWrite)
+		read, _ := rl.Allow("ip:1.1.1.1", internal.RateClassRead)
+
+		// assert
+		require.True(t, a.Allowed)
+		require.True(t, b.Allowed)
+		require.True(t, read.Allowed)
+		require.False(t, read.Limited)
+	})
+
+	t.Run("case 4: peeks do not take from the budget", func(t *testing.T) {
+		// arrange
+		rl, _ := newLimiter(t, internal.RateBudget{PerSecond: 1, Burst: 1})
+
+		// act
+		before, _ := rl.Peek("ip:1.1.1.1", internal.RateClassWrite)
+		again, _ := rl.Peek("ip:1.1.1.1", internal.RateClassWrite)
+		taken, _ := rl.Allow("ip:1.1.1.1", internal.RateClassWrite)
+		after, _ := rl.Peek("ip:1.1.1.1", internal.RateClassWrite)
+
+		// assert
+		require.True(t, before.Allowed)
+		require.True(t, again.Allowed)
+		require.True(t, taken.Allowed)
+		require.False(t, after.Allowed)
+		require.Equal(t, time.Second, after.RetryAfter)
+	})
+
+	t.Run("case 5: the buckets are capped, the least recently used one is evicted", func(t *testing.T) {
+		// arrange
+		rl, _ := newLimiter(t, internal.RateBudget{PerSecond: 1, Burst: 1})
+		for i := 0; i < maxRateKeys; i++ {
+			rl.Allow(fmt.Sprintf("ip:%d", i), internal.RateClassWrite)
+		}
+
+		// act
+		recent, _ := rl.Allow("ip:0", internal.RateClassWrite)
+		rl.Allow("ip:flood", internal.RateClassWrite)
+
+		// assert
+		require.False(t, recent.Allowed)
+		require.Len(t, rl.buckets, maxRateKeys)
+		require.Equal(t, maxRateKeys, rl.lru.Len())
+		require.Contains(t, rl.buckets, rateKey{client: "ip:0", class: internal.RateClassWrite})
+		require.NotContains(t, rl.buckets, rateKey{client: "ip:1", class: internal.RateClassWrite})
+	})
+
+	t.Run("case 6: the buckets as good as new are swept on an interval", func(t *testing.T) {
+		// arrange
+		rl, advance := newLimiter(t, internal.RateBudget{PerSecond: 1, Burst: 1})
+		rl.Allow("ip:1.1.1.1", internal.RateClassWrite)
+		rl.Allow("ip:2.2.2.2", internal.RateClassWrite)
+
+		// act
+		advance(time.Second)
+		rl.Allow("ip:2.2.2.2", internal.RateClassWrite)
+		early := len(rl.buckets)
+		advance(rateSweepInterval)
+		rl.Allow("ip:3.3.3.3", internal.RateClassWrite)
+
+		// assert
+		require.Equal(t, 2, early)
+		require.Len(t, rl.buckets, 1)
+		require.Contains(t, rl.buckets, rateKey{client: "ip:3.3.3.3", class: internal.RateClassWrite})
+	})
+
+	t.Run("case 7: invalid budget", func(t *testing.T) {
+		// act
+		_, err := NewRateLimiterMap(internal.RateLimits{Budgets: map[internal.RateClass]internal.RateBudget{internal.RateClassRead: {Burst: 1}}})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidRateLimits)
+	})
+}
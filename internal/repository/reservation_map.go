@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"app/internal"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// NewReservationMap is a function that returns a new instance of ReservationMap
+func NewReservationMap() *ReservationMap {
+	return &ReservationMap{
+		vehicles: make(map[int][]int),
+	}
+}
+
+// ReservationMap is a struct that implements the ReservationRepository interface in memory
+// - the reservations are never removed, the canceled ones are the history
+type ReservationMap struct {
+	// mu is the mutex that guards the reservations, the check of the overlaps and the addition are atomic
+	mu sync.RWMutex
+	// reservations are the reservations in the order they were made, the id of each one is its position + 1
+	reservations []internal.Reservation
+	// vehicles is a map of the positions of the reservations of each vehicle, by start
+	vehicles map[int][]int
+}
+
+// FindById is a method that returns a reservation by id
+func (r *ReservationMap) FindById(id int) (rs internal.Reservation, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id < 1 || id > len(r.reservations) {
+		return rs, fmt.Errorf("%w: %d", internal.ErrReservationNotFound, id)
+	}
+	return r.reservations[id-1], nil
+}
+
+// FindByVehicle is a method that returns the reservations of a vehicle, canceled or not, by start
+func (r *ReservationMap) FindByVehicle(vehicleId int) (rs []internal.Reservation, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rs = make([]internal.Reservation, 0, len(r.vehicles[vehicleId]))
+	for _, i := range r.vehicles[vehicleId] {
+		rs = append(rs, r.reservations[i])
+	}
+	return
+}
+
+// FindOverlapping is a method that returns the active reservations that overlap with a period [from, to)
+func (r *ReservationMap) FindOverlapping(from time.Time, to time.Time) (rs []internal.Reservation, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rs = make([]internal.Reservation, 0)
+	for _, value := range r.reservations {
+		if value.Overlaps(from, to) {
+			rs = append(rs, value)
+		}
+	}
+	return
+}
+
+// Reserve is a method that adds a reservation and returns its id, it cannot overlap with the active ones of its vehicle
+func (r *ReservationMap) Reserve(rs internal.Reservation) (id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, i := range r.vehicles[rs.VehicleId] {
+		if r.reservations[i].Overlaps(rs.From, rs.To) {
+			return 0, fmt.Errorf("%w: reservation %d", internal.ErrReservationConflict, r.reservations[i].Id)
+		}
+	}
+
+	rs.Id = len(r.reservations) + 1
+	rs.CanceledAt = nil
+	r.reservations = append(r.reservations, rs)
+
+	positions := append(r.vehicles[rs.VehicleId], rs.Id-1)
+	sort.SliceStable(positions, func(i, j int) bool {
+		return r.reservations[positions[i]].From.Before(r.reservations[positions[j]].From)
+	})
+	r.vehicles[rs.VehicleId] = positions
+
+	return rs.Id, nil
+}
+
+// Cancel is a method that cancels a reservation at a moment and returns it
+// - canceling a canceled reservation returns it as it was
+func (r *ReservationMap) Cancel(id int, at time.Time) (rs internal.Reservation, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > len(r.reservations) {
+		return rs, fmt.Errorf("%w: %d", internal.ErrReservationNotFound, id)
+	}
+	if r.reservations[id-1].IsActive() {
+		r.reservations[id-1].CanceledAt = &at
+	}
+	return r.reservations[id-1], nil
+}
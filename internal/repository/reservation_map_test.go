@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"app/internal"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ReservationMap
+func TestReservationMap_Reserve(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2024, 3, 1, hour, 0, 0, 0, time.UTC) }
+
+	t.Run("case 1: overlapping reservations of a vehicle are rejected", func(t *testing.T) {
+		// arrange
+		rp := NewReservationMap()
+
+		// act
+		id, err := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "jane", From: at(8), To: at(12)})
+		_, errOverlap := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(11), To: at(14)})
+		_, errInside := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(9), To: at(10)})
+		_, errAfter := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(12), To: at(14)})
+		_, errBefore := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(6), To: at(8)})
+		_, errOther := rp.Reserve(internal.Reservation{VehicleId: 2, Holder: "john", From: at(8), To: at(12)})
+		reservations, _ := rp.FindByVehicle(1)
+
+		// assert: the periods exclude their end
+		require.NoError(t, err)
+		require.Equal(t, 1, id)
+		require.ErrorIs(t, errOverlap, internal.ErrReservationConflict)
+		require.ErrorIs(t, errInside, internal.ErrReservationConflict)
+		require.NoError(t, errAfter)
+		require.NoError(t, errBefore)
+		require.NoError(t, errOther)
+		require.Len(t, reservations, 3)
+		require.Equal(t, at(6), reservations[0].From)
+		require.Equal(t, at(8), reservations[1].From)
+	})
+
+	t.Run("case 2: canceled reservations free their period", func(t *testing.T) {
+		// arrange
+		rp := NewReservationMap()
+		id, _ := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "jane", From: at(8), To: at(12)})
+
+		// act
+		canceled, err := rp.Cancel(id, at(7))
+		again, errAgain := rp.Cancel(id, at(9))
+		_, errReserve := rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(8), To: at(12)})
+		overlapping, _ := rp.FindOverlapping(at(9), at(10))
+		_, errUnknown := rp.Cancel(3, at(9))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, at(7), *canceled.CanceledAt)
+		require.NoError(t, errAgain)
+		require.Equal(t, at(7), *again.CanceledAt)
+		require.NoError(t, errReserve)
+		require.Len(t, overlapping, 1)
+		require.Equal(t, "john", overlapping[0].Holder)
+		require.ErrorIs(t, errUnknown, internal.ErrReservationNotFound)
+	})
+
+	t.Run("case 3: concurrent bookings of the same period, only one succeeds", func(t *testing.T) {
+		// arrange
+		rp := NewReservationMap()
+		var wg sync.WaitGroup
+		errs := make([]error, 50)
+
+		// act
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = rp.Reserve(internal.Reservation{VehicleId: 1, Holder: "holder", From: at(8 + i%3), To: at(12)})
+			}(i)
+		}
+		wg.Wait()
+
+		// assert
+		var succeeded int
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+				continue
+			}
+			require.ErrorIs(t, err, internal.ErrReservationConflict)
+		}
+		require.Equal(t, 1, succeeded)
+	})
+}
@@ -1,6 +1,13 @@
 package repository
 
-import "app/internal"
+import (
+	"app/internal"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
 
 // NewVehicleMap is a function that returns a new instance of VehicleMap
 func NewVehicleMap(db map[int]internal.Vehicle) *VehicleMap {
@@ -9,48 +16,266 @@ func NewVehicleMap(db map[int]internal.Vehicle) *VehicleMap {
 	if db != nil {
 		defaultDb = db
 	}
-	return &VehicleMap{db: defaultDb}
+
+	rp := &VehicleMap{
+		db:            defaultDb,
+		history:       make(map[int][]version),
+		registrations: make(map[string][]int),
+		vins:          make(map[string][]int),
+		search:        newSearchIndex(),
+		now:           time.Now,
+	}
+
+	// the loaded vehicles are valid since the beginning of time
+	for key, value := range defaultDb {
+		vh := value
+		rp.history[key] = []version{{vehicle: &vh}}
+		rp.index(value)
+		rp.search.add(value)
+	}
+
+	return rp
 }
 
 // VehicleMap is a struct that represents a vehicle repository
 type VehicleMap struct {
-	// db is a map of vehicles
+	// mu is the mutex that guards the db and the history
+	mu sync.RWMutex
+	// db is a map of the current vehicles
 	db map[int]internal.Vehicle
+	// history is a map of the versions of each vehicle, sorted by time
+	history map[int][]version
+	// registrations is a map of the ids holding each normalized registration
+	// - soft deleted vehicles keep their registration until purged
+	// - more than one id only happens with duplicated loaded data
+	registrations map[string][]int
+	// vins is a map of the ids holding each normalized VIN, with the same rules as the registrations
+	vins map[string][]int
+	// search is the full text index of the current vehicles
+	search *searchIndex
+	// similar is the nearest neighbour tree of the current vehicles, nil until a search needs it again
+	// - writers clear it holding mu, readers build it holding mu for reading and similarMu
+	similar *similarIndex
+	// similarMu is the mutex that guards the lazy build of similar
+	similarMu sync.Mutex
+	// now is the clock used to timestamp the versions
+	now func() time.Time
+}
+
+// put is a method that stores a new version of a vehicle
+func (r *VehicleMap) put(v internal.Vehicle) {
+	var prev *internal.Vehicle
+	if old, ok := r.db[v.Id]; ok {
+		r.unindex(old)
+		prev = &old
+	}
+	r.index(v)
+	r.search.add(v)
+	r.similar = nil
+
+	r.db[v.Id] = v
+	r.record(prev, v)
+}
+
+// remove is a method that hard deletes the current record of a vehicle
+// - its past versions are kept, so the point-in-time reads still see it at the moments it existed
+func (r *VehicleMap) remove(id int) {
+	if old, ok := r.db[id]; ok {
+		r.unindex(old)
+	}
+
+	r.search.remove(id)
+	r.similar = nil
+
+	delete(r.db, id)
+}
+
+// index is a method that adds a vehicle to the unique key indexes
+func (r *VehicleMap) index(v internal.Vehicle) {
+	indexKey(r.registrations, internal.NormalizeRegistration(v.Registration), v.Id)
+	indexKey(r.vins, internal.NormalizeVIN(v.VIN), v.Id)
+}
+
+// unindex is a method that removes a vehicle from the unique key indexes
+func (r *VehicleMap) unindex(v internal.Vehicle) {
+	unindexKey(r.registrations, internal.NormalizeRegistration(v.Registration), v.Id)
+	unindexKey(r.vins, internal.NormalizeVIN(v.VIN), v.Id)
+}
+
+// indexKey is a function that adds an id to the holders of a key
+func indexKey(idx map[string][]int, key string, id int) {
+	if key == "" {
+		return
+	}
+	idx[key] = append(idx[key], id)
+}
+
+// unindexKey is a function that removes an id from the holders of a key
+func unindexKey(idx map[string][]int, key string, id int) {
+	ids := idx[key]
+	for i, holder := range ids {
+		if holder == id {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	if len(ids) == 0 {
+		delete(idx, key)
+		return
+	}
+	idx[key] = ids
+}
+
+// keyTaken is a function that reports if a key is held by a vehicle other than id
+func keyTaken(idx map[string][]int, key string, id int) bool {
+	for _, holder := range idx[key] {
+		if holder != id {
+			return true
+		}
+	}
+	return false
+}
+
+// registrationTaken is a method that reports if a registration is held by a vehicle other than id
+func (r *VehicleMap) registrationTaken(registration string, id int) bool {
+	return keyTaken(r.registrations, internal.NormalizeRegistration(registration), id)
+}
+
+// vinTaken is a method that reports if a VIN is held by a vehicle other than id
+func (r *VehicleMap) vinTaken(vin string, id int) bool {
+	return keyTaken(r.vins, internal.NormalizeVIN(vin), id)
+}
+
+// findByKey is a method that returns the only visible vehicle holding a unique key
+// - the index answers for the current state, past states are scanned
+// - ambiguous is returned when several visible vehicles hold the key
+func (r *VehicleMap) findByKey(idx map[string][]int, key string, keyOf func(v internal.Vehicle) string, ambiguous error, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	var found []internal.Vehicle
+	if q.AsOf.IsZero() {
+		for _, id := range idx[key] {
+			found = append(found, r.db[id])
+		}
+	} else {
+		for _, value := range r.state(q) {
+			if keyOf(value) == key {
+				found = append(found, value)
+			}
+		}
+	}
+
+	var visibles []internal.Vehicle
+	for _, value := range found {
+		if visible(value, q) {
+			visibles = append(visibles, value)
+		}
+	}
+
+	switch len(visibles) {
+	case 0:
+		return internal.Vehicle{}, internal.ErrorVehicleNotFound
+	case 1:
+		return visibles[0], nil
+	default:
+		return internal.Vehicle{}, ambiguous
+	}
+}
+
+// visible is a function that reports if a vehicle is visible for a query
+func visible(v internal.Vehicle, q internal.VehicleQuery) bool {
+	return v.DeletedAt == nil || q.IncludeDeleted
 }
 
 // FindAll is a method that returns a map of all vehicles
-func (r *VehicleMap) FindAll() (v map[int]internal.Vehicle, err error) {
+func (r *VehicleMap) FindAll(q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	v = make(map[int]internal.Vehicle)
 
 	// copy db
-	for key, value := range r.db {
+	for key, value := range r.state(q) {
+		if !visible(value, q) {
+			continue
+		}
 		v[key] = value
 	}
 
 	return
 }
 
+// FindById is a method that returns a vehicle by id GET /vehicles/{id}
+func (r *VehicleMap) FindById(id int, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.state(q)[id]
+
+	if !ok || !visible(v, q) {
+		return internal.Vehicle{}, internal.ErrorVehicleNotFound
+	}
+
+	return v, nil
+}
+
+// FindByRegistration is a method that returns a vehicle by its normalized registration GET /vehicles/registration/{registration}
+func (r *VehicleMap) FindByRegistration(registration string, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findByKey(r.registrations, internal.NormalizeRegistration(registration), func(v internal.Vehicle) string {
+		return internal.NormalizeRegistration(v.Registration)
+	}, internal.ErrorRegistrationAmbiguous, q)
+}
+
+// FindByVIN is a method that returns a vehicle by its normalized VIN GET /vehicles/vin/{vin}
+func (r *VehicleMap) FindByVIN(vin string, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findByKey(r.vins, internal.NormalizeVIN(vin), func(v internal.Vehicle) string {
+		return internal.NormalizeVIN(v.VIN)
+	}, internal.ErrorVINAmbiguous, q)
+}
+
 //Add is a method that adds a vehicle //Exercise 1 POST /vehicles
 func (r *VehicleMap) Add(v internal.Vehicle) (err error){
-	
-	// check if vehicle already exists
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check if vehicle already exists (soft deleted vehicles keep their id until purged)
 	_, ok := r.db[v.Id]
 
 	if ok {
 		return internal.ErrorVehicleAlreadyExists
 	}
 
+	// check if registration already exists
+	if r.registrationTaken(v.Registration, v.Id) {
+		return internal.ErrorRegistrationAlreadyExists
+	}
+
+	// check if vin already exists
+	if r.vinTaken(v.VIN, v.Id) {
+		return internal.ErrorVINAlreadyExists
+	}
+
 	// add vehicle
-	r.db[v.Id] = v
+	r.put(v)
 
 	return 
 }
 
 //Search vehicles by color and year //Exercise 2 GET /vehicles/color/{color}/year/{year}
-func (r *VehicleMap) SearchByColorAndYear(color string, year int) (v []internal.Vehicle, err error) {
+func (r *VehicleMap) SearchByColorAndYear(color string, year int, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, value := range r.db {
-		if value.Color == color && value.FabricationYear == year {
+	for _, value := range r.state(q) {
+		if !visible(value, q) {
+			continue
+		}
+		if strings.EqualFold(value.Color, color) && value.FabricationYear == year {
 			v = append(v, value)
 		}
 	}
@@ -63,10 +288,15 @@ func (r *VehicleMap) SearchByColorAndYear(color string, year int) (v []internal.
 }
 
 //Search vehicles by brand and year range //Exercise 3 GET /vehicles/brand/{brand}/between/{start_year}/{end_year}
-func (r *VehicleMap) SearchByBrand(brand string, start_year int, end_year int) (v []internal.Vehicle, err error) {
+func (r *VehicleMap) SearchByBrand(brand string, start_year int, end_year int, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, value := range r.db{
-		if value.Brand == brand && value.FabricationYear >= start_year && value.FabricationYear <= end_year {
+	for _, value := range r.state(q){
+		if !visible(value, q) {
+			continue
+		}
+		if strings.EqualFold(value.Brand, brand) && value.FabricationYear >= start_year && value.FabricationYear <= end_year {
 			v = append(v, value)
 		}
 	}
@@ -79,12 +309,17 @@ func (r *VehicleMap) SearchByBrand(brand string, start_year int, end_year int) (
 }
 
 //Get average speed by brand //Exercise 4 GET /vehicles/average_speed/brand/{brand}
-func (r *VehicleMap) GetAverageSpeedByBrand(brand string) (avgSpeed float64, err error) {
+func (r *VehicleMap) GetAverageSpeedByBrand(brand string, q internal.VehicleQuery) (avgSpeed float64, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
 	var size float64
 
-	for _, value := range r.db {
-		if value.Brand == brand {
+	for _, value := range r.state(q) {
+		if !visible(value, q) {
+			continue
+		}
+		if strings.EqualFold(value.Brand, brand) {
 			avgSpeed += value.MaxSpeed
 			size++
 		}
@@ -99,28 +334,56 @@ func (r *VehicleMap) GetAverageSpeedByBrand(brand string) (avgSpeed float64, err
 
 //Add multiple vehicles //Exercise 5 POST /vehicles/batch
 func (r *VehicleMap) AddMultiple(vehicles []internal.Vehicle) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// check the whole batch before adding anything
+	ids := make(map[int]bool)
+	registrations := make(map[string]bool)
+	vins := make(map[string]bool)
 	for _, value := range vehicles {
 
 		// check if vehicle already exists
 		_, ok := r.db[value.Id]
 
-		if ok {
+		if ok || ids[value.Id] {
 			return internal.ErrorVehicleAlreadyExists
 		}
+		ids[value.Id] = true
 
-		r.db[value.Id] = value
+		// check if registration already exists
+		key := internal.NormalizeRegistration(value.Registration)
+
+		if r.registrationTaken(key, value.Id) || (key != "" && registrations[key]) {
+			return internal.ErrorRegistrationAlreadyExists
+		}
+		registrations[key] = true
+
+		// check if vin already exists
+		vin := internal.NormalizeVIN(value.VIN)
+
+		if r.vinTaken(vin, value.Id) || (vin != "" && vins[vin]) {
+			return internal.ErrorVINAlreadyExists
+		}
+		vins[vin] = true
 
 	}
+
+	for _, value := range vehicles {
+		r.put(value)
+	}
 	return nil
 }
 
 //Update max speed by id //Exercise 6 PUT /vehicles/{id}/update_speed
 func (r *VehicleMap) UpdateMaxSpeedById(id int, maxSpeed float64) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if entry, ok := r.db[id]; ok {
+	if entry, ok := r.db[id]; ok && entry.DeletedAt == nil {
 
 		entry.MaxSpeed = maxSpeed
-		r.db[id] = entry
+		r.put(entry)
 		return nil
 
 	}
@@ -129,11 +392,17 @@ func (r *VehicleMap) UpdateMaxSpeedById(id int, maxSpeed float64) (err error) {
 }
 
 //Search vehicles by fuel_type //Exercise 7 GET /vehicles/fuel_type/{fuel_type}
-func (r *VehicleMap) GetVehiclesByFuelType(fuelType string) (v []internal.Vehicle, err error) {
+func (r *VehicleMap) GetVehiclesByFuelType(fuelType string, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, value := range r.db {
+	for _, value := range r.state(q) {
+
+		if !visible(value, q) {
+			continue
+		}
 
-		if value.FuelType == fuelType {
+		if strings.EqualFold(value.FuelType, fuelType) {
 
 			v = append(v, value)
 
@@ -148,12 +417,16 @@ func (r *VehicleMap) GetVehiclesByFuelType(fuelType string) (v []internal.Vehicl
 	return v, nil
 }
 
-//Delete a vehicle by id //Exercise 8 DELETE /vehicles/{id}
+//Soft delete a vehicle by id //Exercise 8 DELETE /vehicles/{id}
 func (r *VehicleMap) DeleteById(id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if _, ok := r.db[id]; ok {
+	if entry, ok := r.db[id]; ok && entry.DeletedAt == nil {
 
-		delete(r.db, id)
+		now := r.now()
+		entry.DeletedAt = &now
+		r.put(entry)
 		return nil
 
 	}
@@ -161,12 +434,56 @@ func (r *VehicleMap) DeleteById(id int) (err error) {
 	return internal.ErrorVehicleNotFound
 }
 
+//Restore a soft deleted vehicle by id POST /vehicles/{id}/restore
+func (r *VehicleMap) RestoreById(id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.db[id]
+
+	if !ok {
+		return internal.ErrorVehicleNotFound
+	}
+
+	if entry.DeletedAt == nil {
+		return internal.ErrorVehicleNotDeleted
+	}
+
+	entry.DeletedAt = nil
+	r.put(entry)
+
+	return nil
+}
+
+//Hard delete the vehicles soft deleted before a moment
+func (r *VehicleMap) PurgeDeleted(before time.Time) (purged int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, value := range r.db {
+
+		if value.DeletedAt != nil && value.DeletedAt.Before(before) {
+			r.remove(key)
+			purged++
+		}
+
+	}
+
+	return purged, nil
+}
+
 //Search vehicles by transmission type //Exercise 9 GET /vehicles/transmission/{transmission}
-func (r *VehicleMap) GetVehiclesByTransmission(transmission string) (v []internal.Vehicle, err error) {
+func (r *VehicleMap) GetVehiclesByTransmission(transmission string, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, value := range r.db {
+	for _, value := range r.state(q) {
+
+		if !visible(value, q) {
+			continue
+		}
 
-		if value.Transmission == transmission {
+		if strings.EqualFold(value.Transmission, transmission) {
 			v = append(v, value)
 		}
 
@@ -181,11 +498,13 @@ func (r *VehicleMap) GetVehiclesByTransmission(transmission string) (v []interna
 
 //Update fuel type by id //Exercise 10 PUT /vehicles/{id}/update_fuel
 func (r *VehicleMap) UpdateFuelTypeById(id int, fuelType string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if entry, ok := r.db[id]; ok {
+	if entry, ok := r.db[id]; ok && entry.DeletedAt == nil {
 
 		entry.FuelType = fuelType
-		r.db[id] = entry
+		r.put(entry)
 		return nil
 
 	}
@@ -193,14 +512,83 @@ func (r *VehicleMap) UpdateFuelTypeById(id int, fuelType string) (err error) {
 	return internal.ErrorVehicleNotFound
 }
 
+// UpdateDepotById is a method that changes the depot that owns a vehicle
+func (r *VehicleMap) UpdateDepotById(id int, depotId int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.db[id]
+
+	if !ok || entry.DeletedAt != nil {
+		return internal.ErrorVehicleNotFound
+	}
+
+	entry.DepotId = depotId
+	r.put(entry)
+
+	return nil
+}
+
+//Replace the attributes of a vehicle by id, deleted or not, keeping the unique keys unique
+func (r *VehicleMap) ReplaceById(v internal.Vehicle) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.db[v.Id]
+
+	if !ok {
+		return internal.ErrorVehicleNotFound
+	}
+
+	if r.registrationTaken(v.Registration, v.Id) {
+		return internal.ErrorRegistrationAlreadyExists
+	}
+
+	if r.vinTaken(v.VIN, v.Id) {
+		return internal.ErrorVINAlreadyExists
+	}
+
+	entry.VehicleAttributes = v.VehicleAttributes
+	r.put(entry)
+
+	return nil
+}
+
+//Update registration by id PUT /vehicles/{id}/update_registration
+func (r *VehicleMap) UpdateRegistrationById(id int, registration string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.db[id]
+
+	if !ok || entry.DeletedAt != nil {
+		return internal.ErrorVehicleNotFound
+	}
+
+	if r.registrationTaken(registration, id) {
+		return internal.ErrorRegistrationAlreadyExists
+	}
+
+	entry.Registration = registration
+	r.put(entry)
+
+	return nil
+}
+
 //Get average capacity of people by brand //Exercise 11 GET /vehicles/average_capacity/brand/{brand}
-func (r *VehicleMap) GetAverageCapacityByBrand(brand string) (avgCapacity int, err error) {
+func (r *VehicleMap) GetAverageCapacityByBrand(brand string, q internal.VehicleQuery) (avgCapacity int, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
 	var size int
 
-	for _, value := range r.db {
+	for _, value := range r.state(q) {
+
+		if !visible(value, q) {
+			continue
+		}
 
-		if value.Brand == brand {
+		if strings.EqualFold(value.Brand, brand) {
 			avgCapacity += value.Capacity
 			size++
 		}
@@ -217,10 +605,15 @@ func (r *VehicleMap) GetAverageCapacityByBrand(brand string) (avgCapacity int, e
 }
 
 //Search vehicles by a range of dimensions of length and width //Exercise 12 GET /vehicles/dimensions?length={min_length}-{max_length}&width={min_width}-{max_width}
-func (r *VehicleMap) GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64) (v []internal.Vehicle, err error) {
+func (r *VehicleMap) GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	for _, value := range r.db {
+	for _, value := range r.state(q) {
 
+		if !visible(value, q) {
+			continue
+		}
 
 		if value.Dimensions.Length >= minLength && value.Dimensions.Length <= maxLength && value.Dimensions.Width >= minWidth && value.Dimensions.Width <= maxWidth {
 			v = append(v, value)
@@ -237,9 +630,15 @@ func (r *VehicleMap) GetVehiclesByDimensions(minLength float64, maxLength float6
 }
 
 //Search vehicles based by a range of weight //Exercise 13 GET /vehicles/weight?min_weight={min_weight}&max_weight={max_weight}
-func (r *VehicleMap) GetVehiclesByWeight(minWeight float64, maxWeight float64) (v []internal.Vehicle, err error) {
+func (r *VehicleMap) GetVehiclesByWeight(minWeight float64, maxWeight float64, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, value := range r.state(q) {
 
-	for _, value := range r.db {
+		if !visible(value, q) {
+			continue
+		}
 
 		if value.Weight >= minWeight && value.Weight <= maxWeight {
 			v = append(v, value)
@@ -252,4 +651,82 @@ func (r *VehicleMap) GetVehiclesByWeight(minWeight float64, maxWeight float64) (
 	}
 
 	return v, nil
+}
+
+// Search is a method that returns the vehicles matching a text, most relevant first GET /vehicles/search?q={text}
+// - past states are searched with an index built for the occasion
+func (r *VehicleMap) Search(text string, limit int, q internal.VehicleQuery) (results []internal.VehicleSearchResult, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state := r.state(q)
+	index := r.search
+	if !q.AsOf.IsZero() {
+		index = newSearchIndex()
+		for _, value := range state {
+			index.add(value)
+		}
+	}
+
+	for id, score := range index.search(text) {
+		value, ok := state[id]
+		if !ok || !visible(value, q) {
+			continue
+		}
+		results = append(results, internal.VehicleSearchResult{Vehicle: value, Score: score})
+	}
+
+	if len(results) == 0 {
+		return nil, internal.ErrorVehiclesNotFound
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Vehicle.Id < results[j].Vehicle.Id
+	})
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// Similar is a method that returns the vehicles closest to a reference one, closest first GET /vehicles/{id}/similar
+// - the tree of the current vehicles is kept until the next write, past states use a tree built for the occasion
+func (r *VehicleMap) Similar(s internal.VehicleSimilarQuery, q internal.VehicleQuery) (similar []internal.SimilarVehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state := r.state(q)
+	ref, ok := state[s.Id]
+	if !ok || !visible(ref, q) {
+		return nil, internal.ErrorVehicleNotFound
+	}
+
+	var index *similarIndex
+	if q.AsOf.IsZero() {
+		r.similarMu.Lock()
+		if r.similar == nil {
+			r.similar = newSimilarIndex(r.db)
+		}
+		index = r.similar
+		r.similarMu.Unlock()
+	} else {
+		index = newSimilarIndex(state)
+	}
+
+	accept := func(v internal.Vehicle) bool {
+		return visible(v, q) && s.Filter.Match(v)
+	}
+	for _, candidate := range index.nearest(ref, s.K, s.Weights, accept) {
+		similar = append(similar, internal.SimilarVehicle{Vehicle: candidate.vehicle, Distance: math.Sqrt(candidate.d2)})
+	}
+
+	if len(similar) == 0 {
+		return nil, internal.ErrorVehiclesNotFound
+	}
+
+	return similar, nil
 }
\ No newline at end of file
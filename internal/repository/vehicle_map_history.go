@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"app/internal"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// keyframeInterval is the number of versions of a vehicle between two whole copies of it
+// - the other versions only keep the fields that changed, a past state is rebuilt from the last copy before it
+const keyframeInterval = 16
+
+// vehicleFields are the paths of the leaf fields of a vehicle, the fields the changes of the versions refer to
+var vehicleFields = leafFields(reflect.TypeOf(internal.Vehicle{}), nil)
+
+// leafFields is a function that returns the paths of the fields of a struct, the nested structs flattened
+// - the moments are leaves
+func leafFields(t reflect.Type, prefix []int) (paths [][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		path := append(append([]int(nil), prefix...), i)
+		if f := t.Field(i); f.Type.Kind() == reflect.Struct && f.Type != reflect.TypeOf(time.Time{}) {
+			paths = append(paths, leafFields(f.Type, path)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return
+}
+
+// change is a struct that represents the new value of a field of a vehicle
+type change struct {
+	// field is the position of the field in vehicleFields
+	field int
+	// value is the new value of the field
+	value any
+}
+
+// version is a struct that represents the state of a vehicle from a moment on
+// - the first version and every keyframeInterval versions keep the whole vehicle, the others the changes since the previous one
+type version struct {
+	// from is the moment since the version is valid
+	from time.Time
+	// vehicle is the whole state of the vehicle, nil if the version only keeps its changes
+	vehicle *internal.Vehicle
+	// changes are the fields that changed since the previous version
+	changes []change
+}
+
+// diff is a function that returns the fields of a vehicle that changed since a previous state of it
+func diff(prev internal.Vehicle, v internal.Vehicle) (changes []change) {
+	before, after := reflect.ValueOf(prev), reflect.ValueOf(v)
+	for i, path := range vehicleFields {
+		value := after.FieldByIndex(path).Interface()
+		if !reflect.DeepEqual(before.FieldByIndex(path).Interface(), value) {
+			changes = append(changes, change{field: i, value: value})
+		}
+	}
+	return
+}
+
+// record is a method that appends a version to the history of a vehicle, prev is its previous state, nil if it is new
+func (r *VehicleMap) record(prev *internal.Vehicle, v internal.Vehicle) {
+	versions := r.history[v.Id]
+
+	// keep the versions sorted even if the clock goes backwards
+	from := r.now()
+	if n := len(versions); n > 0 && versions[n-1].from.After(from) {
+		from = versions[n-1].from
+	}
+
+	next := version{from: from}
+	if prev == nil || len(versions)%keyframeInterval == 0 {
+		next.vehicle = &v
+	} else {
+		next.changes = diff(*prev, v)
+	}
+	r.history[v.Id] = append(versions, next)
+}
+
+// rebuild is a function that returns the state of a vehicle at a version of its history
+func rebuild(versions []version, i int) (v internal.Vehicle) {
+	// last whole copy
+	k := i
+	for versions[k].vehicle == nil {
+		k--
+	}
+	v = *versions[k].vehicle
+
+	// changes since then
+	value := reflect.ValueOf(&v).Elem()
+	for _, next := range versions[k+1 : i+1] {
+		for _, c := range next.changes {
+			value.FieldByIndex(vehicleFields[c.field]).Set(reflect.ValueOf(c.value))
+		}
+	}
+	return
+}
+
+// state is a method that returns the vehicles as they were at the moment of the query
+func (r *VehicleMap) state(q internal.VehicleQuery) (v map[int]internal.Vehicle) {
+	// current state
+	if q.AsOf.IsZero() {
+		return r.db
+	}
+
+	// past state: last version of each vehicle valid at the moment
+	v = make(map[int]internal.Vehicle)
+	for key, versions := range r.history {
+		i := sort.Search(len(versions), func(i int) bool {
+			return versions[i].from.After(q.AsOf)
+		}) - 1
+
+		if i < 0 {
+			continue
+		}
+		v[key] = rebuild(versions, i)
+	}
+
+	return
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"app/internal"
+	"sort"
+	"strings"
+)
+
+// List is a method that returns a filtered, sorted and paged list of vehicles GET /vehicles
+// - total is the number of vehicles that meet the filter, before the paging
+func (r *VehicleMap) List(l internal.VehicleListQuery, q internal.VehicleQuery) (v []internal.Vehicle, total int, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = []internal.Vehicle{}
+	for _, value := range r.state(q) {
+		if !visible(value, q) || !l.Filter.Match(value) {
+			continue
+		}
+		v = append(v, value)
+	}
+
+	sort.Slice(v, func(i, j int) bool {
+		for _, key := range l.Sort {
+			var cmp int
+			if key.Field.IsNumeric() {
+				a, b := key.Field.Numeric(v[i]), key.Field.Numeric(v[j])
+				switch {
+				case a < b:
+					cmp = -1
+				case a > b:
+					cmp = 1
+				}
+			} else {
+				cmp = strings.Compare(strings.ToLower(key.Field.Categorical(v[i])), strings.ToLower(key.Field.Categorical(v[j])))
+			}
+
+			if cmp != 0 {
+				return (cmp < 0) != key.Desc
+			}
+		}
+		return v[i].Id < v[j].Id
+	})
+
+	total = len(v)
+	if l.Offset >= total {
+		return []internal.Vehicle{}, total, nil
+	}
+	v = v[l.Offset:]
+	if l.Limit > 0 && l.Limit < len(v) {
+		v = v[:l.Limit]
+	}
+
+	return v, total, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the filtered, sorted and paged list
+func TestVehicleMap_List(t *testing.T) {
+	rp := NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "ford"}, Metrics: internal.VehicleMetrics{Volume: 10, SizeClass: "van"}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi"}, Metrics: internal.VehicleMetrics{Volume: 6, SizeClass: "compact"}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}, Metrics: internal.VehicleMetrics{Volume: 12, SizeClass: "van"}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat"}, Metrics: internal.VehicleMetrics{Volume: 6, SizeClass: "mini"}},
+	})
+
+	// ids is a function that returns the ids of a list of vehicles
+	ids := func(v []internal.Vehicle) (ids []int) {
+		for _, vehicle := range v {
+			ids = append(ids, vehicle.Id)
+		}
+		return
+	}
+
+	t.Run("case 1: sorted by a derived field, ties broken by id", func(t *testing.T) {
+		// act
+		v, total, err := rp.List(internal.VehicleListQuery{Sort: []internal.SortKey{{Field: internal.FieldVolume, Desc: true}}}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 4, total)
+		require.Equal(t, []int{3, 1, 2, 4}, ids(v))
+	})
+
+	t.Run("case 2: sorted by several fields, categorical ones without case", func(t *testing.T) {
+		// act
+		v, _, err := rp.List(internal.VehicleListQuery{Sort: []internal.SortKey{{Field: internal.FieldBrand}, {Field: internal.FieldVolume, Desc: true}}}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 4, 3, 1}, ids(v))
+	})
+
+	t.Run("case 3: filtered by size class and paged", func(t *testing.T) {
+		// arrange
+		l := internal.VehicleListQuery{
+			Filter: internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldSizeClass: "van"}},
+			Sort:   []internal.SortKey{{Field: internal.FieldVolume}},
+			Offset: 1,
+			Limit:  5,
+		}
+
+		// act
+		v, total, err := rp.List(l, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 2, total)
+		require.Equal(t, []int{3}, ids(v))
+	})
+
+	t.Run("case 4: an offset past the end returns no vehicles", func(t *testing.T) {
+		// act
+		v, total, err := rp.List(internal.VehicleListQuery{Offset: 10}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 4, total)
+		require.Empty(t, v)
+	})
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"app/internal"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Aggregate is a method that computes the statistics of a numeric field by group GET /vehicles/stats
+func (r *VehicleMap) Aggregate(s internal.VehicleStatsQuery, q internal.VehicleQuery) (stats []internal.VehicleStats, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// group the values
+	groups := make(map[string][]float64)
+	keys := make(map[string]map[internal.VehicleField]string)
+	for _, value := range r.state(q) {
+		if !visible(value, q) || !s.Filter.Match(value) {
+			continue
+		}
+
+		group := make(map[internal.VehicleField]string, len(s.GroupBy))
+		parts := make([]string, len(s.GroupBy))
+		for i, field := range s.GroupBy {
+			group[field] = field.Categorical(value)
+			parts[i] = group[field]
+		}
+		key := strings.Join(parts, "\x00")
+
+		groups[key] = append(groups[key], s.Field.Numeric(value))
+		keys[key] = group
+	}
+
+	if len(groups) == 0 {
+		return nil, internal.ErrorVehiclesNotFound
+	}
+
+	// summarize each group, sorted by its key
+	sorted := make([]string, 0, len(groups))
+	for key := range groups {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		st := internal.Summarize(groups[key], s.Percentiles)
+		st.Group = keys[key]
+		stats = append(stats, st)
+	}
+
+	return stats, nil
+}
+
+// Histogram is a method that counts the vehicles by buckets of a numeric field GET /vehicles/histogram
+func (r *VehicleMap) Histogram(h internal.VehicleHistogramQuery, q internal.VehicleQuery) (buckets []internal.HistogramBucket, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var values []float64
+	for _, value := range r.state(q) {
+		if !visible(value, q) || !h.Filter.Match(value) {
+			continue
+		}
+		values = append(values, h.Field.Numeric(value))
+	}
+
+	if len(values) == 0 {
+		return nil, internal.ErrorVehiclesNotFound
+	}
+
+	// edges: explicit or of the same width between the minimum and the maximum
+	edges := h.Edges
+	if len(edges) == 0 {
+		min, max := values[0], values[0]
+		for _, value := range values {
+			min = math.Min(min, value)
+			max = math.Max(max, value)
+		}
+
+		edges = make([]float64, h.Buckets+1)
+		for i := range edges {
+			edges[i] = min + (max-min)*float64(i)/float64(h.Buckets)
+		}
+		edges[h.Buckets] = max
+	}
+
+	buckets = make([]internal.HistogramBucket, len(edges)-1)
+	for i := range buckets {
+		buckets[i] = internal.HistogramBucket{Min: edges[i], Max: edges[i+1]}
+	}
+
+	// count: values out of the edges are left out
+	last := len(buckets) - 1
+	for _, value := range values {
+		if value < edges[0] || value > edges[last+1] {
+			continue
+		}
+		i := sort.Search(len(edges), func(i int) bool { return edges[i] > value }) - 1
+		if i > last {
+			i = last
+		}
+		buckets[i].Count++
+	}
+
+	return buckets, nil
+}
+
+// Frequencies is a method that counts the vehicles by value of a categorical field GET /vehicles/frequencies
+func (r *VehicleMap) Frequencies(f internal.VehicleFrequencyQuery, q internal.VehicleQuery) (freq []internal.Frequency, total int, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, value := range r.state(q) {
+		if !visible(value, q) || !f.Filter.Match(value) {
+			continue
+		}
+		counts[f.Field.Categorical(value)]++
+		total++
+	}
+
+	if total == 0 {
+		return nil, 0, internal.ErrorVehiclesNotFound
+	}
+
+	for value, count := range counts {
+		freq = append(freq, internal.Frequency{Value: value, Count: count})
+	}
+	sort.Slice(freq, func(i, j int) bool {
+		if freq[i].Count != freq[j].Count {
+			return freq[i].Count > freq[j].Count
+		}
+		return freq[i].Value < freq[j].Value
+	})
+
+	if f.Top > 0 && f.Top < len(freq) {
+		freq = freq[:f.Top]
+	}
+
+	return freq, total, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Aggregate
+func TestVehicleMap_Aggregate(t *testing.T) {
+	rp := NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "diesel", FabricationYear: 1995, MaxSpeed: 100}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "diesel", FabricationYear: 1999, MaxSpeed: 200}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "gas", FabricationYear: 2001, MaxSpeed: 150}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi", FuelType: "diesel", FabricationYear: 2005, MaxSpeed: 300}},
+	})
+
+	t.Run("case 1: a single group of every vehicle", func(t *testing.T) {
+		// act
+		stats, err := rp.Aggregate(internal.VehicleStatsQuery{Field: internal.FieldMaxSpeed, Percentiles: []float64{90}}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		require.Equal(t, 4, stats[0].Count)
+		require.Equal(t, 750.0, stats[0].Sum)
+		require.Equal(t, 100.0, stats[0].Min)
+		require.Equal(t, 300.0, stats[0].Max)
+		require.Equal(t, 187.5, stats[0].Mean)
+		require.Equal(t, 175.0, stats[0].Median)
+		require.InDelta(t, 73.95, stats[0].StdDev, 0.01)
+		require.InDelta(t, 270.0, stats[0].Percentiles[90], 1e-9)
+	})
+
+	t.Run("case 2: grouped by brand and decade after a filter", func(t *testing.T) {
+		// arrange
+		min := 1990.0
+		filter := internal.VehicleFilter{
+			Equals: map[internal.VehicleField]string{internal.FieldFuelType: "diesel"},
+			Ranges: map[internal.VehicleField]internal.Range{internal.FieldYear: {Min: &min}},
+		}
+
+		// act
+		stats, err := rp.Aggregate(internal.VehicleStatsQuery{
+			Field:   internal.FieldMaxSpeed,
+			GroupBy: []internal.VehicleField{internal.FieldBrand, internal.FieldDecade},
+			Filter:  filter,
+		}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, stats, 2)
+		require.Equal(t, map[internal.VehicleField]string{"brand": "Audi", "decade": "2000s"}, stats[0].Group)
+		require.Equal(t, 1, stats[0].Count)
+		require.Equal(t, map[internal.VehicleField]string{"brand": "Ford", "decade": "1990s"}, stats[1].Group)
+		require.Equal(t, 150.0, stats[1].Mean)
+	})
+
+	t.Run("case 3: no vehicle meets the filter", func(t *testing.T) {
+		// arrange
+		filter := internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldBrand: "Kia"}}
+
+		// act
+		_, err := rp.Aggregate(internal.VehicleStatsQuery{Field: internal.FieldWeight, Filter: filter}, internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrorVehiclesNotFound)
+	})
+}
+
+// Tests for Histogram
+func TestVehicleMap_Histogram(t *testing.T) {
+	rp := NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Weight: 100}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Weight: 150}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Weight: 199}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Weight: 300}},
+	})
+
+	t.Run("case 1: buckets of the same width include the maximum", func(t *testing.T) {
+		// act
+		buckets, err := rp.Histogram(internal.VehicleHistogramQuery{Field: internal.FieldWeight, Buckets: 2}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []internal.HistogramBucket{
+			{Min: 100, Max: 200, Count: 3},
+			{Min: 200, Max: 300, Count: 1},
+		}, buckets)
+	})
+
+	t.Run("case 2: explicit edges leave out the values out of them", func(t *testing.T) {
+		// act
+		buckets, err := rp.Histogram(internal.VehicleHistogramQuery{Field: internal.FieldWeight, Edges: []float64{120, 150, 200}}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []internal.HistogramBucket{
+			{Min: 120, Max: 150, Count: 0},
+			{Min: 150, Max: 200, Count: 2},
+		}, buckets)
+	})
+}
+
+// Tests for Frequencies
+func TestVehicleMap_Frequencies(t *testing.T) {
+	// arrange
+	rp := NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Color: "Blue"}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Color: "Red"}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Color: "Blue"}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Color: "Green"}},
+	})
+
+	// act
+	freq, total, err := rp.Frequencies(internal.VehicleFrequencyQuery{Field: internal.FieldColor, Top: 2}, internal.VehicleQuery{})
+
+	// assert
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	require.Equal(t, []internal.Frequency{{Value: "Blue", Count: 2}, {Value: "Green", Count: 1}}, freq)
+}
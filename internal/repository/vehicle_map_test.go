@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"app/internal"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newVehicleMapAt is a function that returns a repository with a clock that can be moved by the test
+func newVehicleMapAt(t0 time.Time) (rp *VehicleMap, set func(t time.Time)) {
+	now := t0
+	rp = NewVehicleMap(nil)
+	rp.now = func() time.Time { return now }
+	set = func(t time.Time) { now = t }
+	return
+}
+
+// Tests for point-in-time reads
+func TestVehicleMap_AsOf(t *testing.T) {
+	t1 := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)
+	t3 := time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("case 1: updates are only visible after they happen", func(t *testing.T) {
+		// arrange
+		rp, set := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 100}}))
+		set(t2)
+		require.NoError(t, rp.UpdateMaxSpeedById(1, 150))
+
+		// act
+		before, err1 := rp.GetAverageSpeedByBrand("Ford", internal.VehicleQuery{AsOf: t1.Add(time.Hour)})
+		after, err2 := rp.GetAverageSpeedByBrand("Ford", internal.VehicleQuery{AsOf: t2})
+		current, err3 := rp.GetAverageSpeedByBrand("Ford", internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Equal(t, 100.0, before)
+		require.Equal(t, 150.0, after)
+		require.Equal(t, 150.0, current)
+	})
+
+	t.Run("case 2: vehicles do not exist before they are added", func(t *testing.T) {
+		// arrange
+		rp, _ := newVehicleMapAt(t2)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1}))
+
+		// act
+		v, err := rp.FindAll(internal.VehicleQuery{AsOf: t1})
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, v)
+	})
+
+	t.Run("case 3: deleted vehicles are hidden after the delete and visible before", func(t *testing.T) {
+		// arrange
+		rp, set := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Color: "Blue", FabricationYear: 2000}}))
+		set(t2)
+		require.NoError(t, rp.DeleteById(1))
+
+		// act
+		before, err1 := rp.SearchByColorAndYear("Blue", 2000, internal.VehicleQuery{AsOf: t1})
+		_, err2 := rp.SearchByColorAndYear("Blue", 2000, internal.VehicleQuery{AsOf: t3})
+		deleted, err3 := rp.SearchByColorAndYear("Blue", 2000, internal.VehicleQuery{AsOf: t3, IncludeDeleted: true})
+
+		// assert
+		require.NoError(t, err1)
+		require.Len(t, before, 1)
+		require.Nil(t, before[0].DeletedAt)
+		require.ErrorIs(t, err2, internal.ErrorVehiclesNotFound)
+		require.NoError(t, err3)
+		require.Len(t, deleted, 1)
+		require.Equal(t, t2, *deleted[0].DeletedAt)
+	})
+
+	t.Run("case 4: a purged vehicle stays in the past moments and its id can be added again", func(t *testing.T) {
+		// arrange
+		rp, set := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford"}}))
+		set(t2)
+		require.NoError(t, rp.DeleteById(1))
+		purged, err := rp.PurgeDeleted(t2.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+
+		// act
+		current, err1 := rp.FindAll(internal.VehicleQuery{IncludeDeleted: true})
+		first, err2 := rp.FindAll(internal.VehicleQuery{AsOf: t1})
+		gap, err3 := rp.FindAll(internal.VehicleQuery{AsOf: t2.Add(time.Hour), IncludeDeleted: true})
+		set(t3)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi"}}))
+		before, err4 := rp.FindAll(internal.VehicleQuery{AsOf: t1})
+		second, err5 := rp.FindAll(internal.VehicleQuery{AsOf: t3})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.NoError(t, err4)
+		require.NoError(t, err5)
+		require.Empty(t, current)
+		require.Equal(t, "Ford", first[1].Brand)
+		require.Equal(t, "Ford", gap[1].Brand)
+		require.NotNil(t, gap[1].DeletedAt)
+		require.Equal(t, "Ford", before[1].Brand)
+		require.Equal(t, "Audi", second[1].Brand)
+		require.Len(t, rp.history[1], 3)
+	})
+
+	t.Run("case 5: loaded vehicles are visible at any moment", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(map[int]internal.Vehicle{1: {Id: 1}})
+
+		// act
+		v, err := rp.FindAll(internal.VehicleQuery{AsOf: t1})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+	})
+
+	t.Run("case 6: versions keep the changed fields, past states are rebuilt across whole copies", func(t *testing.T) {
+		// arrange
+		rp, set := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "diesel", MaxSpeed: 100}}))
+		for i := 1; i <= 2*keyframeInterval; i++ {
+			set(t1.Add(time.Duration(i) * time.Hour))
+			require.NoError(t, rp.UpdateMaxSpeedById(1, float64(100+i)))
+		}
+		set(t2)
+		require.NoError(t, rp.UpdateFuelTypeById(1, "gasoline"))
+		require.NoError(t, rp.DeleteById(1))
+
+		// act
+		versions := rp.history[1]
+		past := make(map[int]float64)
+		for i := 0; i <= 2*keyframeInterval; i++ {
+			v, err := rp.FindById(1, internal.VehicleQuery{AsOf: t1.Add(time.Duration(i) * time.Hour)})
+			require.NoError(t, err)
+			past[i] = v.MaxSpeed
+		}
+		fuel, errFuel := rp.FindById(1, internal.VehicleQuery{AsOf: t2.Add(-time.Minute)})
+		deleted, errDeleted := rp.FindById(1, internal.VehicleQuery{AsOf: t2, IncludeDeleted: true})
+
+		// assert
+		require.Len(t, versions, 2*keyframeInterval+3)
+		for i, value := range versions {
+			require.Equal(t, i%keyframeInterval == 0, value.vehicle != nil, "version %d", i)
+		}
+		require.Len(t, versions[1].changes, 1)
+		for i, speed := range past {
+			require.Equal(t, float64(100+i), speed)
+		}
+		require.NoError(t, errFuel)
+		require.Equal(t, "diesel", fuel.FuelType)
+		require.Nil(t, fuel.DeletedAt)
+		require.NoError(t, errDeleted)
+		require.Equal(t, "gasoline", deleted.FuelType)
+		require.Equal(t, 132.0, deleted.MaxSpeed)
+		require.Equal(t, t2, *deleted.DeletedAt)
+	})
+}
+
+// Tests for the registration index
+func TestVehicleMap_Registration(t *testing.T) {
+	t.Run("case 1: registrations are unique in any written form", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(nil)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "AB1234"}}))
+
+		// act
+		errAdd := rp.Add(internal.Vehicle{Id: 2, VehicleAttributes: internal.VehicleAttributes{Registration: "ab-12 34"}})
+		errBatch := rp.AddMultiple([]internal.Vehicle{
+			{Id: 3, VehicleAttributes: internal.VehicleAttributes{Registration: "CD1"}},
+			{Id: 4, VehicleAttributes: internal.VehicleAttributes{Registration: "cd-1"}},
+		})
+		v, errFind := rp.FindByRegistration("ab 1234", internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, errAdd, internal.ErrorRegistrationAlreadyExists)
+		require.ErrorIs(t, errBatch, internal.ErrorRegistrationAlreadyExists)
+		require.NoError(t, errFind)
+		require.Equal(t, 1, v.Id)
+		_, err := rp.FindById(3, internal.VehicleQuery{})
+		require.ErrorIs(t, err, internal.ErrorVehicleNotFound)
+	})
+
+	t.Run("case 2: updates move the vehicle in the index", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(nil)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "OLD"}}))
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 2, VehicleAttributes: internal.VehicleAttributes{Registration: "OTHER"}}))
+
+		// act
+		errTaken := rp.UpdateRegistrationById(1, "OTHER")
+		errUpdate := rp.UpdateRegistrationById(1, "NEW")
+		_, errOld := rp.FindByRegistration("OLD", internal.VehicleQuery{})
+		v, errNew := rp.FindByRegistration("NEW", internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, errTaken, internal.ErrorRegistrationAlreadyExists)
+		require.NoError(t, errUpdate)
+		require.ErrorIs(t, errOld, internal.ErrorVehicleNotFound)
+		require.NoError(t, errNew)
+		require.Equal(t, 1, v.Id)
+	})
+
+	t.Run("case 3: duplicated loaded registrations are ambiguous", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "0"}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Registration: "0"}},
+		})
+
+		// act
+		_, err := rp.FindByRegistration("0", internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrorRegistrationAmbiguous)
+	})
+}
+
+// Tests for the soft deletes
+func TestVehicleMap_SoftDelete(t *testing.T) {
+	t1 := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	t.Run("case 1: deleted vehicles are only read when the deleted ones are included", func(t *testing.T) {
+		// arrange
+		rp, _ := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Registration: "AB1"}}))
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Registration: "AB2"}}))
+
+		// act
+		err := rp.DeleteById(1)
+		errAgain := rp.DeleteById(1)
+		all, _ := rp.FindAll(internal.VehicleQuery{})
+		withDeleted, _ := rp.FindAll(internal.VehicleQuery{IncludeDeleted: true})
+		_, errFind := rp.FindById(1, internal.VehicleQuery{})
+		deleted, errDeleted := rp.FindById(1, internal.VehicleQuery{IncludeDeleted: true})
+		errRegistration := rp.Add(internal.Vehicle{Id: 3, VehicleAttributes: internal.VehicleAttributes{Registration: "AB1"}})
+
+		// assert: the deleted vehicles keep their registration until purged
+		require.NoError(t, err)
+		require.ErrorIs(t, errAgain, internal.ErrorVehicleNotFound)
+		require.Len(t, all, 1)
+		require.Len(t, withDeleted, 2)
+		require.ErrorIs(t, errFind, internal.ErrorVehicleNotFound)
+		require.NoError(t, errDeleted)
+		require.Equal(t, t1, *deleted.DeletedAt)
+		require.ErrorIs(t, errRegistration, internal.ErrorRegistrationAlreadyExists)
+	})
+
+	t.Run("case 2: only the deleted vehicles can be restored", func(t *testing.T) {
+		// arrange
+		rp, _ := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1}))
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 2}))
+		require.NoError(t, rp.DeleteById(1))
+
+		// act
+		err := rp.RestoreById(1)
+		errActive := rp.RestoreById(2)
+		errUnknown := rp.RestoreById(3)
+		v, errFind := rp.FindById(1, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.ErrorIs(t, errActive, internal.ErrorVehicleNotDeleted)
+		require.ErrorIs(t, errUnknown, internal.ErrorVehicleNotFound)
+		require.NoError(t, errFind)
+		require.Nil(t, v.DeletedAt)
+	})
+
+	t.Run("case 3: the purge only removes the vehicles deleted before the cutoff", func(t *testing.T) {
+		// arrange
+		rp, set := newVehicleMapAt(t1)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Registration: "AB1"}}))
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 2}))
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 3}))
+		require.NoError(t, rp.DeleteById(1))
+		set(t2)
+		require.NoError(t, rp.DeleteById(2))
+
+		// act
+		purged, err := rp.PurgeDeleted(t2)
+		withDeleted, _ := rp.FindAll(internal.VehicleQuery{IncludeDeleted: true})
+		errRestore := rp.RestoreById(1)
+		errRegistration := rp.Add(internal.Vehicle{Id: 4, VehicleAttributes: internal.VehicleAttributes{Registration: "AB1"}})
+
+		// assert: a vehicle deleted at the cutoff is kept
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+		require.Len(t, withDeleted, 2)
+		require.NotContains(t, withDeleted, 1)
+		require.ErrorIs(t, errRestore, internal.ErrorVehicleNotFound)
+		require.NoError(t, errRegistration)
+	})
+}
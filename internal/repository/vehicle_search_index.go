@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"app/internal"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	// searchFieldWeights is the relevance of a match in each searchable field
+	searchFieldWeights = []struct {
+		weight float64
+		value  func(v internal.Vehicle) string
+	}{
+		{weight: 3, value: func(v internal.Vehicle) string { return v.Brand }},
+		{weight: 2, value: func(v internal.Vehicle) string { return v.Model }},
+		{weight: 1, value: func(v internal.Vehicle) string { return v.Color }},
+		{weight: 3, value: func(v internal.Vehicle) string { return internal.NormalizeRegistration(v.Registration) }},
+	}
+	// diacritics is the folding of the accented latin letters
+	diacritics = map[rune]string{
+		'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+		'ç': "c", 'č': "c", 'ć': "c",
+		'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ě': "e",
+		'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+		'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+		'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+		'ř': "r", 'š': "s", 'ś': "s", 'ß': "ss",
+		'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ů': "u",
+		'ý': "y", 'ÿ': "y", 'ž': "z", 'ź': "z", 'ż': "z",
+	}
+)
+
+const (
+	// searchPrefixScore is the relevance of a token starting by the searched one
+	searchPrefixScore = 0.7
+	// searchTypoPenalty is the relevance lost by each typo
+	searchTypoPenalty = 0.3
+)
+
+// newSearchIndex is a function that returns an empty search index
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[int]float64),
+		docs:     make(map[int][]string),
+	}
+}
+
+// searchIndex is a struct that represents an inverted index over the searchable fields of the vehicles
+type searchIndex struct {
+	// postings is a map of the ids holding each token, with the weight of the best field holding it
+	postings map[string]map[int]float64
+	// docs is a map of the tokens of each id
+	docs map[int][]string
+	// vocabulary are the tokens of the index, sorted to find the prefixes
+	vocabulary []string
+}
+
+// fold is a function that lower cases a text and removes its diacritics
+func fold(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := diacritics[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// tokenize is a function that splits a folded text into its words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(fold(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// add is a method that indexes a vehicle, replacing its previous tokens
+func (x *searchIndex) add(v internal.Vehicle) {
+	x.remove(v.Id)
+
+	for _, field := range searchFieldWeights {
+		for _, token := range tokenize(field.value(v)) {
+			ids, ok := x.postings[token]
+			if !ok {
+				ids = make(map[int]float64)
+				x.postings[token] = ids
+				i := sort.SearchStrings(x.vocabulary, token)
+				x.vocabulary = append(x.vocabulary[:i], append([]string{token}, x.vocabulary[i:]...)...)
+			}
+
+			if _, ok := ids[v.Id]; !ok {
+				x.docs[v.Id] = append(x.docs[v.Id], token)
+			}
+			if field.weight > ids[v.Id] {
+				ids[v.Id] = field.weight
+			}
+		}
+	}
+}
+
+// remove is a method that removes the tokens of an id
+func (x *searchIndex) remove(id int) {
+	for _, token := range x.docs[id] {
+		ids := x.postings[token]
+		delete(ids, id)
+		if len(ids) > 0 {
+			continue
+		}
+
+		delete(x.postings, token)
+		i := sort.SearchStrings(x.vocabulary, token)
+		x.vocabulary = append(x.vocabulary[:i], x.vocabulary[i+1:]...)
+	}
+	delete(x.docs, id)
+}
+
+// search is a method that returns the relevance of each id matching any word of a text
+// - a word matches a token exactly, as its prefix or with a few typos depending on its length
+// - each word adds the score of its best match in the best field of the id
+func (x *searchIndex) search(text string) (scores map[int]float64) {
+	scores = make(map[int]float64)
+
+	for _, word := range tokenize(text) {
+		best := make(map[int]float64)
+		match := func(token string, score float64) {
+			for id, weight := range x.postings[token] {
+				if score*weight > best[id] {
+					best[id] = score * weight
+				}
+			}
+		}
+
+		// exact and prefix matches
+		for i := sort.SearchStrings(x.vocabulary, word); i < len(x.vocabulary) && strings.HasPrefix(x.vocabulary[i], word); i++ {
+			if x.vocabulary[i] == word {
+				match(word, 1)
+				continue
+			}
+			match(x.vocabulary[i], searchPrefixScore)
+		}
+
+		// typos
+		typos := maxTypos(word)
+		for _, token := range x.vocabulary {
+			if typos == 0 {
+				break
+			}
+			if d := abs(len(token) - len(word)); d > typos || token == word {
+				continue
+			}
+			if d := editDistance(word, token, typos); d <= typos {
+				match(token, 1-searchTypoPenalty*float64(d))
+			}
+		}
+
+		for id, score := range best {
+			scores[id] += score
+		}
+	}
+
+	return
+}
+
+// maxTypos is a function that returns the number of typos tolerated in a word
+func maxTypos(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is a function that returns the Levenshtein distance between two words,
+// or limit+1 as soon as it is known to exceed the limit
+func editDistance(a string, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// abs is a function that returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
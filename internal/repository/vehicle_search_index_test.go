@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for Search
+func TestVehicleMap_Search(t *testing.T) {
+	newRepository := func() *VehicleMap {
+		return NewVehicleMap(map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Chevrolet", Model: "Cavalier", Color: "Blue", Registration: "8371"}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Citroën", Model: "C4", Color: "Red", Registration: "1234"}},
+			3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Blue Oval", Color: "White", Registration: "5678"}},
+		})
+	}
+
+	t.Run("case 1: case and diacritics are folded", func(t *testing.T) {
+		// arrange
+		rp := newRepository()
+
+		// act
+		results, err := rp.Search("CITROEN", 0, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, 2, results[0].Vehicle.Id)
+	})
+
+	t.Run("case 2: prefixes and typos match", func(t *testing.T) {
+		// arrange
+		rp := newRepository()
+
+		// act
+		prefix, err1 := rp.Search("chev", 0, internal.VehicleQuery{})
+		typo, err2 := rp.Search("chevrolt", 0, internal.VehicleQuery{})
+		words, err3 := rp.Search("Chevy Cavalier", 0, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Equal(t, 1, prefix[0].Vehicle.Id)
+		require.Equal(t, 1, typo[0].Vehicle.Id)
+		require.Equal(t, 1, words[0].Vehicle.Id)
+	})
+
+	t.Run("case 3: the best field ranks first", func(t *testing.T) {
+		// arrange
+		rp := newRepository()
+
+		// act
+		results, err := rp.Search("blue", 0, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, 3, results[0].Vehicle.Id)
+		require.Equal(t, 1, results[1].Vehicle.Id)
+	})
+
+	t.Run("case 4: the index follows the mutations", func(t *testing.T) {
+		// arrange
+		rp := newRepository()
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Škoda", Registration: "AB-99"}}))
+		require.NoError(t, rp.UpdateRegistrationById(4, "ZZ11"))
+		require.NoError(t, rp.DeleteById(2))
+
+		// act
+		added, err1 := rp.Search("skoda", 0, internal.VehicleQuery{})
+		_, err2 := rp.Search("ab99", 0, internal.VehicleQuery{})
+		plate, err3 := rp.Search("zz11", 0, internal.VehicleQuery{})
+		_, err4 := rp.Search("citroen", 0, internal.VehicleQuery{})
+		deleted, err5 := rp.Search("citroen", 0, internal.VehicleQuery{IncludeDeleted: true})
+
+		// assert
+		require.NoError(t, err1)
+		require.Equal(t, 4, added[0].Vehicle.Id)
+		require.ErrorIs(t, err2, internal.ErrorVehiclesNotFound)
+		require.NoError(t, err3)
+		require.Equal(t, 4, plate[0].Vehicle.Id)
+		require.ErrorIs(t, err4, internal.ErrorVehiclesNotFound)
+		require.NoError(t, err5)
+		require.Equal(t, 2, deleted[0].Vehicle.Id)
+	})
+}
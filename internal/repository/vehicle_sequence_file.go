@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// NewVehicleSequenceFile is a function that returns a new instance of VehicleSequenceFile
+// - the last id is read from path; an empty path keeps the sequence in memory only
+func NewVehicleSequenceFile(path string) (s *VehicleSequenceFile, err error) {
+	s = &VehicleSequenceFile{path: path}
+	if path == "" {
+		return
+	}
+
+	// read last id
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+		return
+	}
+
+	var state VehicleSequenceJSON
+	if err = json.Unmarshal(bytes, &state); err != nil {
+		return
+	}
+	s.last = state.LastId
+
+	return
+}
+
+// VehicleSequenceJSON is a struct that represents the state of the sequence in JSON format
+type VehicleSequenceJSON struct {
+	LastId int `json:"last_id"`
+}
+
+// VehicleSequenceFile is a struct that implements the VehicleSequence interface persisting the last id in a file
+type VehicleSequenceFile struct {
+	// mu is the mutex that guards the last id
+	mu sync.Mutex
+	// path is the path to the file that contains the last id
+	path string
+	// last is the last id returned or advanced
+	last int
+}
+
+// Next is a method that returns a new id
+func (s *VehicleSequenceFile) Next() (id int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.save(s.last + 1); err != nil {
+		return
+	}
+	s.last++
+
+	return s.last, nil
+}
+
+// Advance is a method that makes sure the next ids are greater than id
+func (s *VehicleSequenceFile) Advance(id int) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id <= s.last {
+		return
+	}
+
+	if err = s.save(id); err != nil {
+		return
+	}
+	s.last = id
+
+	return
+}
+
+// save is a method that persists the last id, replacing the file atomically
+func (s *VehicleSequenceFile) save(last int) (err error) {
+	if s.path == "" {
+		return
+	}
+
+	bytes, err := json.Marshal(VehicleSequenceJSON{LastId: last})
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(bytes); err != nil {
+		tmp.Close()
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(tmp.Name(), s.path)
+	return
+}
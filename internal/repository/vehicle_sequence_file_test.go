@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for VehicleSequenceFile
+func TestVehicleSequenceFile(t *testing.T) {
+	t.Run("case 1: ids are unique under concurrency and survive a restart", func(t *testing.T) {
+		// arrange
+		path := filepath.Join(t.TempDir(), "sequence.json")
+		sq, err := NewVehicleSequenceFile(path)
+		require.NoError(t, err)
+
+		// act
+		var mu sync.Mutex
+		ids := make(map[int]bool)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				id, err := sq.Next()
+				require.NoError(t, err)
+				mu.Lock()
+				ids[id] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		restarted, err := NewVehicleSequenceFile(path)
+		require.NoError(t, err)
+		next, err := restarted.Next()
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, ids, 50)
+		require.Equal(t, 51, next)
+	})
+
+	t.Run("case 2: advance skips the explicit ids", func(t *testing.T) {
+		// arrange
+		sq, err := NewVehicleSequenceFile("")
+		require.NoError(t, err)
+
+		// act
+		require.NoError(t, sq.Advance(100))
+		require.NoError(t, sq.Advance(10))
+		id, err := sq.Next()
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 101, id)
+	})
+}
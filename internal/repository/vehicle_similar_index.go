@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"app/internal"
+	"container/heap"
+	"sort"
+	"strings"
+)
+
+// similarAxes are the numeric fields the tree splits on
+var similarAxes = []internal.VehicleField{
+	internal.FieldYear, internal.FieldCapacity, internal.FieldMaxSpeed, internal.FieldWeight,
+	internal.FieldHeight, internal.FieldLength, internal.FieldWidth,
+}
+
+// similarCategories are the categorical fields compared at the leaves
+var similarCategories = []internal.VehicleField{
+	internal.FieldBrand, internal.FieldModel, internal.FieldFuelType, internal.FieldTransmission,
+}
+
+// similarNode is a node of the k-d tree
+type similarNode struct {
+	// point are the scaled numeric values of the vehicle
+	point []float64
+	// vehicle is the vehicle of the node
+	vehicle internal.Vehicle
+	// axis is the index of the axis the node splits on
+	axis int
+	// left and right are the subtrees below and above the split
+	left, right *similarNode
+}
+
+// similarIndex is a k-d tree over the numeric fields of the vehicles, scaled by the range of each field
+// - the numeric part of the distance is a lower bound of the whole distance, so subtrees farther than
+// the k-th candidate can be skipped whatever the categorical weights are
+type similarIndex struct {
+	// root is the root of the tree
+	root *similarNode
+	// min and span are the minimum and the range of each axis
+	min, span []float64
+}
+
+// newSimilarIndex is a function that builds the tree of a set of vehicles
+func newSimilarIndex(db map[int]internal.Vehicle) *similarIndex {
+	ix := &similarIndex{min: make([]float64, len(similarAxes)), span: make([]float64, len(similarAxes))}
+
+	// scale: every axis ranges from 0 to 1
+	max := make([]float64, len(similarAxes))
+	first := true
+	for _, v := range db {
+		for i, field := range similarAxes {
+			value := field.Numeric(v)
+			if first || value < ix.min[i] {
+				ix.min[i] = value
+			}
+			if first || value > max[i] {
+				max[i] = value
+			}
+		}
+		first = false
+	}
+	for i := range similarAxes {
+		ix.span[i] = max[i] - ix.min[i]
+		if ix.span[i] == 0 {
+			ix.span[i] = 1
+		}
+	}
+
+	nodes := make([]*similarNode, 0, len(db))
+	for _, v := range db {
+		nodes = append(nodes, &similarNode{point: ix.point(v), vehicle: v})
+	}
+	ix.root = buildSimilar(nodes, 0)
+
+	return ix
+}
+
+// point is a method that returns the scaled numeric values of a vehicle
+func (ix *similarIndex) point(v internal.Vehicle) (p []float64) {
+	p = make([]float64, len(similarAxes))
+	for i, field := range similarAxes {
+		p[i] = (field.Numeric(v) - ix.min[i]) / ix.span[i]
+	}
+	return
+}
+
+// buildSimilar is a function that builds a balanced subtree splitting on the median
+func buildSimilar(nodes []*similarNode, depth int) *similarNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	axis := depth % len(similarAxes)
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].point[axis] != nodes[j].point[axis] {
+			return nodes[i].point[axis] < nodes[j].point[axis]
+		}
+		return nodes[i].vehicle.Id < nodes[j].vehicle.Id
+	})
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	node.axis = axis
+	node.left = buildSimilar(nodes[:mid], depth+1)
+	node.right = buildSimilar(nodes[mid+1:], depth+1)
+	return node
+}
+
+// similarCandidate is a vehicle found by the search with its squared distance
+type similarCandidate struct {
+	vehicle internal.Vehicle
+	d2      float64
+}
+
+// closer is a function that reports if a candidate comes before another one, ties broken by id
+func closer(a, b similarCandidate) bool {
+	if a.d2 != b.d2 {
+		return a.d2 < b.d2
+	}
+	return a.vehicle.Id < b.vehicle.Id
+}
+
+// similarHeap is a max heap of the candidates, the farthest on top
+type similarHeap []similarCandidate
+
+func (h similarHeap) Len() int           { return len(h) }
+func (h similarHeap) Less(i, j int) bool { return closer(h[j], h[i]) }
+func (h similarHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *similarHeap) Push(x any)        { *h = append(*h, x.(similarCandidate)) }
+func (h *similarHeap) Pop() (x any) {
+	old := *h
+	x, *h = old[len(old)-1], old[:len(old)-1]
+	return
+}
+
+// nearest is a method that returns the k accepted vehicles closest to a reference, closest first,
+// without the reference itself
+func (ix *similarIndex) nearest(ref internal.Vehicle, k int, weights map[internal.VehicleField]float64, accept func(v internal.Vehicle) bool) (candidates []similarCandidate) {
+	target := ix.point(ref)
+	axisWeights := make([]float64, len(similarAxes))
+	for i, field := range similarAxes {
+		axisWeights[i] = weights[field]
+	}
+
+	h := &similarHeap{}
+	var visit func(n *similarNode)
+	visit = func(n *similarNode) {
+		if n == nil {
+			return
+		}
+
+		if n.vehicle.Id != ref.Id && accept(n.vehicle) {
+			var d2 float64
+			for i := range similarAxes {
+				diff := n.point[i] - target[i]
+				d2 += axisWeights[i] * diff * diff
+			}
+			for _, field := range similarCategories {
+				if !strings.EqualFold(field.Categorical(n.vehicle), field.Categorical(ref)) {
+					d2 += weights[field]
+				}
+			}
+
+			candidate := similarCandidate{vehicle: n.vehicle, d2: d2}
+			switch {
+			case h.Len() < k:
+				heap.Push(h, candidate)
+			case closer(candidate, (*h)[0]):
+				(*h)[0] = candidate
+				heap.Fix(h, 0)
+			}
+		}
+
+		// nearest side first, the other one only if it can hold a closer vehicle
+		diff := target[n.axis] - n.point[n.axis]
+		near, far := n.left, n.right
+		if diff > 0 {
+			near, far = n.right, n.left
+		}
+		visit(near)
+		if h.Len() < k || axisWeights[n.axis]*diff*diff <= (*h)[0].d2 {
+			visit(far)
+		}
+	}
+	visit(ix.root)
+
+	candidates = make([]similarCandidate, h.Len())
+	for i := len(candidates) - 1; i >= 0; i-- {
+		candidates[i] = heap.Pop(h).(similarCandidate)
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"app/internal"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the nearest neighbour search
+func TestVehicleMap_Similar(t *testing.T) {
+	t.Run("case 1: the tree finds the same vehicles as a full scan", func(t *testing.T) {
+		// arrange
+		rnd := rand.New(rand.NewSource(1))
+		brands := []string{"Ford", "Audi", "Fiat"}
+		db := make(map[int]internal.Vehicle)
+		for id := 1; id <= 500; id++ {
+			db[id] = internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{
+				Brand:           brands[rnd.Intn(len(brands))],
+				FabricationYear: 1980 + rnd.Intn(40),
+				Capacity:        2 + rnd.Intn(6),
+				MaxSpeed:        float64(100 + rnd.Intn(150)),
+				Weight:          float64(800 + rnd.Intn(1500)),
+				Dimensions:      internal.Dimensions{Height: rnd.Float64() * 3, Length: rnd.Float64() * 6, Width: rnd.Float64() * 3},
+			}}
+		}
+		rp := NewVehicleMap(db)
+		weights := map[internal.VehicleField]float64{internal.FieldBrand: 0.2, internal.FieldYear: 2, internal.FieldMaxSpeed: 1, internal.FieldWeight: 0.5}
+
+		// full scan
+		ix := newSimilarIndex(db)
+		var expected []int
+		distances := make(map[int]float64)
+		for id, v := range db {
+			if id == 42 {
+				continue
+			}
+			var d2 float64
+			for i, field := range similarAxes {
+				diff := ix.point(v)[i] - ix.point(db[42])[i]
+				d2 += weights[field] * diff * diff
+			}
+			if v.Brand != db[42].Brand {
+				d2 += weights[internal.FieldBrand]
+			}
+			distances[id] = math.Sqrt(d2)
+			expected = append(expected, id)
+		}
+		sort.Slice(expected, func(i, j int) bool {
+			if distances[expected[i]] != distances[expected[j]] {
+				return distances[expected[i]] < distances[expected[j]]
+			}
+			return expected[i] < expected[j]
+		})
+
+		// act
+		similar, err := rp.Similar(internal.VehicleSimilarQuery{Id: 42, K: 10, Weights: weights}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, similar, 10)
+		for i, s := range similar {
+			require.Equal(t, expected[i], s.Vehicle.Id)
+			require.InDelta(t, distances[expected[i]], s.Distance, 1e-9)
+		}
+	})
+
+	t.Run("case 2: filtered and deleted vehicles are skipped", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{FuelType: "diesel", MaxSpeed: 100}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{FuelType: "diesel", MaxSpeed: 101}},
+			3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{FuelType: "gasoline", MaxSpeed: 102}},
+			4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{FuelType: "diesel", MaxSpeed: 200}},
+		})
+		require.NoError(t, rp.DeleteById(2))
+		weights := map[internal.VehicleField]float64{internal.FieldMaxSpeed: 1}
+		filter := internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldFuelType: "Diesel"}}
+
+		// act
+		similar, err := rp.Similar(internal.VehicleSimilarQuery{Id: 1, K: 10, Weights: weights, Filter: filter}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, similar, 1)
+		require.Equal(t, 4, similar[0].Vehicle.Id)
+	})
+
+	t.Run("case 3: writes are seen by the next search", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{MaxSpeed: 100}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{MaxSpeed: 200}},
+		})
+		query := internal.VehicleSimilarQuery{Id: 1, K: 1, Weights: map[internal.VehicleField]float64{internal.FieldMaxSpeed: 1}}
+		_, err := rp.Similar(query, internal.VehicleQuery{})
+		require.NoError(t, err)
+		require.NoError(t, rp.Add(internal.Vehicle{Id: 3, VehicleAttributes: internal.VehicleAttributes{MaxSpeed: 110}}))
+
+		// act
+		similar, err := rp.Similar(query, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 3, similar[0].Vehicle.Id)
+	})
+
+	t.Run("case 4: unknown reference vehicle", func(t *testing.T) {
+		// arrange
+		rp := NewVehicleMap(nil)
+
+		// act
+		_, err := rp.Similar(internal.VehicleSimilarQuery{Id: 1, K: 1}, internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrorVehicleNotFound)
+	})
+}
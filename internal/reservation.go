@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrReservationNotFound = errors.New("Reservation not found")
+	ErrReservationConflict = errors.New("Vehicle already reserved")
+	ErrInvalidReservation  = errors.New("Invalid reservation")
+)
+
+// Reservation is a struct that represents a vehicle booked for a period [From, To)
+type Reservation struct {
+	// Id is the unique identifier of the reservation within its tenant
+	Id int
+	// VehicleId is the id of the reserved vehicle
+	VehicleId int
+	// Holder is who the vehicle is booked for
+	Holder string
+	// Purpose is a free description of the trip
+	Purpose string
+	// From is the moment the reservation starts
+	From time.Time
+	// To is the moment the reservation ends, excluded
+	To time.Time
+	// CreatedAt is the moment the reservation was made
+	CreatedAt time.Time
+	// CanceledAt is the moment the reservation was canceled, nil while it is active
+	CanceledAt *time.Time
+}
+
+// IsActive is a method that reports if the reservation has not been canceled
+func (r Reservation) IsActive() bool {
+	return r.CanceledAt == nil
+}
+
+// Overlaps is a method that reports if the reservation is active and shares some time with a period [from, to)
+// - a reservation ending when another one starts does not overlap with it
+func (r Reservation) Overlaps(from time.Time, to time.Time) bool {
+	return r.IsActive() && r.From.Before(to) && from.Before(r.To)
+}
+
+// AvailabilityQuery is a struct that represents the vehicles to look for that are free for a period [From, To)
+type AvailabilityQuery struct {
+	// From is the moment the period starts
+	From time.Time
+	// To is the moment the period ends, excluded
+	To time.Time
+	// Filter are the conditions the vehicles must meet, e.g. a minimum capacity
+	Filter VehicleFilter
+}
+
+// ReservationRepository is an interface that represents a reservation repository
+type ReservationRepository interface {
+	// FindById is a method that returns a reservation by id
+	FindById(id int) (r Reservation, err error)
+	// FindByVehicle is a method that returns the reservations of a vehicle, canceled or not, by start
+	FindByVehicle(vehicleId int) (r []Reservation, err error)
+	// FindOverlapping is a method that returns the active reservations that overlap with a period [from, to)
+	FindOverlapping(from time.Time, to time.Time) (r []Reservation, err error)
+	// Reserve is a method that adds a reservation and returns its id, it cannot overlap with the active ones of its vehicle
+	Reserve(r Reservation) (id int, err error)
+	// Cancel is a method that cancels a reservation at a moment and returns it
+	Cancel(id int, at time.Time) (r Reservation, err error)
+}
+
+// ReservationService is an interface that represents a reservation service
+type ReservationService interface {
+	// Reserve is a method that books a vehicle for a period and returns the stored reservation
+	Reserve(r Reservation) (stored Reservation, err error)
+	// Reservation is a method that returns a reservation of a vehicle, canceled or not
+	Reservation(vehicleId int, id int) (r Reservation, err error)
+	// Cancel is a method that cancels a reservation of a vehicle and returns it
+	Cancel(vehicleId int, id int) (r Reservation, err error)
+	// VehicleReservations is a method that returns the reservations of a vehicle, canceled or not, by start
+	VehicleReservations(vehicleId int) (r []Reservation, err error)
+	// Available is a method that returns the vehicles matching a filter that are free for a period, by id
+	Available(q AvailabilityQuery) (v []Vehicle, err error)
+}
+
+// ReservationServiceProvider is an interface that represents the source of the reservation service of each request
+type ReservationServiceProvider interface {
+	// Service is a method that returns the reservation service for a context
+	Service(ctx context.Context) ReservationService
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"app/internal"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewAuthDefault is a function that returns a new instance of AuthDefault
+func NewAuthDefault(cfg internal.AuthConfig) (a *AuthDefault, err error) {
+	a = &AuthDefault{
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		rolesClaim:  cfg.RolesClaim,
+		tenantClaim: cfg.TenantClaim,
+		leeway:      cfg.Leeway,
+		keys:        make(map[string]internal.JWTKey),
+		now:         time.Now,
+	}
+	if a.rolesClaim == "" {
+		a.rolesClaim = "roles"
+	}
+	if a.tenantClaim == "" {
+		a.tenantClaim = "tenant"
+	}
+
+	for _, k := range cfg.APIKeys {
+		hash, err := hex.DecodeString(k.Hash)
+		if k.Id == "" || err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("%w: api key %q needs an id and a hexadecimal sha256", internal.ErrInvalidAuthConfig, k.Id)
+		}
+		a.apiKeys = append(a.apiKeys, apiKeyHash{key: k, hash: hash})
+	}
+	for _, k := range cfg.JWTKeys {
+		switch {
+		case k.Id == "":
+			return nil, fmt.Errorf("%w: jwt keys need a kid", internal.ErrInvalidAuthConfig)
+		case k.Algorithm == "HS256" && len(k.Secret) < 32:
+			return nil, fmt.Errorf("%w: key %s: HS256 secrets need at least 32 bytes", internal.ErrInvalidAuthConfig, k.Id)
+		case k.Algorithm == "RS256" && k.PublicKey == nil:
+			return nil, fmt.Errorf("%w: key %s: RS256 keys need a public key", internal.ErrInvalidAuthConfig, k.Id)
+		case k.Algorithm != "HS256" && k.Algorithm != "RS256":
+			return nil, fmt.Errorf("%w: key %s: unsupported algorithm %q", internal.ErrInvalidAuthConfig, k.Id, k.Algorithm)
+		}
+		if _, ok := a.keys[k.Id]; ok {
+			return nil, fmt.Errorf("%w: key %s is repeated", internal.ErrInvalidAuthConfig, k.Id)
+		}
+		a.keys[k.Id] = k
+	}
+
+	return
+}
+
+// apiKeyHash is a struct that represents an API key with its decoded hash
+type apiKeyHash struct {
+	key  internal.APIKey
+	hash []byte
+}
+
+// AuthDefault is a struct that implements the Authenticator interface
+// - the API keys are compared by their SHA-256 in constant time
+// - the tokens are verified with the standard library, only HS256 and RS256 are accepted
+type AuthDefault struct {
+	// apiKeys are the accepted API keys
+	apiKeys []apiKeyHash
+	// keys is a map of the keys of the tokens by kid
+	keys map[string]internal.JWTKey
+	// issuer is the iss claim the tokens must have, any when empty
+	issuer string
+	// audience is the aud claim the tokens must contain, any when empty
+	audience string
+	// rolesClaim is the claim with the roles of the tokens
+	rolesClaim string
+	// tenantClaim is the claim with the tenant of the tokens
+	tenantClaim string
+	// leeway is the clock skew tolerated on the time claims
+	leeway time.Duration
+	// now is the clock the expiration is checked against
+	now func() time.Time
+}
+
+// AuthenticateAPIKey is a method that returns the principal of an API key
+func (a *AuthDefault) AuthenticateAPIKey(key string) (p internal.Principal, err error) {
+	if key == "" {
+		return p, internal.ErrUnauthenticated
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	// every key is compared so the time does not tell which one matched
+	var found *internal.APIKey
+	for i := range a.apiKeys {
+		if subtle.ConstantTimeCompare(sum[:], a.apiKeys[i].hash) == 1 {
+			found = &a.apiKeys[i].key
+		}
+	}
+	if found == nil {
+		return p, fmt.Errorf("%w: unknown api key", internal.ErrInvalidCredentials)
+	}
+
+	p = internal.Principal{
+		Id:     found.Id,
+		Method: internal.AuthMethodAPIKey,
+		Roles:  append([]string(nil), found.Roles...),
+		Tenant: found.Tenant,
+	}
+	return
+}
+
+// jwtHeader is a struct that represents the header of a token
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyId     string `json:"kid"`
+}
+
+// AuthenticateToken is a method that returns the principal of a JSON Web Token
+func (a *AuthDefault) AuthenticateToken(token string) (p internal.Principal, err error) {
+	if token == "" {
+		return p, internal.ErrUnauthenticated
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return p, fmt.Errorf("%w: malformed token", internal.ErrInvalidCredentials)
+	}
+
+	// header
+	var header jwtHeader
+	if err = decodeSegment(parts[0], &header); err != nil {
+		return p, fmt.Errorf("%w: malformed header", internal.ErrInvalidCredentials)
+	}
+	key, err := a.key(header)
+	if err != nil {
+		return
+	}
+
+	// signature: the algorithm is the one of the key, never the one of the header
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return p, fmt.Errorf("%w: malformed signature", internal.ErrInvalidCredentials)
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	switch key.Algorithm {
+	case "HS256":
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(signed)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return p, fmt.Errorf("%w: invalid signature", internal.ErrInvalidCredentials)
+		}
+	case "RS256":
+		digest := sha256.Sum256(signed)
+		if rsa.VerifyPKCS1v15(key.PublicKey, crypto.SHA256, digest[:], signature) != nil {
+			return p, fmt.Errorf("%w: invalid signature", internal.ErrInvalidCredentials)
+		}
+	}
+
+	// claims
+	var claims map[string]any
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return p, fmt.Errorf("%w: malformed claims", internal.ErrInvalidCredentials)
+	}
+	if err = a.validateClaims(claims); err != nil {
+		return
+	}
+
+	tenant, _ := claims[a.tenantClaim].(string)
+	p = internal.Principal{
+		Id:     claims["sub"].(string),
+		Method: internal.AuthMethodJWT,
+		Roles:  stringsClaim(claims[a.rolesClaim]),
+		Tenant: tenant,
+		Claims: claims,
+	}
+	return
+}
+
+// key is a method that returns the key a token is verified against
+// - without kid, the only key of the algorithm of the header
+func (a *AuthDefault) key(header jwtHeader) (key internal.JWTKey, err error) {
+	if header.KeyId != "" {
+		key, ok := a.keys[header.KeyId]
+		if !ok {
+			return key, fmt.Errorf("%w: unknown key %s", internal.ErrInvalidCredentials, header.KeyId)
+		}
+		if key.Algorithm != header.Algorithm {
+			return key, fmt.Errorf("%w: key %s does not sign with %s", internal.ErrInvalidCredentials, key.Id, header.Algorithm)
+		}
+		return key, nil
+	}
+
+	var matches int
+	for _, k := range a.keys {
+		if k.Algorithm == header.Algorithm {
+			key = k
+			matches++
+		}
+	}
+	if matches != 1 {
+		return key, fmt.Errorf("%w: no key for the token, set its kid", internal.ErrInvalidCredentials)
+	}
+	return
+}
+
+// validateClaims is a method that checks the registered claims of a token
+func (a *AuthDefault) validateClaims(claims map[string]any) (err error) {
+	if sub, ok := claims["sub"].(string); !ok || sub == "" {
+		return fmt.Errorf("%w: the token has no subject", internal.ErrInvalidCredentials)
+	}
+
+	now := a.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: the token has no expiration", internal.ErrInvalidCredentials)
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(a.leeway)) {
+		return fmt.Errorf("%w: the token is expired", internal.ErrInvalidCredentials)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.leeway).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: the token is not valid yet", internal.ErrInvalidCredentials)
+	}
+
+	if a.issuer != "" && claims["iss"] != a.issuer {
+		return fmt.Errorf("%w: unexpected issuer", internal.ErrInvalidCredentials)
+	}
+	if a.audience != "" {
+		var found bool
+		for _, aud := range stringsClaim(claims["aud"]) {
+			found = found || aud == a.audience
+		}
+		if !found {
+			return fmt.Errorf("%w: unexpected audience", internal.ErrInvalidCredentials)
+		}
+	}
+	return
+}
+
+// decodeSegment is a function that decodes a base64url JSON segment of a token
+func decodeSegment(segment string, v any) (err error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(bytes, v)
+}
+
+// stringsClaim is a function that returns a claim that can be a string or an array of strings
+func stringsClaim(claim any) (values []string) {
+	switch c := claim.(type) {
+	case string:
+		values = strings.Fields(c)
+	case []any:
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	return
+}
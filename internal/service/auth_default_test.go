@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signToken is a function that returns a token signed with a HS256 secret or a RS256 private key
+func signToken(t *testing.T, header, claims map[string]any, secret []byte, private *rsa.PrivateKey) string {
+	t.Helper()
+	segment := func(v any) string {
+		bytes, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(bytes)
+	}
+	signed := segment(header) + "." + segment(claims)
+
+	var signature []byte
+	if private != nil {
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	} else {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Tests for AuthDefault
+func TestAuthDefault_AuthenticateAPIKey(t *testing.T) {
+	sum := sha256.Sum256([]byte("s3cr3t-key"))
+	au, err := service.NewAuthDefault(internal.AuthConfig{
+		APIKeys: []internal.APIKey{{Id: "importer", Hash: hex.EncodeToString(sum[:]), Roles: []string{"fleet_manager"}}},
+	})
+	require.NoError(t, err)
+
+	t.Run("case 1: known key", func(t *testing.T) {
+		// act
+		p, err := au.AuthenticateAPIKey("s3cr3t-key")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, internal.Principal{Id: "importer", Method: internal.AuthMethodAPIKey, Roles: []string{"fleet_manager"}}, p)
+	})
+
+	t.Run("case 2: unknown key", func(t *testing.T) {
+		// act
+		_, err := au.AuthenticateAPIKey("other-key")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidCredentials)
+	})
+
+	t.Run("case 3: no key", func(t *testing.T) {
+		// act
+		_, err := au.AuthenticateAPIKey("")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrUnauthenticated)
+	})
+
+	t.Run("case 4: keys are stored as sha256", func(t *testing.T) {
+		// act
+		_, err := service.NewAuthDefault(internal.AuthConfig{APIKeys: []internal.APIKey{{Id: "plain", Hash: "s3cr3t-key"}}})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidAuthConfig)
+	})
+}
+
+func TestAuthDefault_AuthenticateToken(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	au, err := service.NewAuthDefault(internal.AuthConfig{
+		JWTKeys: []internal.JWTKey{
+			{Id: "hs", Algorithm: "HS256", Secret: secret},
+			{Id: "rs", Algorithm: "RS256", PublicKey: &private.PublicKey},
+		},
+		Issuer:   "https://issuer.example",
+		Audience: "vehicles",
+		Leeway:   time.Minute,
+	})
+	require.NoError(t, err)
+	claims := func(exp time.Time) map[string]any {
+		return map[string]any{"sub": "jane", "iss": "https://issuer.example", "aud": []string{"vehicles", "other"}, "exp": exp.Unix(), "roles": []string{"viewer"}}
+	}
+	later := time.Now().Add(time.Hour)
+
+	t.Run("case 1: HS256 token", func(t *testing.T) {
+		// arrange
+		token := signToken(t, map[string]any{"alg": "HS256", "kid": "hs"}, claims(later), secret, nil)
+
+		// act
+		p, err := au.AuthenticateToken(token)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "jane", p.Id)
+		require.Equal(t, internal.AuthMethodJWT, p.Method)
+		require.Equal(t, []string{"viewer"}, p.Roles)
+	})
+
+	t.Run("case 2: RS256 token", func(t *testing.T) {
+		// arrange
+		token := signToken(t, map[string]any{"alg": "RS256", "kid": "rs"}, claims(later), nil, private)
+
+		// act
+		p, err := au.AuthenticateToken(token)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "jane", p.Id)
+	})
+
+	t.Run("case 3: expired token, beyond the leeway", func(t *testing.T) {
+		// arrange
+		token := signToken(t, map[string]any{"alg": "HS256", "kid": "hs"}, claims(time.Now().Add(-2*time.Minute)), secret, nil)
+
+		// act
+		_, err := au.AuthenticateToken(token)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidCredentials)
+		require.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("case 4: bad signature", func(t *testing.T) {
+		// arrange
+		token := signToken(t, map[string]any{"alg": "HS256", "kid": "hs"}, claims(later), []byte("another secret of at least 32 bytes"), nil)
+
+		// act
+		_, err := au.AuthenticateToken(token)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidCredentials)
+		require.ErrorContains(t, err, "signature")
+	})
+
+	t.Run("case 5: algorithm of the header other than the key's", func(t *testing.T) {
+		// arrange: the public key used as a HMAC secret must never verify
+		token := signToken(t, map[string]any{"alg": "HS256", "kid": "rs"}, claims(later), secret, nil)
+
+		// act
+		_, err := au.AuthenticateToken(token)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidCredentials)
+	})
+
+	t.Run("case 6: unsigned token", func(t *testing.T) {
+		// arrange
+		token := signToken(t, map[string]any{"alg": "none"}, claims(later), secret, nil)
+
+		// act
+		_, err := au.AuthenticateToken(token)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidCredentials)
+	})
+
+	t.Run("case 7: other audience", func(t *testing.T) {
+		// arrange
+		c := claims(later)
+		c["aud"] = "billing"
+		token := signToken(t, map[string]any{"alg": "HS256", "kid": "hs"}, c, secret, nil)
+
+		// act
+		_, err := au.AuthenticateToken(token)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidCredentials)
+		require.ErrorContains(t, err, "audience")
+	})
+
+	t.Run("case 8: without kid, the only key of the algorithm", func(t *testing.T) {
+		// arrange
+		token := signToken(t, map[string]any{"alg": "RS256"}, claims(later), nil, private)
+
+		// act
+		_, err := au.AuthenticateToken(token)
+
+		// assert
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+)
+
+// serviceProvider is an interface that represents the provider of the service S of each context
+type serviceProvider[S any] interface {
+	// Service is a method that returns the service of a context
+	Service(ctx context.Context) S
+}
+
+// binder is a function that returns a service that authorizes the calls of a principal before delegating them
+type binder[S any] func(sv S, az internal.Authorizer, p *internal.Principal) S
+
+// NewAuthorizedProvider is a function that returns a new instance of AuthorizedProvider
+func NewAuthorizedProvider[S any](sp serviceProvider[S], az internal.Authorizer, bind binder[S]) *AuthorizedProvider[S] {
+	return &AuthorizedProvider[S]{sp: sp, az: az, bind: bind}
+}
+
+// AuthorizedProvider is a struct that implements the service providers of the authorized services
+// - the service of each context is bound to its principal
+type AuthorizedProvider[S any] struct {
+	// sp is the provider of the services the authorized calls are delegated to
+	sp serviceProvider[S]
+	// az is the authorizer of the principals
+	az internal.Authorizer
+	// bind returns the authorized service of a principal
+	bind binder[S]
+}
+
+// Service is a method that returns the service of the principal of a context, anonymous when there is none
+func (p *AuthorizedProvider[S]) Service(ctx context.Context) S {
+	var pr *internal.Principal
+	if value, ok := internal.PrincipalFrom(ctx); ok {
+		pr = &value
+	}
+	return p.bind(p.sp.Service(ctx), p.az, pr)
+}
+
+// authorization is a struct that checks the permissions of the operations of a service for a principal
+// - embedded by the authorized services
+type authorization struct {
+	// az is the authorizer of the principal
+	az internal.Authorizer
+	// p is the principal of the calls, nil when anonymous
+	p *internal.Principal
+	// operations is the permission of each operation of the service
+	operations map[string]internal.Permission
+}
+
+// authorize is a method that checks the permission of an operation
+func (a authorization) authorize(operation string) (err error) {
+	return a.az.Authorize(a.p, a.operations[operation])
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"app/internal"
+)
+
+// NewDepotAuthorized is a function that returns a new instance of DepotAuthorized
+func NewDepotAuthorized(sv internal.DepotService, az internal.Authorizer, p *internal.Principal) internal.DepotService {
+	return &DepotAuthorized{authorization: authorization{az: az, p: p, operations: internal.DepotOperations}, sv: sv}
+}
+
+// DepotAuthorized is a struct that implements the DepotService interface
+// - every method requires the permission of internal.DepotOperations before being delegated
+type DepotAuthorized struct {
+	authorization
+	// sv is the service the authorized calls are delegated to
+	sv internal.DepotService
+}
+
+// FindAll is a method that returns a map of all depots
+func (s *DepotAuthorized) FindAll() (d map[int]internal.Depot, err error) {
+	if err = s.authorize("FindAll"); err != nil {
+		return
+	}
+	return s.sv.FindAll()
+}
+
+// FindById is a method that returns a depot by id
+func (s *DepotAuthorized) FindById(id int) (d internal.Depot, err error) {
+	if err = s.authorize("FindById"); err != nil {
+		return
+	}
+	return s.sv.FindById(id)
+}
+
+// Add is a method that adds a depot and returns its id
+func (s *DepotAuthorized) Add(d internal.Depot) (id int, err error) {
+	if err = s.authorize("Add"); err != nil {
+		return
+	}
+	return s.sv.Add(d)
+}
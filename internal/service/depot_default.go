@@ -0,0 +1,42 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+)
+
+// NewDepotDefault is a function that returns a new instance of DepotDefault
+func NewDepotDefault(rp internal.DepotRepository, tenant string) *DepotDefault {
+	return &DepotDefault{rp: rp, tenant: tenant}
+}
+
+// DepotDefault is a struct that represents the default service for depots
+type DepotDefault struct {
+	// tenant is the tenant that owns the depots
+	tenant string
+	// rp is the repository that will be used by the service
+	rp internal.DepotRepository
+}
+
+// FindAll is a method that returns a map of all depots
+func (s *DepotDefault) FindAll() (d map[int]internal.Depot, err error) {
+	return s.rp.FindAll()
+}
+
+// FindById is a method that returns a depot by id
+func (s *DepotDefault) FindById(id int) (d internal.Depot, err error) {
+	return s.rp.FindById(id)
+}
+
+// Add is a method that adds a depot of the tenant and returns its id
+func (s *DepotDefault) Add(d internal.Depot) (id int, err error) {
+	d.Name = strings.TrimSpace(d.Name)
+	d.City = strings.TrimSpace(d.City)
+	if d.Name == "" {
+		return 0, fmt.Errorf("%w: name is required", internal.ErrInvalidDepot)
+	}
+
+	d.Tenant = s.tenant
+	return s.rp.Add(d)
+}
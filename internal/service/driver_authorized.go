@@ -0,0 +1,74 @@
+package service
+
+import (
+	"app/internal"
+)
+
+// NewDriverAuthorized is a function that returns a new instance of DriverAuthorized
+func NewDriverAuthorized(sv internal.DriverService, az internal.Authorizer, p *internal.Principal) internal.DriverService {
+	return &DriverAuthorized{authorization: authorization{az: az, p: p, operations: internal.DriverOperations}, sv: sv}
+}
+
+// DriverAuthorized is a struct that implements the DriverService interface
+// - every method requires the permission of internal.DriverOperations before being delegated
+type DriverAuthorized struct {
+	authorization
+	// sv is the service the authorized calls are delegated to
+	sv internal.DriverService
+}
+
+// FindAll is a method that returns a map of all drivers
+func (s *DriverAuthorized) FindAll() (d map[int]internal.Driver, err error) {
+	if err = s.authorize("FindAll"); err != nil {
+		return
+	}
+	return s.sv.FindAll()
+}
+
+// FindById is a method that returns a driver by id
+func (s *DriverAuthorized) FindById(id int) (d internal.Driver, err error) {
+	if err = s.authorize("FindById"); err != nil {
+		return
+	}
+	return s.sv.FindById(id)
+}
+
+// Add is a method that adds a driver and returns its id
+func (s *DriverAuthorized) Add(d internal.Driver) (id int, err error) {
+	if err = s.authorize("Add"); err != nil {
+		return
+	}
+	return s.sv.Add(d)
+}
+
+// Assign is a method that puts a driver in charge of a vehicle
+func (s *DriverAuthorized) Assign(vehicleId int, driverId int) (a internal.Assignment, err error) {
+	if err = s.authorize("Assign"); err != nil {
+		return
+	}
+	return s.sv.Assign(vehicleId, driverId)
+}
+
+// Unassign is a method that ends the current assignment of a vehicle
+func (s *DriverAuthorized) Unassign(vehicleId int) (a internal.Assignment, err error) {
+	if err = s.authorize("Unassign"); err != nil {
+		return
+	}
+	return s.sv.Unassign(vehicleId)
+}
+
+// VehicleAssignments is a method that returns the history of the assignments of a vehicle
+func (s *DriverAuthorized) VehicleAssignments(vehicleId int) (a []internal.Assignment, err error) {
+	if err = s.authorize("VehicleAssignments"); err != nil {
+		return
+	}
+	return s.sv.VehicleAssignments(vehicleId)
+}
+
+// DriverAssignments is a method that returns the history of the assignments of a driver
+func (s *DriverAuthorized) DriverAssignments(driverId int) (a []internal.Assignment, err error) {
+	if err = s.authorize("DriverAssignments"); err != nil {
+		return
+	}
+	return s.sv.DriverAssignments(driverId)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewDriverDefault is a function that returns a new instance of DriverDefault
+// - vr is the repository of the vehicles of the same tenant, the ones the drivers are assigned to
+func NewDriverDefault(rp internal.DriverRepository, vr internal.VehicleRepository, tenant string) *DriverDefault {
+	return &DriverDefault{rp: rp, vr: vr, tenant: tenant, now: time.Now}
+}
+
+// DriverDefault is a struct that represents the default service for drivers and their assignments
+type DriverDefault struct {
+	// tenant is the tenant that employs the drivers
+	tenant string
+	// rp is the repository that will be used by the service
+	rp internal.DriverRepository
+	// vr is the repository of the vehicles the drivers are assigned to
+	vr internal.VehicleRepository
+	// now is the clock of the assignments
+	now func() time.Time
+}
+
+// FindAll is a method that returns a map of all drivers
+func (s *DriverDefault) FindAll() (d map[int]internal.Driver, err error) {
+	return s.rp.FindAll()
+}
+
+// FindById is a method that returns a driver by id
+func (s *DriverDefault) FindById(id int) (d internal.Driver, err error) {
+	return s.rp.FindById(id)
+}
+
+// Add is a method that adds a driver of the tenant and returns its id
+// - the license number is compared without spaces nor dashes and in upper case
+func (s *DriverDefault) Add(d internal.Driver) (id int, err error) {
+	d.Name = strings.TrimSpace(d.Name)
+	d.LicenseNumber = internal.NormalizeLicenseNumber(d.LicenseNumber)
+	switch {
+	case d.Name == "":
+		return 0, fmt.Errorf("%w: name is required", internal.ErrInvalidDriver)
+	case d.LicenseNumber == "":
+		return 0, fmt.Errorf("%w: license number is required", internal.ErrInvalidDriver)
+	}
+
+	d.Tenant = s.tenant
+	return s.rp.Add(d)
+}
+
+// Assign is a method that puts a driver in charge of a vehicle from now on
+// - deleted vehicles cannot be assigned
+func (s *DriverDefault) Assign(vehicleId int, driverId int) (a internal.Assignment, err error) {
+	if _, err = s.vr.FindById(vehicleId, internal.VehicleQuery{}); err != nil {
+		return
+	}
+
+	a = internal.Assignment{VehicleId: vehicleId, DriverId: driverId, From: s.now()}
+	a.Id, err = s.rp.Assign(a)
+	return
+}
+
+// Unassign is a method that ends the current assignment of a vehicle now
+func (s *DriverDefault) Unassign(vehicleId int) (a internal.Assignment, err error) {
+	return s.rp.Unassign(vehicleId, s.now())
+}
+
+// VehicleAssignments is a method that returns the history of the assignments of a vehicle, oldest first
+// - the history of the deleted vehicles is kept
+func (s *DriverDefault) VehicleAssignments(vehicleId int) (a []internal.Assignment, err error) {
+	if _, err = s.vr.FindById(vehicleId, internal.VehicleQuery{IncludeDeleted: true}); err != nil {
+		return
+	}
+	return s.rp.Assignments(internal.AssignmentQuery{VehicleId: vehicleId})
+}
+
+// DriverAssignments is a method that returns the history of the assignments of a driver, oldest first
+func (s *DriverDefault) DriverAssignments(driverId int) (a []internal.Assignment, err error) {
+	if _, err = s.rp.FindById(driverId); err != nil {
+		return
+	}
+	return s.rp.Assignments(internal.AssignmentQuery{DriverId: driverId})
+}
@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for DriverDefault
+func TestDriverDefault(t *testing.T) {
+	// newService is a function that returns a service with the driver 1 and the fleet
+	newService := func() *service.DriverDefault {
+		dr := repository.NewDriverMap(map[int]internal.Driver{1: {Id: 1, Name: "Jane Doe", LicenseNumber: "LIC1"}})
+		return service.NewDriverDefault(dr, newFleet(), "acme")
+	}
+
+	t.Run("case 1: license numbers are normalized and unique", func(t *testing.T) {
+		// arrange
+		sv := newService()
+
+		// act
+		id, err := sv.Add(internal.Driver{Name: " John Roe ", LicenseNumber: "lic 2"})
+		d, _ := sv.FindById(id)
+		_, errRepeated := sv.Add(internal.Driver{Name: "Jane Smith", LicenseNumber: "lic-1"})
+		_, errInvalid := sv.Add(internal.Driver{Name: "Nobody"})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, internal.Driver{Id: id, Tenant: "acme", Name: "John Roe", LicenseNumber: "LIC2"}, d)
+		require.ErrorIs(t, errRepeated, internal.ErrDriverAlreadyExists)
+		require.ErrorIs(t, errInvalid, internal.ErrInvalidDriver)
+	})
+
+	t.Run("case 2: only existing vehicles are assigned", func(t *testing.T) {
+		// arrange
+		sv := newService()
+
+		// act
+		a, err := sv.Assign(1, 1)
+		_, errDeleted := sv.Assign(6, 1)
+		_, errUnknown := sv.Assign(9, 1)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, a.VehicleId)
+		require.True(t, a.IsCurrent())
+		require.ErrorIs(t, errDeleted, internal.ErrorVehicleNotFound)
+		require.ErrorIs(t, errUnknown, internal.ErrorVehicleNotFound)
+	})
+
+	t.Run("case 3: history of a vehicle", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		sv.Assign(1, 1)
+		sv.Unassign(1)
+
+		// act
+		history, err := sv.VehicleAssignments(1)
+		deleted, errDeleted := sv.VehicleAssignments(6)
+		_, errUnknown := sv.DriverAssignments(9)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.False(t, history[0].IsCurrent())
+		require.NoError(t, errDeleted)
+		require.Empty(t, deleted)
+		require.ErrorIs(t, errUnknown, internal.ErrDriverNotFound)
+	})
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+)
+
+// NewEmissionsDefault is a function that returns a new instance of EmissionsDefault
+// - the fuel types are case insensitive and each one has a single factor
+func NewEmissionsDefault(rules internal.EmissionRules) (e *EmissionsDefault, err error) {
+	e = &EmissionsDefault{
+		currency: rules.Currency,
+		factors:  make(map[string]internal.EmissionFactor),
+	}
+
+	for _, f := range rules.Factors {
+		fuelType := strings.ToLower(f.FuelType)
+		if err = ValidateFuelType(fuelType); err != nil {
+			return nil, fmt.Errorf("emission factor %s: %w", f.FuelType, err)
+		}
+		if f.Unit == "" || f.Base < 0 || f.PerTonne < 0 || f.PerKmh < 0 || f.CO2PerUnit < 0 || f.PricePerUnit < 0 {
+			return nil, fmt.Errorf("%w: emission factor %s: unit is required and values can not be negative", internal.ErrInvalidEmissionRules, f.FuelType)
+		}
+		if _, ok := e.factors[fuelType]; ok {
+			return nil, fmt.Errorf("%w: emission factor %s: repeated", internal.ErrInvalidEmissionRules, f.FuelType)
+		}
+		e.factors[fuelType] = f
+	}
+
+	return
+}
+
+// EmissionsDefault is a struct that implements the VehicleEmissionsEstimator interface with a linear consumption model
+type EmissionsDefault struct {
+	// currency is the currency of the prices
+	currency string
+	// factors is a map of the emission factor of each fuel type
+	factors map[string]internal.EmissionFactor
+}
+
+// Estimate is a method that returns the emissions of a vehicle driven a distance every year
+func (e *EmissionsDefault) Estimate(v internal.Vehicle, kmPerYear float64) (em internal.VehicleEmissions, err error) {
+	f, ok := e.factors[strings.ToLower(v.FuelType)]
+	if !ok {
+		return em, fmt.Errorf("%w: %s", internal.ErrNoEmissionFactor, v.FuelType)
+	}
+
+	em = internal.VehicleEmissions{
+		Vehicle:             v,
+		Unit:                f.Unit,
+		ConsumptionPer100Km: f.Base + f.PerTonne*v.Weight/1000 + f.PerKmh*v.MaxSpeed,
+		KmPerYear:           kmPerYear,
+		Currency:            e.currency,
+	}
+	em.FuelPerYear = em.ConsumptionPer100Km * kmPerYear / 100
+	em.CO2PerKm = em.ConsumptionPer100Km / 100 * f.CO2PerUnit * 1000
+	em.CO2PerYear = em.FuelPerYear * f.CO2PerUnit
+	em.CostPerKm = em.ConsumptionPer100Km / 100 * f.PricePerUnit
+	em.CostPerYear = em.FuelPerYear * f.PricePerUnit
+
+	return
+}
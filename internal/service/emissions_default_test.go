@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for EmissionsDefault
+func TestEmissionsDefault_Estimate(t *testing.T) {
+	em, err := service.NewEmissionsDefault(internal.EmissionRules{
+		Currency: "EUR",
+		Factors: []internal.EmissionFactor{
+			{FuelType: "diesel", Unit: "l", Base: 4, PerTonne: 2, PerKmh: 0.01, CO2PerUnit: 2.5, PricePerUnit: 1.5},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("case 1: linear consumption, emissions and cost", func(t *testing.T) {
+		// arrange
+		v := internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{FuelType: "Diesel", Weight: 1500, MaxSpeed: 200}}
+
+		// act
+		e, err := em.Estimate(v, 10000)
+
+		// assert
+		require.NoError(t, err)
+		require.InDelta(t, 9, e.ConsumptionPer100Km, 1e-9)
+		require.InDelta(t, 900, e.FuelPerYear, 1e-9)
+		require.InDelta(t, 225, e.CO2PerKm, 1e-9)
+		require.InDelta(t, 2250, e.CO2PerYear, 1e-9)
+		require.InDelta(t, 1350, e.CostPerYear, 1e-9)
+		require.Equal(t, "EUR", e.Currency)
+	})
+
+	t.Run("case 2: fuel type without factor", func(t *testing.T) {
+		// act
+		_, err := em.Estimate(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{FuelType: "electric"}}, 10000)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrNoEmissionFactor)
+	})
+
+	t.Run("case 3: factors only for the accepted fuel types", func(t *testing.T) {
+		// act
+		_, err := service.NewEmissionsDefault(internal.EmissionRules{Factors: []internal.EmissionFactor{{FuelType: "hydrogen", Unit: "kg"}}})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidFuelType)
+	})
+
+	t.Run("case 4: fuel types are case insensitive and have a single factor", func(t *testing.T) {
+		// arrange
+		upper := internal.EmissionFactor{FuelType: "Gasoline", Unit: "l", Base: 5}
+		lower := internal.EmissionFactor{FuelType: "gasoline", Unit: "l", Base: 6}
+
+		// act
+		single, err := service.NewEmissionsDefault(internal.EmissionRules{Factors: []internal.EmissionFactor{upper}})
+		require.NoError(t, err)
+		e, errEstimate := single.Estimate(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{FuelType: "gasoline"}}, 100)
+		_, errRepeated := service.NewEmissionsDefault(internal.EmissionRules{Factors: []internal.EmissionFactor{upper, lower}})
+
+		// assert
+		require.NoError(t, errEstimate)
+		require.InDelta(t, 5, e.ConsumptionPer100Km, 1e-9)
+		require.ErrorIs(t, errRepeated, internal.ErrInvalidEmissionRules)
+	})
+
+	t.Run("case 5: the shipped factors cover every accepted fuel type", func(t *testing.T) {
+		// arrange
+		rules, err := loader.NewEmissionRulesJSONFile("../../docs/emissions/factors.json").Load()
+		require.NoError(t, err)
+		shipped, err := service.NewEmissionsDefault(rules)
+		require.NoError(t, err)
+
+		for _, fuelType := range []string{"biodiesel", "gas", "diesel", "gasoline", "electric"} {
+			// act
+			_, err := shipped.Estimate(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{FuelType: fuelType}}, 1)
+
+			// assert
+			require.NoError(t, err, fuelType)
+		}
+	})
+}
+
+// Tests for the fleet-wide emissions report
+func TestVehicleDefault_GetEmissionsReport(t *testing.T) {
+	em, err := service.NewEmissionsDefault(internal.EmissionRules{Factors: []internal.EmissionFactor{
+		{FuelType: "diesel", Unit: "l", Base: 10, CO2PerUnit: 2, PricePerUnit: 1},
+		{FuelType: "gasoline", Unit: "l", Base: 5, CO2PerUnit: 2, PricePerUnit: 2},
+	}})
+	require.NoError(t, err)
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "diesel"}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", FuelType: "diesel"}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi", FuelType: "gasoline"}},
+		4: {Id: 4, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi", FuelType: "electric"}},
+	})
+	sv := service.NewVehicleDefault(rp, &service.ConfigVehicleDefault{Emissions: em})
+
+	t.Run("case 1: grouped by brand and fuel type", func(t *testing.T) {
+		// act
+		groups, skipped, err := sv.GetEmissionsReport(internal.EmissionsReportQuery{KmPerYear: 1000}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, skipped)
+		require.Equal(t, []internal.EmissionsGroup{
+			{Group: map[internal.VehicleField]string{internal.FieldBrand: "Audi", internal.FieldFuelType: "gasoline"}, Count: 1, CO2PerYear: 100, MeanCO2PerKm: 100, CostPerYear: 100},
+			{Group: map[internal.VehicleField]string{internal.FieldBrand: "Ford", internal.FieldFuelType: "diesel"}, Count: 2, CO2PerYear: 400, MeanCO2PerKm: 200, CostPerYear: 200},
+		}, groups)
+	})
+
+	t.Run("case 2: invalid distance", func(t *testing.T) {
+		// act
+		_, _, err := sv.GetEmissionsReport(internal.EmissionsReportQuery{KmPerYear: -1}, internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidEmissionsQuery)
+	})
+}
@@ -0,0 +1,31 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"fmt"
+	"time"
+)
+
+// newFleet is a function that returns a vehicle repository with the fleet of the tests of the services that refer to vehicles
+// - 1 and 3: Ford Focus, diesel, automatic, 5 and 7 seats
+// - 2: Ford Focus, diesel, manual, 5 seats
+// - 4: Ford Fiesta, diesel, manual, 4 seats
+// - 5: Tesla Model 3, electric, automatic, 5 seats
+// - 6: Ford Focus, diesel, automatic, 5 seats, deleted
+func newFleet() *repository.VehicleMap {
+	deleted := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	vehicles := map[int]internal.Vehicle{
+		1: {VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", FuelType: "diesel", Transmission: "automatic", Capacity: 5}},
+		2: {VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", FuelType: "diesel", Transmission: "manual", Capacity: 5}},
+		3: {VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", FuelType: "diesel", Transmission: "automatic", Capacity: 7}},
+		4: {VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Fiesta", FuelType: "diesel", Transmission: "manual", Capacity: 4}},
+		5: {VehicleAttributes: internal.VehicleAttributes{Brand: "Tesla", Model: "Model 3", FuelType: "electric", Transmission: "automatic", Capacity: 5}},
+		6: {VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Focus", FuelType: "diesel", Transmission: "automatic", Capacity: 5}, DeletedAt: &deleted},
+	}
+	for id, v := range vehicles {
+		v.Id, v.Registration = id, fmt.Sprintf("A-%d", id)
+		vehicles[id] = v
+	}
+	return repository.NewVehicleMap(vehicles)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"app/internal"
+)
+
+// NewFuelAuthorized is a function that returns a new instance of FuelAuthorized
+func NewFuelAuthorized(sv internal.FuelService, az internal.Authorizer, p *internal.Principal) internal.FuelService {
+	return &FuelAuthorized{authorization: authorization{az: az, p: p, operations: internal.FuelOperations}, sv: sv}
+}
+
+// FuelAuthorized is a struct that implements the FuelService interface
+// - every method requires the permission of internal.FuelOperations before being delegated
+type FuelAuthorized struct {
+	authorization
+	// sv is the service the authorized calls are delegated to
+	sv internal.FuelService
+}
+
+// AddLog is a method that records a refuel or a reading of a vehicle and returns its id
+func (s *FuelAuthorized) AddLog(l internal.FuelLog) (id int, err error) {
+	if err = s.authorize("AddLog"); err != nil {
+		return
+	}
+	return s.sv.AddLog(l)
+}
+
+// Report is a method that returns the consumption of a vehicle
+func (s *FuelAuthorized) Report(vehicleId int, window int) (r internal.FuelReport, err error) {
+	if err = s.authorize("Report"); err != nil {
+		return
+	}
+	return s.sv.Report(vehicleId, window)
+}
+
+// Stats is a method that compares the consumption of the vehicles with their peers
+func (s *FuelAuthorized) Stats(q internal.FuelStatsQuery) (st []internal.FuelStats, err error) {
+	if err = s.authorize("Stats"); err != nil {
+		return
+	}
+	return s.sv.Stats(q)
+}
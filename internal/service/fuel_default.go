@@ -0,0 +1,205 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewFuelDefault is a function that returns a new instance of FuelDefault
+// - vr is the repository of the vehicles of the same tenant, the ones the logs are of
+func NewFuelDefault(rp internal.FuelLogRepository, vr internal.VehicleRepository) *FuelDefault {
+	return &FuelDefault{rp: rp, vr: vr, now: time.Now}
+}
+
+// FuelDefault is a struct that represents the default service for the fuel logs and the consumption of the vehicles
+type FuelDefault struct {
+	// mu is the mutex that makes the check of the odometer and the addition of a log atomic
+	mu sync.Mutex
+	// rp is the repository that will be used by the service
+	rp internal.FuelLogRepository
+	// vr is the repository of the vehicles the logs are of
+	vr internal.VehicleRepository
+	// now is the clock of the logs
+	now func() time.Time
+}
+
+// AddLog is a method that records a refuel or a reading of a vehicle and returns its id
+// - the odometer cannot go backwards: a later log cannot have a lower reading than an earlier one
+func (s *FuelDefault) AddLog(l internal.FuelLog) (id int, err error) {
+	if _, err = s.vr.FindById(l.VehicleId, internal.VehicleQuery{}); err != nil {
+		return
+	}
+
+	switch {
+	case l.Date.IsZero():
+		return 0, fmt.Errorf("%w: date is required", internal.ErrInvalidFuelLog)
+	case l.Date.After(s.now()):
+		return 0, fmt.Errorf("%w: date is in the future", internal.ErrInvalidFuelLog)
+	case l.Odometer < 0 || l.Amount < 0 || l.Cost < 0:
+		return 0, fmt.Errorf("%w: odometer, amount and cost cannot be negative", internal.ErrInvalidFuelLog)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs, err := s.rp.FindByVehicle(l.VehicleId)
+	if err != nil {
+		return
+	}
+	for _, e := range logs {
+		if (e.Date.Before(l.Date) && e.Odometer > l.Odometer) || (e.Date.After(l.Date) && e.Odometer < l.Odometer) {
+			return 0, fmt.Errorf("%w: odometer %.0f km goes backwards from log %d", internal.ErrInvalidFuelLog, l.Odometer, e.Id)
+		}
+	}
+
+	return s.rp.Add(l)
+}
+
+// Report is a method that returns the consumption of a vehicle, deleted or not, the rolling average over the last window refuels
+// - a window under 1 is 1
+func (s *FuelDefault) Report(vehicleId int, window int) (r internal.FuelReport, err error) {
+	v, err := s.vr.FindById(vehicleId, internal.VehicleQuery{IncludeDeleted: true})
+	if err != nil {
+		return
+	}
+	logs, err := s.rp.FindByVehicle(vehicleId)
+	if err != nil {
+		return
+	}
+
+	return fuelReport(v, logs, window), nil
+}
+
+// fuelReport is a function that computes the consumption of a vehicle from its logs, oldest first
+// - the amount of a refuel is the one used since the previous refuel, the ones without distance are added to the next one
+func fuelReport(v internal.Vehicle, logs []internal.FuelLog, window int) (r internal.FuelReport) {
+	if window < 1 {
+		window = 1
+	}
+
+	r = internal.FuelReport{VehicleId: v.Id, Unit: internal.FuelUnit(v.FuelType), Logs: make([]internal.FuelConsumption, 0, len(logs))}
+	var previous *internal.FuelLog
+	var pending, rolling float64
+	var distances, amounts []float64
+	for i, l := range logs {
+		c := internal.FuelConsumption{Log: l}
+		switch {
+		case l.Amount == 0:
+			// a reading only
+		case previous == nil:
+			previous = &logs[i]
+		case l.Odometer <= previous.Odometer:
+			pending += l.Amount
+		default:
+			c.Distance = l.Odometer - previous.Odometer
+			c.Consumption = per100Km(pending+l.Amount, c.Distance)
+			r.Distance += c.Distance
+			r.Amount += pending + l.Amount
+			distances, amounts = append(distances, c.Distance), append(amounts, pending+l.Amount)
+			previous, pending = &logs[i], 0
+
+			// the rolling average weighs each refuel by its distance
+			from := len(distances) - window
+			if from < 0 {
+				from = 0
+			}
+			var distance, amount float64
+			for j := from; j < len(distances); j++ {
+				distance += distances[j]
+				amount += amounts[j]
+			}
+			rolling = per100Km(amount, distance)
+		}
+		c.Rolling = rolling
+		r.Logs = append(r.Logs, c)
+	}
+
+	if r.Distance > 0 {
+		r.Consumption = per100Km(r.Amount, r.Distance)
+	}
+	return
+}
+
+// per100Km is a function that returns the amount used every 100 km, rounded to hundredths
+func per100Km(amount float64, distance float64) float64 {
+	return math.Round(amount/distance*100*100) / 100
+}
+
+// Stats is a method that compares the consumption of the vehicles with their peers, the ones of the same brand, model and fuel type
+// - only the vehicles with two refuels at least are compared, groups of less than 3 vehicles have no outliers
+func (s *FuelDefault) Stats(q internal.FuelStatsQuery) (st []internal.FuelStats, err error) {
+	vehicles, err := s.vr.FindAll(internal.VehicleQuery{})
+	if err != nil {
+		return
+	}
+
+	// group the consumption of the vehicles
+	type peer struct {
+		id          int
+		consumption float64
+	}
+	groups := make(map[string][]peer)
+	keys := make(map[string]internal.Vehicle)
+	for _, v := range vehicles {
+		if !q.Filter.Match(v) {
+			continue
+		}
+		logs, err := s.rp.FindByVehicle(v.Id)
+		if err != nil {
+			return nil, err
+		}
+		r := fuelReport(v, logs, 1)
+		if r.Consumption == 0 {
+			continue
+		}
+
+		key := strings.Join([]string{v.Brand, v.Model, v.FuelType}, "\x00")
+		groups[key] = append(groups[key], peer{id: v.Id, consumption: r.Consumption})
+		keys[key] = v
+	}
+
+	// summarize each group, sorted by its key
+	sorted := make([]string, 0, len(groups))
+	for key := range groups {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	st = make([]internal.FuelStats, 0, len(sorted))
+	for _, key := range sorted {
+		peers, v := groups[key], keys[key]
+		values := make([]float64, len(peers))
+		for i, p := range peers {
+			values[i] = p.consumption
+		}
+
+		fs := internal.FuelStats{Stats: internal.Summarize(values, nil), Unit: internal.FuelUnit(v.FuelType), Outliers: make([]internal.FuelOutlier, 0)}
+		fs.Stats.Group = map[internal.VehicleField]string{
+			internal.FieldBrand:    v.Brand,
+			internal.FieldModel:    v.Model,
+			internal.FieldFuelType: v.FuelType,
+		}
+		if len(peers) >= 3 {
+			for _, p := range peers {
+				deviation := math.Round((p.consumption/fs.Stats.Median-1)*1000) / 1000
+				if math.Abs(deviation) >= q.Threshold {
+					fs.Outliers = append(fs.Outliers, internal.FuelOutlier{VehicleId: p.id, Consumption: p.consumption, Deviation: deviation})
+				}
+			}
+			sort.Slice(fs.Outliers, func(i, j int) bool {
+				a, b := math.Abs(fs.Outliers[i].Deviation), math.Abs(fs.Outliers[j].Deviation)
+				if a != b {
+					return a > b
+				}
+				return fs.Outliers[i].VehicleId < fs.Outliers[j].VehicleId
+			})
+		}
+		st = append(st, fs)
+	}
+	return
+}
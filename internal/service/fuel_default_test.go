@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for FuelDefault
+func TestFuelDefault(t *testing.T) {
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 8, 0, 0, 0, time.UTC) }
+
+	// newService is a function that returns a service with the fleet
+	newService := func() *service.FuelDefault {
+		return service.NewFuelDefault(repository.NewFuelLogMap(), newFleet())
+	}
+
+	t.Run("case 1: logs are validated", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		_, err := sv.AddLog(internal.FuelLog{VehicleId: 1, Date: day(10), Odometer: 5000, Amount: 40})
+		require.NoError(t, err)
+
+		// act
+		_, errBackwards := sv.AddLog(internal.FuelLog{VehicleId: 1, Date: day(11), Odometer: 4900, Amount: 40})
+		_, errBefore := sv.AddLog(internal.FuelLog{VehicleId: 1, Date: day(9), Odometer: 5100})
+		_, errFuture := sv.AddLog(internal.FuelLog{VehicleId: 1, Date: time.Now().Add(time.Hour), Odometer: 6000})
+		_, errNegative := sv.AddLog(internal.FuelLog{VehicleId: 1, Date: day(12), Odometer: 6000, Amount: -1})
+		_, errDeleted := sv.AddLog(internal.FuelLog{VehicleId: 6, Date: day(12), Odometer: 6000})
+		_, errEarlier := sv.AddLog(internal.FuelLog{VehicleId: 1, Date: day(9), Odometer: 4900})
+
+		// assert
+		require.ErrorIs(t, errBackwards, internal.ErrInvalidFuelLog)
+		require.ErrorIs(t, errBefore, internal.ErrInvalidFuelLog)
+		require.ErrorIs(t, errFuture, internal.ErrInvalidFuelLog)
+		require.ErrorIs(t, errNegative, internal.ErrInvalidFuelLog)
+		require.ErrorIs(t, errDeleted, internal.ErrorVehicleNotFound)
+		require.NoError(t, errEarlier)
+	})
+
+	t.Run("case 2: consumption between refuels and rolling average", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		for _, l := range []internal.FuelLog{
+			{VehicleId: 1, Date: day(1), Odometer: 1000, Amount: 40},
+			{VehicleId: 1, Date: day(2), Odometer: 1200},
+			{VehicleId: 1, Date: day(3), Odometer: 1500, Amount: 30},
+			{VehicleId: 1, Date: day(4), Odometer: 1500, Amount: 2},
+			{VehicleId: 1, Date: day(5), Odometer: 2000, Amount: 38},
+			{VehicleId: 1, Date: day(6), Odometer: 2500, Amount: 25},
+		} {
+			_, err := sv.AddLog(l)
+			require.NoError(t, err)
+		}
+
+		// act
+		r, err := sv.Report(1, 2)
+
+		// assert: the top-up of the day 4 is used by the refuel of the day 5
+		require.NoError(t, err)
+		require.Equal(t, "l", r.Unit)
+		require.Equal(t, 1500.0, r.Distance)
+		require.Equal(t, 95.0, r.Amount)
+		require.Equal(t, 6.33, r.Consumption)
+		consumption, rolling := make([]float64, 0), make([]float64, 0)
+		for _, c := range r.Logs {
+			consumption, rolling = append(consumption, c.Consumption), append(rolling, c.Rolling)
+		}
+		require.Equal(t, []float64{0, 0, 6, 0, 8, 5}, consumption)
+		require.Equal(t, []float64{0, 0, 6, 6, 7, 6.5}, rolling)
+	})
+
+	t.Run("case 3: electric vehicles in kWh", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		_, err := sv.AddLog(internal.FuelLog{VehicleId: 5, Date: day(1), Odometer: 0, Amount: 60})
+		require.NoError(t, err)
+		_, err = sv.AddLog(internal.FuelLog{VehicleId: 5, Date: day(2), Odometer: 300, Amount: 45})
+		require.NoError(t, err)
+
+		// act
+		r, err := sv.Report(5, 5)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "kWh", r.Unit)
+		require.Equal(t, 15.0, r.Consumption)
+	})
+
+	t.Run("case 4: outliers compared with their peers", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		for id, amount := range map[int]float64{1: 60, 2: 62, 3: 90, 4: 50, 5: 150} {
+			_, err := sv.AddLog(internal.FuelLog{VehicleId: id, Date: day(1), Odometer: 0, Amount: 40})
+			require.NoError(t, err)
+			_, err = sv.AddLog(internal.FuelLog{VehicleId: id, Date: day(2), Odometer: 1000, Amount: amount})
+			require.NoError(t, err)
+		}
+
+		// act
+		st, err := sv.Stats(internal.FuelStatsQuery{
+			Filter:    internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldBrand: "Ford"}},
+			Threshold: 0.25,
+		})
+
+		// assert: the Fiesta has no peers to be compared with
+		require.NoError(t, err)
+		require.Len(t, st, 2)
+		require.Equal(t, "Fiesta", st[0].Stats.Group[internal.FieldModel])
+		require.Equal(t, 1, st[0].Stats.Count)
+		require.Empty(t, st[0].Outliers)
+		require.Equal(t, "Focus", st[1].Stats.Group[internal.FieldModel])
+		require.Equal(t, 3, st[1].Stats.Count)
+		require.Equal(t, 6.2, st[1].Stats.Median)
+		require.Equal(t, []internal.FuelOutlier{{VehicleId: 3, Consumption: 9, Deviation: 0.452}}, st[1].Outliers)
+	})
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"app/internal"
+	"time"
+)
+
+// NewMaintenanceAuthorized is a function that returns a new instance of MaintenanceAuthorized
+func NewMaintenanceAuthorized(sv internal.MaintenanceService, az internal.Authorizer, p *internal.Principal) internal.MaintenanceService {
+	return &MaintenanceAuthorized{authorization: authorization{az: az, p: p, operations: internal.MaintenanceOperations}, sv: sv}
+}
+
+// MaintenanceAuthorized is a struct that implements the MaintenanceService interface
+// - every method requires the permission of internal.MaintenanceOperations before being delegated
+type MaintenanceAuthorized struct {
+	authorization
+	// sv is the service the authorized calls are delegated to
+	sv internal.MaintenanceService
+}
+
+// AddRecord is a method that records a service done to a vehicle and returns its id
+func (s *MaintenanceAuthorized) AddRecord(r internal.MaintenanceRecord) (id int, err error) {
+	if err = s.authorize("AddRecord"); err != nil {
+		return
+	}
+	return s.sv.AddRecord(r)
+}
+
+// Records is a method that returns the services of a vehicle
+func (s *MaintenanceAuthorized) Records(vehicleId int) (r []internal.MaintenanceRecord, err error) {
+	if err = s.authorize("Records"); err != nil {
+		return
+	}
+	return s.sv.Records(vehicleId)
+}
+
+// Due is a method that returns the services overdue or due within a time
+func (s *MaintenanceAuthorized) Due(within time.Duration) (d []internal.MaintenanceDue, err error) {
+	if err = s.authorize("Due"); err != nil {
+		return
+	}
+	return s.sv.Due(within)
+}
+
+// Costs is a method that summarizes the costs of the services by group
+func (s *MaintenanceAuthorized) Costs(q internal.MaintenanceCostQuery) (c []internal.MaintenanceCost, err error) {
+	if err = s.authorize("Costs"); err != nil {
+		return
+	}
+	return s.sv.Costs(q)
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NewMaintenanceDefault is a function that returns a new instance of MaintenanceDefault
+// - vr is the repository of the vehicles of the same tenant, the ones the records are of
+func NewMaintenanceDefault(rp internal.MaintenanceRepository, vr internal.VehicleRepository, rules []internal.MaintenanceRule) (s *MaintenanceDefault, err error) {
+	type ruleKey struct{ kind, brand, fuelType string }
+	seen := make(map[ruleKey]bool)
+	rules = append([]internal.MaintenanceRule(nil), rules...)
+	for i, rl := range rules {
+		rl.Type = internal.NormalizeMaintenanceType(rl.Type)
+		key := ruleKey{rl.Type, internal.NormalizeMaintenanceType(rl.Brand), internal.NormalizeMaintenanceType(rl.FuelType)}
+		switch {
+		case rl.Type == "":
+			return nil, fmt.Errorf("%w: rule %d has no type", internal.ErrInvalidMaintenanceRules, i)
+		case rl.Every < 0 || rl.EveryKm < 0:
+			return nil, fmt.Errorf("%w: rule %d: the intervals cannot be negative", internal.ErrInvalidMaintenanceRules, i)
+		case seen[key]:
+			return nil, fmt.Errorf("%w: rule %d: repeated for %s %s %s", internal.ErrInvalidMaintenanceRules, i, rl.Type, rl.Brand, rl.FuelType)
+		}
+		seen[key] = true
+		rules[i] = rl
+	}
+
+	s = &MaintenanceDefault{rp: rp, vr: vr, rules: rules, now: time.Now}
+	return
+}
+
+// MaintenanceDefault is a struct that represents the default service for the maintenance of the vehicles
+type MaintenanceDefault struct {
+	// rp is the repository that will be used by the service
+	rp internal.MaintenanceRepository
+	// vr is the repository of the vehicles the records are of
+	vr internal.VehicleRepository
+	// rules are the intervals of the services by type, brand and fuel type
+	rules []internal.MaintenanceRule
+	// now is the clock of the due services
+	now func() time.Time
+	// mu is the mutex that makes the check of the odometer and the addition of a record atomic
+	mu sync.Mutex
+}
+
+// AddRecord is a method that records a service done to a vehicle and returns its id
+// - the odometer cannot go backwards: a later service cannot have a lower reading than an earlier one
+func (s *MaintenanceDefault) AddRecord(r internal.MaintenanceRecord) (id int, err error) {
+	if _, err = s.vr.FindById(r.VehicleId, internal.VehicleQuery{}); err != nil {
+		return
+	}
+
+	r.Type = internal.NormalizeMaintenanceType(r.Type)
+	switch {
+	case r.Type == "":
+		return 0, fmt.Errorf("%w: type is required", internal.ErrInvalidMaintenanceRecord)
+	case r.Date.IsZero():
+		return 0, fmt.Errorf("%w: date is required", internal.ErrInvalidMaintenanceRecord)
+	case r.Date.After(s.now()):
+		return 0, fmt.Errorf("%w: date is in the future", internal.ErrInvalidMaintenanceRecord)
+	case r.Odometer < 0 || r.Cost < 0:
+		return 0, fmt.Errorf("%w: odometer and cost cannot be negative", internal.ErrInvalidMaintenanceRecord)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.rp.FindByVehicle(r.VehicleId)
+	if err != nil {
+		return
+	}
+	for _, e := range records {
+		if (e.Date.Before(r.Date) && e.Odometer > r.Odometer) || (e.Date.After(r.Date) && e.Odometer < r.Odometer) {
+			return 0, fmt.Errorf("%w: odometer %.0f km goes backwards from record %d", internal.ErrInvalidMaintenanceRecord, r.Odometer, e.Id)
+		}
+	}
+
+	return s.rp.Add(r)
+}
+
+// Records is a method that returns the services of a vehicle, deleted or not, oldest first
+func (s *MaintenanceDefault) Records(vehicleId int) (r []internal.MaintenanceRecord, err error) {
+	if _, err = s.vr.FindById(vehicleId, internal.VehicleQuery{IncludeDeleted: true}); err != nil {
+		return
+	}
+	return s.rp.FindByVehicle(vehicleId)
+}
+
+// Due is a method that returns the services overdue or due within a time, soonest first
+// - only the vehicles with records are scheduled, the first record is the start of the types never done
+// - the distance left is projected into a date with the average daily distance between the records
+func (s *MaintenanceDefault) Due(within time.Duration) (d []internal.MaintenanceDue, err error) {
+	vehicles, err := s.vr.FindAll(internal.VehicleQuery{})
+	if err != nil {
+		return
+	}
+
+	now := s.now()
+	horizon := now.Add(within)
+	d = make([]internal.MaintenanceDue, 0)
+	for _, v := range vehicles {
+		records, err := s.rp.FindByVehicle(v.Id)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			continue
+		}
+
+		for _, rl := range s.vehicleRules(v) {
+			due, ok := scheduleDue(v, rl, records, now)
+			if ok && !due.DueAt.After(horizon) {
+				d = append(d, due)
+			}
+		}
+	}
+
+	sort.Slice(d, func(i, j int) bool {
+		switch {
+		case !d[i].DueAt.Equal(d[j].DueAt):
+			return d[i].DueAt.Before(d[j].DueAt)
+		case d[i].VehicleId != d[j].VehicleId:
+			return d[i].VehicleId < d[j].VehicleId
+		}
+		return d[i].Type < d[j].Type
+	})
+	return
+}
+
+// vehicleRules is a method that returns the most specific rule of each type that applies to a vehicle
+// - the types a rule without intervals exempts the vehicle from are left out
+func (s *MaintenanceDefault) vehicleRules(v internal.Vehicle) (rules []internal.MaintenanceRule) {
+	best := make(map[string]int)
+	var types []string
+	for i, rl := range s.rules {
+		if !rl.Matches(v) {
+			continue
+		}
+		j, ok := best[rl.Type]
+		if !ok {
+			types = append(types, rl.Type)
+		}
+		if !ok || rl.Specificity() > s.rules[j].Specificity() {
+			best[rl.Type] = i
+		}
+	}
+
+	for _, t := range types {
+		if rl := s.rules[best[t]]; rl.Every > 0 || rl.EveryKm > 0 {
+			rules = append(rules, rl)
+		}
+	}
+	return
+}
+
+// scheduleDue is a function that returns when a vehicle is due for the service of a rule
+// - not ok when it is only due by distance and the vehicle has no usage to project it with
+func scheduleDue(v internal.Vehicle, rl internal.MaintenanceRule, records []internal.MaintenanceRecord, now time.Time) (d internal.MaintenanceDue, ok bool) {
+	first, last := records[0], records[0]
+	d = internal.MaintenanceDue{VehicleId: v.Id, Brand: v.Brand, Type: rl.Type}
+	for _, r := range records {
+		if r.Type == rl.Type {
+			last = r
+		}
+		d.Odometer = math.Max(d.Odometer, r.Odometer)
+	}
+	d.LastDate, d.LastOdometer = last.Date, last.Odometer
+
+	if rl.Every > 0 {
+		dueDate := last.Date.Add(rl.Every)
+		d.DueDate, d.DueAt = &dueDate, dueDate
+	}
+	if rl.EveryKm > 0 {
+		dueOdometer := last.Odometer + rl.EveryKm
+		d.DueOdometer = &dueOdometer
+
+		// project the distance left with the daily distance of the vehicle, unknown without usage
+		var projected time.Time
+		days := records[len(records)-1].Date.Sub(first.Date).Hours() / 24
+		switch left := dueOdometer - d.Odometer; {
+		case left <= 0:
+			projected = now
+		case days >= 1 && d.Odometer > first.Odometer:
+			// beyond a century the projection is meaningless and would overflow the duration
+			if daysLeft := left / ((d.Odometer - first.Odometer) / days); daysLeft < 36500 {
+				projected = records[len(records)-1].Date.Add(time.Duration(daysLeft * float64(24*time.Hour)))
+			}
+		}
+		if !projected.IsZero() && (d.DueAt.IsZero() || projected.Before(d.DueAt)) {
+			d.DueAt = projected
+		}
+	}
+	if d.DueAt.IsZero() {
+		return d, false
+	}
+
+	d.Overdue = !d.DueAt.After(now)
+	return d, true
+}
+
+// Costs is a method that summarizes the costs of the services by group, most expensive first
+// - the services of the deleted vehicles are counted as well
+func (s *MaintenanceDefault) Costs(q internal.MaintenanceCostQuery) (c []internal.MaintenanceCost, err error) {
+	if q.GroupBy != "" && !q.GroupBy.IsCategorical() {
+		return nil, fmt.Errorf("%w: %s cannot be grouped by", internal.ErrInvalidVehicleField, q.GroupBy)
+	}
+
+	records, err := s.rp.FindAll()
+	if err != nil {
+		return
+	}
+	vehicles, err := s.vr.FindAll(internal.VehicleQuery{IncludeDeleted: true})
+	if err != nil {
+		return
+	}
+
+	groups := make(map[string]*internal.MaintenanceCost)
+	c = make([]internal.MaintenanceCost, 0)
+	for _, r := range records {
+		if (!q.From.IsZero() && r.Date.Before(q.From)) || (!q.To.IsZero() && r.Date.After(q.To)) {
+			continue
+		}
+
+		key := strconv.Itoa(r.VehicleId)
+		if q.GroupBy != "" {
+			key = q.GroupBy.Categorical(vehicles[r.VehicleId])
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &internal.MaintenanceCost{Group: key}
+			groups[key] = g
+		}
+		g.Records++
+		g.Total += r.Cost
+	}
+
+	for _, g := range groups {
+		g.Total = math.Round(g.Total*100) / 100
+		g.Average = math.Round(g.Total/float64(g.Records)*100) / 100
+		c = append(c, *g)
+	}
+	sort.Slice(c, func(i, j int) bool {
+		if c[i].Total != c[j].Total {
+			return c[i].Total > c[j].Total
+		}
+		return c[i].Group < c[j].Group
+	})
+	return
+}
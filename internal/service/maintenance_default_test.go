@@ -0,0 +1,188 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for MaintenanceDefault
+func TestMaintenanceDefault(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	daysAgo := func(n int) time.Time { return today.AddDate(0, 0, -n) }
+	rules := []internal.MaintenanceRule{
+		{Type: "inspection", Every: 365 * 24 * time.Hour},
+		{Type: "oil_change", Every: 365 * 24 * time.Hour, EveryKm: 15000},
+		{Type: "oil_change", FuelType: "electric"},
+		{Type: "brakes", EveryKm: 1000},
+	}
+
+	// newService is a function that returns a service with the fleet
+	newService := func() *service.MaintenanceDefault {
+		sv, err := service.NewMaintenanceDefault(repository.NewMaintenanceMap(), newFleet(), rules)
+		require.NoError(t, err)
+		return sv
+	}
+
+	t.Run("case 1: records are validated", func(t *testing.T) {
+		// arrange
+		sv := newService()
+
+		// act
+		id, err := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(10), Odometer: 5000, Type: "Oil Change", Cost: 80})
+		_, errBackwards := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(5), Odometer: 4000, Type: "brakes"})
+		_, errFuture := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: today.AddDate(0, 0, 2), Odometer: 6000, Type: "brakes"})
+		_, errType := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(5), Odometer: 6000})
+		_, errDeleted := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 6, Date: daysAgo(5), Odometer: 6000, Type: "brakes"})
+		records, _ := sv.Records(1)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []internal.MaintenanceRecord{{Id: id, VehicleId: 1, Date: daysAgo(10), Odometer: 5000, Type: "oil_change", Cost: 80}}, records)
+		require.ErrorIs(t, errBackwards, internal.ErrInvalidMaintenanceRecord)
+		require.ErrorIs(t, errFuture, internal.ErrInvalidMaintenanceRecord)
+		require.ErrorIs(t, errType, internal.ErrInvalidMaintenanceRecord)
+		require.ErrorIs(t, errDeleted, internal.ErrorVehicleNotFound)
+	})
+
+	t.Run("case 2: due by time within the horizon", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		_, err := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(360), Odometer: 0, Type: "inspection"})
+		require.NoError(t, err)
+		_, err = sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(100), Odometer: 100, Type: "oil_change"})
+		require.NoError(t, err)
+
+		// act
+		soon, err := sv.Due(30 * 24 * time.Hour)
+		require.NoError(t, err)
+		none, err := sv.Due(24 * time.Hour)
+		require.NoError(t, err)
+
+		// assert
+		require.Len(t, soon, 1)
+		require.Equal(t, "inspection", soon[0].Type)
+		require.Equal(t, daysAgo(-5), *soon[0].DueDate)
+		require.False(t, soon[0].Overdue)
+		require.Empty(t, none)
+	})
+
+	t.Run("case 3: due by distance projected with the usage", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		_, err := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(20), Odometer: 0, Type: "inspection"})
+		require.NoError(t, err)
+		_, err = sv.AddRecord(internal.MaintenanceRecord{VehicleId: 1, Date: daysAgo(10), Odometer: 900, Type: "oil_change"})
+		require.NoError(t, err)
+
+		// act
+		d, err := sv.Due(0)
+
+		// assert: 90 km a day leave the 100 km to the brakes after a day and a bit
+		require.NoError(t, err)
+		require.Len(t, d, 1)
+		require.Equal(t, "brakes", d[0].Type)
+		require.Nil(t, d[0].DueDate)
+		require.Equal(t, 1000.0, *d[0].DueOdometer)
+		require.WithinDuration(t, daysAgo(10).Add(time.Duration(100.0/90*float64(24*time.Hour))), d[0].DueAt, time.Second)
+		require.True(t, d[0].Overdue)
+	})
+
+	t.Run("case 4: a rule without intervals exempts the vehicle", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		_, err := sv.AddRecord(internal.MaintenanceRecord{VehicleId: 5, Date: daysAgo(400), Odometer: 0, Type: "inspection"})
+		require.NoError(t, err)
+
+		// act
+		d, err := sv.Due(0)
+
+		// assert: the oil change of the electric vehicle is never due
+		require.NoError(t, err)
+		require.Len(t, d, 1)
+		require.Equal(t, "inspection", d[0].Type)
+	})
+
+	t.Run("case 5: costs by brand, deleted vehicles included", func(t *testing.T) {
+		// arrange
+		rp := repository.NewMaintenanceMap()
+		for _, r := range []internal.MaintenanceRecord{
+			{VehicleId: 6, Date: daysAgo(30), Type: "inspection", Cost: 100.10},
+			{VehicleId: 6, Date: daysAgo(20), Type: "brakes", Cost: 200.20},
+			{VehicleId: 5, Date: daysAgo(10), Type: "inspection", Cost: 150},
+			{VehicleId: 5, Date: daysAgo(400), Type: "inspection", Cost: 1000},
+		} {
+			_, err := rp.Add(r)
+			require.NoError(t, err)
+		}
+		sv, err := service.NewMaintenanceDefault(rp, newFleet(), nil)
+		require.NoError(t, err)
+
+		// act
+		c, err := sv.Costs(internal.MaintenanceCostQuery{GroupBy: internal.FieldBrand, From: daysAgo(365)})
+		_, errField := sv.Costs(internal.MaintenanceCostQuery{GroupBy: internal.FieldMaxSpeed})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []internal.MaintenanceCost{
+			{Group: "Ford", Records: 2, Total: 300.3, Average: 150.15},
+			{Group: "Tesla", Records: 1, Total: 150, Average: 150},
+		}, c)
+		require.ErrorIs(t, errField, internal.ErrInvalidVehicleField)
+	})
+
+	t.Run("case 6: concurrent records cannot both go backwards", func(t *testing.T) {
+		// arrange: the reads of the records are slow enough for the posts to overlap
+		sv, err := service.NewMaintenanceDefault(&slowMaintenanceMap{repository.NewMaintenanceMap()}, newFleet(), rules)
+		require.NoError(t, err)
+		records := []internal.MaintenanceRecord{
+			{VehicleId: 1, Date: daysAgo(10), Odometer: 5000, Type: "brakes"},
+			{VehicleId: 1, Date: daysAgo(5), Odometer: 4000, Type: "brakes"},
+		}
+
+		// act
+		errs := make(chan error, len(records))
+		for _, r := range records {
+			go func(r internal.MaintenanceRecord) {
+				_, err := sv.AddRecord(r)
+				errs <- err
+			}(r)
+		}
+		var added int
+		for range records {
+			if <-errs == nil {
+				added++
+			}
+		}
+
+		// assert
+		require.Equal(t, 1, added)
+	})
+
+	t.Run("case 7: repeated rules are rejected", func(t *testing.T) {
+		// arrange
+		repeated := []internal.MaintenanceRule{{Type: "Oil Change", EveryKm: 10000}, {Type: "oil_change", Every: time.Hour}}
+
+		// act
+		_, err := service.NewMaintenanceDefault(repository.NewMaintenanceMap(), repository.NewVehicleMap(nil), repeated)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidMaintenanceRules)
+	})
+}
+
+// slowMaintenanceMap is a struct that delays the reads of the records of a MaintenanceMap
+type slowMaintenanceMap struct {
+	*repository.MaintenanceMap
+}
+
+// FindByVehicle is a method that returns the records of a vehicle a while after reading them
+func (r *slowMaintenanceMap) FindByVehicle(vehicleId int) (records []internal.MaintenanceRecord, err error) {
+	records, err = r.MaintenanceMap.FindByVehicle(vehicleId)
+	time.Sleep(10 * time.Millisecond)
+	return
+}
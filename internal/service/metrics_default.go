@@ -0,0 +1,61 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"math"
+)
+
+// NewMetricsDefault is a function that returns a new instance of MetricsDefault
+func NewMetricsDefault(rules internal.MetricsRules) (m *MetricsDefault, err error) {
+	if rules.SeatVolume < 0 || math.IsNaN(rules.SeatVolume) {
+		return nil, fmt.Errorf("%w: invalid seat volume", internal.ErrInvalidMetricsRules)
+	}
+	for _, class := range rules.SizeClasses {
+		if class.Name == "" {
+			return nil, fmt.Errorf("%w: size class without name", internal.ErrInvalidMetricsRules)
+		}
+		if len(class.Filter.Equals) > 0 {
+			return nil, fmt.Errorf("%w: size class %s can only set ranges", internal.ErrInvalidMetricsRules, class.Name)
+		}
+		for field := range class.Filter.Ranges {
+			if !field.IsNumeric() {
+				return nil, fmt.Errorf("%w: size class %s: %s is not numeric", internal.ErrInvalidMetricsRules, class.Name, field)
+			}
+		}
+	}
+
+	m = &MetricsDefault{rules: rules}
+	return
+}
+
+// MetricsDefault is a struct that implements the VehicleDeriver interface
+type MetricsDefault struct {
+	// rules are the parameters of the derived attributes
+	rules internal.MetricsRules
+}
+
+// Derive is a method that returns the derived attributes of a vehicle
+// - the dimensions are in cm, so the volume is divided by 10⁶ and the footprint by 10⁴
+// - the size class is the first one whose ranges hold the vehicle and its numeric derived attributes
+// - a vehicle missing a dimension has neither derived attributes nor size class, it is unknown rather than the smallest
+func (m *MetricsDefault) Derive(v internal.Vehicle) (metrics internal.VehicleMetrics) {
+	if v.Height <= 0 || v.Length <= 0 || v.Width <= 0 {
+		return
+	}
+
+	metrics.Volume = v.Height * v.Length * v.Width / 1e6
+	metrics.Footprint = v.Length * v.Width / 1e4
+	metrics.CargoVolume = math.Max(0, metrics.Volume-float64(v.Capacity)*m.rules.SeatVolume)
+	metrics.Density = v.Weight / metrics.Volume
+
+	v.Metrics = metrics
+	for _, class := range m.rules.SizeClasses {
+		if class.Filter.Match(v) {
+			metrics.SizeClass = class.Name
+			break
+		}
+	}
+
+	return
+}
@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for MetricsDefault with the shipped size classes
+func TestMetricsDefault_Derive(t *testing.T) {
+	rules, err := loader.NewMetricsRulesJSONFile("../../docs/metrics/size_classes.json").Load()
+	require.NoError(t, err)
+	dv, err := service.NewMetricsDefault(rules)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		vehicle   internal.VehicleAttributes
+		sizeClass string
+	}{
+		{name: "case 1: small city car", vehicle: internal.VehicleAttributes{Capacity: 4, Weight: 900, Dimensions: internal.Dimensions{Height: 150, Length: 360, Width: 160}}, sizeClass: "mini"},
+		{name: "case 2: compact", vehicle: internal.VehicleAttributes{Capacity: 5, Weight: 1300, Dimensions: internal.Dimensions{Height: 145, Length: 425, Width: 178}}, sizeClass: "compact"},
+		{name: "case 3: sedan", vehicle: internal.VehicleAttributes{Capacity: 5, Weight: 1500, Dimensions: internal.Dimensions{Height: 145, Length: 470, Width: 182}}, sizeClass: "mid-size"},
+		{name: "case 4: large sedan", vehicle: internal.VehicleAttributes{Capacity: 5, Weight: 1900, Dimensions: internal.Dimensions{Height: 150, Length: 520, Width: 190}}, sizeClass: "full-size"},
+		{name: "case 5: van", vehicle: internal.VehicleAttributes{Capacity: 3, Weight: 2200, Dimensions: internal.Dimensions{Height: 250, Length: 540, Width: 200}}, sizeClass: "van"},
+		{name: "case 6: truck", vehicle: internal.VehicleAttributes{Capacity: 2, Weight: 7500, Dimensions: internal.Dimensions{Height: 330, Length: 800, Width: 250}}, sizeClass: "truck"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			metrics := dv.Derive(internal.Vehicle{VehicleAttributes: c.vehicle})
+
+			// assert
+			require.Equal(t, c.sizeClass, metrics.SizeClass)
+		})
+	}
+
+	t.Run("case 7: volumes, footprint and density", func(t *testing.T) {
+		// act
+		metrics := dv.Derive(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{
+			Capacity: 2, Weight: 1000, Dimensions: internal.Dimensions{Height: 100, Length: 400, Width: 200},
+		}})
+
+		// assert
+		require.InDelta(t, 8, metrics.Volume, 1e-9)
+		require.InDelta(t, 8-2*rules.SeatVolume, metrics.CargoVolume, 1e-9)
+		require.InDelta(t, 8, metrics.Footprint, 1e-9)
+		require.InDelta(t, 125, metrics.Density, 1e-9)
+	})
+
+	t.Run("case 8: a vehicle without length is unclassified", func(t *testing.T) {
+		// act
+		metrics := dv.Derive(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{
+			Capacity: 5, Weight: 2900, Dimensions: internal.Dimensions{Height: 200, Width: 206},
+		}})
+
+		// assert: not the first class with a max bound
+		require.Equal(t, internal.VehicleMetrics{}, metrics)
+	})
+
+	t.Run("case 9: size classes only set ranges of numeric fields", func(t *testing.T) {
+		// act
+		_, err := service.NewMetricsDefault(internal.MetricsRules{SizeClasses: []internal.SizeClass{
+			{Name: "red", Filter: internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldColor: "red"}}},
+		}})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidMetricsRules)
+	})
+}
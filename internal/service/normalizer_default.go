@@ -0,0 +1,82 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NewNormalizerDefault is a function that returns a new instance of NormalizerDefault
+func NewNormalizerDefault(rules []internal.NormalizationRule) (n *NormalizerDefault, err error) {
+	n = &NormalizerDefault{
+		cases:     make(map[internal.VehicleField]string),
+		canonical: make(map[internal.VehicleField]map[string]string),
+	}
+
+	for _, rl := range rules {
+		if !rl.Field.IsCategorical() {
+			return nil, fmt.Errorf("%w: %s can not be normalized", internal.ErrInvalidVehicleField, rl.Field)
+		}
+		switch rl.Case {
+		case "", "lower", "upper", "title":
+		default:
+			return nil, fmt.Errorf("normalization rule %s: invalid case %s", rl.Field, rl.Case)
+		}
+
+		n.cases[rl.Field] = rl.Case
+		values := make(map[string]string)
+		for canonical, aliases := range rl.Synonyms {
+			values[normalizationKey(canonical)] = canonical
+			for _, alias := range aliases {
+				values[normalizationKey(alias)] = canonical
+			}
+		}
+		n.canonical[rl.Field] = values
+	}
+
+	return
+}
+
+// NormalizerDefault is a struct that implements the VehicleNormalizer interface with synonym tables
+type NormalizerDefault struct {
+	// cases is a map of the casing of the values without synonym of each field
+	cases map[internal.VehicleField]string
+	// canonical is a map of the canonical value of each alias of each field
+	canonical map[internal.VehicleField]map[string]string
+}
+
+// normalizationKey is a function that returns the form in which aliases are compared:
+// lower case, with dashes and underscores as spaces and single spaces between words
+func normalizationKey(value string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	}), " ")
+}
+
+// Normalize is a method that returns the canonical value of a field
+// - aliases and canonical values written in any casing are replaced by the canonical value
+// - other values are trimmed and cased following the rule of the field
+func (n *NormalizerDefault) Normalize(field internal.VehicleField, value string) string {
+	if canonical, ok := n.canonical[field][normalizationKey(value)]; ok {
+		return canonical
+	}
+
+	value = strings.Join(strings.Fields(value), " ")
+	switch n.cases[field] {
+	case "lower":
+		return strings.ToLower(value)
+	case "upper":
+		return strings.ToUpper(value)
+	case "title":
+		words := strings.Fields(strings.ToLower(value))
+		for i, word := range words {
+			r := []rune(word)
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		return strings.Join(words, " ")
+	default:
+		return value
+	}
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newNormalizer is a function that returns a normalizer with the shipped synonym tables
+func newNormalizer(t *testing.T) *service.NormalizerDefault {
+	rules, err := loader.NewNormalizationRuleJSONFile("../../docs/normalization/synonyms.json").Load()
+	require.NoError(t, err)
+	nz, err := service.NewNormalizerDefault(rules)
+	require.NoError(t, err)
+	return nz
+}
+
+// Tests for NormalizerDefault with the shipped synonym tables
+func TestNormalizerDefault_Normalize(t *testing.T) {
+	nz := newNormalizer(t)
+
+	cases := []struct {
+		name     string
+		field    internal.VehicleField
+		value    string
+		expected string
+	}{
+		{name: "case 1: fuel type synonym", field: internal.FieldFuelType, value: "Petrol", expected: "gasoline"},
+		{name: "case 2: brand alias in any casing", field: internal.FieldBrand, value: "vw", expected: "Volkswagen"},
+		{name: "case 3: canonical value written with dashes", field: internal.FieldBrand, value: "mercedes benz", expected: "Mercedes-Benz"},
+		{name: "case 4: color without synonym is title cased", field: internal.FieldColor, value: "  dark   blue ", expected: "Dark Blue"},
+		{name: "case 5: transmission is lower cased", field: internal.FieldTransmission, value: "Automatic", expected: "automatic"},
+		{name: "case 6: brand without synonym is kept", field: internal.FieldBrand, value: "Ford", expected: "Ford"},
+		{name: "case 7: field without rule is kept", field: internal.FieldModel, value: "focus", expected: "focus"},
+		{name: "case 8: gasoil is diesel", field: internal.FieldFuelType, value: "Gasoil", expected: "diesel"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			value := nz.Normalize(c.field, c.value)
+
+			// assert
+			require.Equal(t, c.expected, value)
+		})
+	}
+}
+
+// Tests for the normalization of the vehicles by VehicleDefault
+func TestVehicleDefault_Normalize(t *testing.T) {
+	// newService is a function that returns a service over the given vehicles
+	newService := func(t *testing.T, db map[int]internal.Vehicle) (*service.VehicleDefault, *repository.VehicleMap) {
+		rp := repository.NewVehicleMap(db)
+		sq, err := repository.NewVehicleSequenceFile("")
+		require.NoError(t, err)
+		require.NoError(t, sq.Advance(len(db)))
+		return service.NewVehicleDefault(rp, &service.ConfigVehicleDefault{Sequence: sq, Normalizer: newNormalizer(t)}), rp
+	}
+
+	t.Run("case 1: writes are canonicalized and filters match any form", func(t *testing.T) {
+		// arrange
+		sv, _ := newService(t, nil)
+		_, err := sv.Add(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Registration: "AB1", Brand: "chevy", Color: "blue", FuelType: "petrol", Transmission: "Auto"}})
+		require.NoError(t, err)
+
+		// act
+		byFuel, err1 := sv.GetVehiclesByFuelType("Gasoline", internal.VehicleQuery{})
+		byTransmission, err2 := sv.GetVehiclesByTransmission("AUTOMATIC", internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.Len(t, byFuel, 1)
+		require.Len(t, byTransmission, 1)
+		require.Equal(t, "Chevrolet", byFuel[0].Brand)
+		require.Equal(t, "Blue", byFuel[0].Color)
+		require.Equal(t, "gasoline", byFuel[0].FuelType)
+		require.Equal(t, "automatic", byFuel[0].Transmission)
+	})
+
+	t.Run("case 2: fuel type synonyms are stored as their canonical value only", func(t *testing.T) {
+		// arrange
+		sv, rp := newService(t, map[int]internal.Vehicle{1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{FuelType: "gasoline"}}})
+
+		// act
+		err := sv.UpdateFuelTypeById(1, "gasoil")
+		errRaw := service.ValidateFuelType("gasoil")
+
+		// assert
+		require.NoError(t, err)
+		v, _ := rp.FindById(1, internal.VehicleQuery{})
+		require.Equal(t, "diesel", v.FuelType)
+		require.ErrorIs(t, errRaw, internal.ErrInvalidFuelType)
+	})
+
+	t.Run("case 3: a dry run reports the changes without applying them", func(t *testing.T) {
+		// arrange
+		sv, rp := newService(t, map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "VW", Color: "Red", FuelType: "diesel", Transmission: "manual"}},
+			2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "Red", FuelType: "diesel", Transmission: "manual"}},
+		})
+
+		// act
+		changes, err := sv.NormalizeAll(true)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []internal.NormalizationChange{{Id: 1, Field: internal.FieldBrand, From: "VW", To: "Volkswagen"}}, changes)
+		v, err := rp.FindById(1, internal.VehicleQuery{})
+		require.NoError(t, err)
+		require.Equal(t, "VW", v.Brand)
+	})
+
+	t.Run("case 4: the migration normalizes deleted vehicles too", func(t *testing.T) {
+		// arrange
+		sv, rp := newService(t, map[int]internal.Vehicle{
+			1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Color: "grey", FuelType: "Gas Oil", Transmission: "manual"}},
+		})
+		require.NoError(t, sv.DeleteById(1))
+
+		// act
+		changes, err := sv.NormalizeAll(false)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		v, err := rp.FindById(1, internal.VehicleQuery{IncludeDeleted: true})
+		require.NoError(t, err)
+		require.Equal(t, "Gray", v.Color)
+		require.Equal(t, "diesel", v.FuelType)
+		require.NotNil(t, v.DeletedAt)
+	})
+}
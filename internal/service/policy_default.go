@@ -0,0 +1,124 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"strings"
+)
+
+// NewPolicyDefault is a function that returns a new instance of PolicyDefault
+// - the inherited permissions are resolved once, the inheritance can not be cyclic
+func NewPolicyDefault(policy internal.Policy) (a *PolicyDefault, err error) {
+	roles := make(map[string]internal.Role, len(policy.Roles))
+	for _, r := range policy.Roles {
+		if r.Name == "" {
+			return nil, fmt.Errorf("%w: roles need a name", internal.ErrInvalidPolicy)
+		}
+		if _, ok := roles[r.Name]; ok {
+			return nil, fmt.Errorf("%w: role %s is repeated", internal.ErrInvalidPolicy, r.Name)
+		}
+		roles[r.Name] = r
+	}
+
+	a = &PolicyDefault{
+		permissions: make(map[string]map[internal.Permission]bool, len(roles)),
+		grants:      policy.Grants,
+		anonymous:   policy.AnonymousRoles,
+	}
+	for name := range roles {
+		if err = a.resolve(name, roles, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	// grants refer to principals of a method and to known roles
+	for id, names := range policy.Grants {
+		method, principal, ok := strings.Cut(id, ":")
+		if !ok || principal == "" || (method != internal.AuthMethodAPIKey && method != internal.AuthMethodJWT) {
+			return nil, fmt.Errorf("%w: grant of %s: the key is %s:<id> or %s:<subject>", internal.ErrInvalidPolicy, id, internal.AuthMethodAPIKey, internal.AuthMethodJWT)
+		}
+		for _, name := range names {
+			if _, ok := roles[name]; !ok {
+				return nil, fmt.Errorf("%w: grant of %s: unknown role %s", internal.ErrInvalidPolicy, id, name)
+			}
+		}
+	}
+	for _, name := range policy.AnonymousRoles {
+		if _, ok := roles[name]; !ok {
+			return nil, fmt.Errorf("%w: anonymous: unknown role %s", internal.ErrInvalidPolicy, name)
+		}
+	}
+
+	return
+}
+
+// PolicyDefault is a struct that implements the Authorizer interface with roles
+type PolicyDefault struct {
+	// permissions is a map of the permissions of each role, inherited included
+	permissions map[string]map[internal.Permission]bool
+	// grants is a map of the roles granted to principals by method and id
+	grants map[string][]string
+	// anonymous are the roles of the requests without credentials
+	anonymous []string
+}
+
+// resolve is a method that computes the permissions of a role and the roles it inherits
+// - visiting are the roles being resolved, to detect the cycles
+func (a *PolicyDefault) resolve(name string, roles map[string]internal.Role, visiting map[string]bool) (err error) {
+	if _, ok := a.permissions[name]; ok {
+		return
+	}
+	role, ok := roles[name]
+	if !ok {
+		return fmt.Errorf("%w: unknown role %s", internal.ErrInvalidPolicy, name)
+	}
+	if visiting[name] {
+		return fmt.Errorf("%w: role %s inherits itself", internal.ErrInvalidPolicy, name)
+	}
+	visiting[name] = true
+
+	permissions := make(map[internal.Permission]bool)
+	for _, p := range role.Permissions {
+		permissions[p] = true
+	}
+	for _, parent := range role.Inherits {
+		if err = a.resolve(parent, roles, visiting); err != nil {
+			return
+		}
+		for p := range a.permissions[parent] {
+			permissions[p] = true
+		}
+	}
+
+	a.permissions[name] = permissions
+	return
+}
+
+// Authorize is a method that returns a PermissionError when a principal, nil when anonymous, lacks a permission
+// - the roles unknown to the policy grant nothing
+func (a *PolicyDefault) Authorize(p *internal.Principal, permission internal.Permission) (err error) {
+	roles := a.anonymous
+	var id string
+	if p != nil {
+		id = p.Id
+		roles = append(append([]string(nil), p.Roles...), a.grants[p.GrantKey()]...)
+	}
+
+	for _, role := range roles {
+		for granted := range a.permissions[role] {
+			if matchPermission(granted, permission) {
+				return nil
+			}
+		}
+	}
+	return &internal.PermissionError{Principal: id, Permission: permission}
+}
+
+// matchPermission is a function that reports if a granted permission, possibly a wildcard, covers a permission
+func matchPermission(granted, permission internal.Permission) bool {
+	if granted == "*" || granted == permission {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(string(granted), "*")
+	return ok && strings.HasSuffix(prefix, ":") && strings.HasPrefix(string(permission), prefix)
+}
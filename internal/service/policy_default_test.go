@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for PolicyDefault
+func TestPolicyDefault_Authorize(t *testing.T) {
+	az, err := service.NewPolicyDefault(internal.Policy{
+		Roles: []internal.Role{
+			{Name: "viewer", Permissions: []internal.Permission{internal.PermissionVehiclesRead}},
+			{Name: "fleet_manager", Inherits: []string{"viewer"}, Permissions: []internal.Permission{internal.PermissionVehiclesUpdateSpeed}},
+			{Name: "janitor", Permissions: []internal.Permission{"vehicles:*"}},
+			{Name: "root", Permissions: []internal.Permission{"*"}},
+		},
+		Grants:         map[string][]string{"jwt:jane": {"fleet_manager"}},
+		AnonymousRoles: []string{"viewer"},
+	})
+	require.NoError(t, err)
+
+	t.Run("case 1: permission of an inherited role", func(t *testing.T) {
+		// act
+		err := az.Authorize(&internal.Principal{Id: "john", Roles: []string{"fleet_manager"}}, internal.PermissionVehiclesRead)
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("case 2: missing permission, named by the error", func(t *testing.T) {
+		// act
+		err := az.Authorize(&internal.Principal{Id: "john", Roles: []string{"viewer"}}, internal.PermissionVehiclesDelete)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrForbidden)
+		require.Equal(t, &internal.PermissionError{Principal: "john", Permission: internal.PermissionVehiclesDelete}, err)
+	})
+
+	t.Run("case 3: roles granted by the policy", func(t *testing.T) {
+		// act
+		err := az.Authorize(&internal.Principal{Id: "jane", Method: internal.AuthMethodJWT}, internal.PermissionVehiclesUpdateSpeed)
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("case 4: anonymous requests", func(t *testing.T) {
+		// act
+		errRead := az.Authorize(nil, internal.PermissionVehiclesRead)
+		errWrite := az.Authorize(nil, internal.PermissionVehiclesCreate)
+
+		// assert
+		require.NoError(t, errRead)
+		require.ErrorIs(t, errWrite, internal.ErrForbidden)
+	})
+
+	t.Run("case 5: wildcards", func(t *testing.T) {
+		// act
+		errNamespace := az.Authorize(&internal.Principal{Roles: []string{"janitor"}}, internal.PermissionVehiclesPurge)
+		errOther := az.Authorize(&internal.Principal{Roles: []string{"janitor"}}, "drivers:read")
+		errRoot := az.Authorize(&internal.Principal{Roles: []string{"root"}}, "drivers:read")
+
+		// assert
+		require.NoError(t, errNamespace)
+		require.ErrorIs(t, errOther, internal.ErrForbidden)
+		require.NoError(t, errRoot)
+	})
+
+	t.Run("case 6: roles unknown to the policy grant nothing", func(t *testing.T) {
+		// act
+		err := az.Authorize(&internal.Principal{Roles: []string{"superuser"}}, internal.PermissionVehiclesRead)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrForbidden)
+	})
+
+	t.Run("case 7: the grants of a subject are not the ones of an API key with its id", func(t *testing.T) {
+		// act
+		err := az.Authorize(&internal.Principal{Id: "jane", Method: internal.AuthMethodAPIKey}, internal.PermissionVehiclesUpdateSpeed)
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrForbidden)
+	})
+}
+
+func TestNewPolicyDefault(t *testing.T) {
+	t.Run("case 1: cyclic inheritance", func(t *testing.T) {
+		// act
+		_, err := service.NewPolicyDefault(internal.Policy{Roles: []internal.Role{
+			{Name: "a", Inherits: []string{"b"}},
+			{Name: "b", Inherits: []string{"a"}},
+		}})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidPolicy)
+	})
+
+	t.Run("case 2: grant of an unknown role", func(t *testing.T) {
+		// act
+		_, err := service.NewPolicyDefault(internal.Policy{
+			Roles:  []internal.Role{{Name: "viewer"}},
+			Grants: map[string][]string{"jwt:jane": {"admin"}},
+		})
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidPolicy)
+	})
+
+	t.Run("case 3: grant without method", func(t *testing.T) {
+		// act
+		_, errBare := service.NewPolicyDefault(internal.Policy{
+			Roles:  []internal.Role{{Name: "viewer"}},
+			Grants: map[string][]string{"jane": {"viewer"}},
+		})
+		_, errUnknown := service.NewPolicyDefault(internal.Policy{
+			Roles:  []internal.Role{{Name: "viewer"}},
+			Grants: map[string][]string{"oauth:jane": {"viewer"}},
+		})
+
+		// assert
+		require.ErrorIs(t, errBare, internal.ErrInvalidPolicy)
+		require.ErrorIs(t, errUnknown, internal.ErrInvalidPolicy)
+	})
+
+	t.Run("case 4: every operation of the services has a permission", func(t *testing.T) {
+		// arrange
+		services := map[reflect.Type]map[string]internal.Permission{
+			reflect.TypeOf((*internal.VehicleService)(nil)).Elem():     internal.VehicleOperations,
+			reflect.TypeOf((*internal.DepotService)(nil)).Elem():       internal.DepotOperations,
+			reflect.TypeOf((*internal.DriverService)(nil)).Elem():      internal.DriverOperations,
+			reflect.TypeOf((*internal.MaintenanceService)(nil)).Elem(): internal.MaintenanceOperations,
+			reflect.TypeOf((*internal.FuelService)(nil)).Elem():        internal.FuelOperations,
+			reflect.TypeOf((*internal.ReservationService)(nil)).Elem(): internal.ReservationOperations,
+		}
+
+		// assert
+		for tp, operations := range services {
+			require.Len(t, operations, tp.NumMethod(), tp.Name())
+			for i := 0; i < tp.NumMethod(); i++ {
+				require.NotEmpty(t, operations[tp.Method(i).Name], tp.Name()+"."+tp.Method(i).Name)
+			}
+		}
+	})
+}
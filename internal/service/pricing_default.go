@@ -0,0 +1,183 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// maxRentalDays is the number of days a quote can be for at most
+const maxRentalDays = 365
+
+// NewPricingDefault is a function that returns a new instance of PricingDefault
+func NewPricingDefault(rules internal.PricingRules) (p *PricingDefault, err error) {
+	type rateKey struct{ brand, sizeClass string }
+	rates := make(map[rateKey]bool)
+	for i, r := range rules.BaseRates {
+		key := rateKey{strings.ToLower(r.Brand), strings.ToLower(r.SizeClass)}
+		switch {
+		case r.Daily <= 0:
+			return nil, fmt.Errorf("%w: base rate %d: the daily price must be positive", internal.ErrInvalidPricingRules, i)
+		case rates[key]:
+			return nil, fmt.Errorf("%w: base rate %d: repeated for %s %s", internal.ErrInvalidPricingRules, i, r.Brand, r.SizeClass)
+		}
+		rates[key] = true
+	}
+
+	for i, s := range rules.Surcharges {
+		if s.Name == "" || s.Daily <= 0 {
+			return nil, fmt.Errorf("%w: surcharge %d: name is required and the daily price must be positive", internal.ErrInvalidPricingRules, i)
+		}
+		for field := range s.When.Equals {
+			if !field.IsCategorical() {
+				return nil, fmt.Errorf("%w: surcharge %s: %s is not categorical", internal.ErrInvalidPricingRules, s.Name, field)
+			}
+		}
+		for field := range s.When.Ranges {
+			if !field.IsNumeric() {
+				return nil, fmt.Errorf("%w: surcharge %s: %s is not numeric", internal.ErrInvalidPricingRules, s.Name, field)
+			}
+		}
+	}
+
+	for i, s := range rules.Seasons {
+		_, errFrom := time.Parse("01-02", s.From)
+		_, errTo := time.Parse("01-02", s.To)
+		switch {
+		case s.Name == "":
+			return nil, fmt.Errorf("%w: season %d has no name", internal.ErrInvalidPricingRules, i)
+		case errFrom != nil || errTo != nil:
+			return nil, fmt.Errorf("%w: season %s: from and to must be days of the year (01-02)", internal.ErrInvalidPricingRules, s.Name)
+		case s.Percent <= 0 || s.Percent > 100:
+			return nil, fmt.Errorf("%w: season %s: the percent must be between 0 and 100", internal.ErrInvalidPricingRules, s.Name)
+		}
+	}
+
+	durations := make(map[int]bool)
+	for i, d := range rules.Durations {
+		switch {
+		case d.MinDays < 1:
+			return nil, fmt.Errorf("%w: duration %d: the minimum days must be positive", internal.ErrInvalidPricingRules, i)
+		case d.Percent <= 0 || d.Percent > 100:
+			return nil, fmt.Errorf("%w: duration %d: the percent must be between 0 and 100", internal.ErrInvalidPricingRules, i)
+		case durations[d.MinDays]:
+			return nil, fmt.Errorf("%w: duration %d: repeated for %d days", internal.ErrInvalidPricingRules, i, d.MinDays)
+		}
+		durations[d.MinDays] = true
+	}
+
+	p = &PricingDefault{rules: rules}
+	return
+}
+
+// PricingDefault is a struct that implements the VehiclePricer interface with the rules of a file
+// - the price of a day is the base rate plus the surcharges, the seasons discount each day and the duration the rest
+type PricingDefault struct {
+	// rules are the rates, surcharges and discounts
+	rules internal.PricingRules
+}
+
+// cents is a function that rounds an amount to hundredths
+func cents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Quote is a method that returns the price of the rental of a vehicle for a period [from, to)
+// - every started day is charged, a day within several seasons gets the greatest of their discounts
+func (p *PricingDefault) Quote(v internal.Vehicle, from time.Time, to time.Time) (q internal.VehicleQuote, err error) {
+	switch {
+	case from.IsZero() || to.IsZero():
+		return q, fmt.Errorf("%w: from and to are required", internal.ErrInvalidQuoteQuery)
+	case !to.After(from):
+		return q, fmt.Errorf("%w: to must be after from", internal.ErrInvalidQuoteQuery)
+	}
+	days := int(math.Ceil(to.Sub(from).Hours() / 24))
+	if days > maxRentalDays {
+		return q, fmt.Errorf("%w: a rental lasts %d days at most", internal.ErrInvalidQuoteQuery, maxRentalDays)
+	}
+
+	rate, ok := p.baseRate(v)
+	if !ok {
+		return q, fmt.Errorf("%w: %s %s", internal.ErrNoBaseRate, v.Brand, v.Metrics.SizeClass)
+	}
+
+	q = internal.VehicleQuote{Vehicle: v, From: from, To: to, Days: days, Currency: p.rules.Currency}
+
+	// prices: the base rate and the surcharges of every day
+	description := "base rate"
+	for _, value := range []string{rate.Brand, rate.SizeClass} {
+		if value != "" {
+			description += " " + value
+		}
+	}
+	q.Lines = append(q.Lines, internal.QuoteLine{Kind: internal.QuoteLineBase, Description: description, Days: days, Daily: rate.Daily, Amount: cents(rate.Daily * float64(days))})
+	for _, s := range p.rules.Surcharges {
+		if s.When.Match(v) {
+			q.Lines = append(q.Lines, internal.QuoteLine{Kind: internal.QuoteLineSurcharge, Description: s.Name, Days: days, Daily: s.Daily, Amount: cents(s.Daily * float64(days))})
+		}
+	}
+	for _, l := range q.Lines {
+		q.Daily += l.Daily
+		q.Subtotal += l.Amount
+	}
+	q.Daily, q.Subtotal = cents(q.Daily), cents(q.Subtotal)
+
+	// seasonal discounts: the days of each season
+	seasonDays := make([]int, len(p.rules.Seasons))
+	for i := 0; i < days; i++ {
+		day := from.UTC().Add(time.Duration(i) * 24 * time.Hour)
+		best := -1
+		for j, s := range p.rules.Seasons {
+			if s.Contains(day) && (best < 0 || s.Percent > p.rules.Seasons[best].Percent) {
+				best = j
+			}
+		}
+		if best >= 0 {
+			seasonDays[best]++
+		}
+	}
+	discounted := q.Subtotal
+	for j, n := range seasonDays {
+		if n == 0 {
+			continue
+		}
+		s := p.rules.Seasons[j]
+		line := internal.QuoteLine{Kind: internal.QuoteLineSeason, Description: s.Name, Days: n, Daily: q.Daily, Percent: s.Percent, Amount: -cents(q.Daily * float64(n) * s.Percent / 100)}
+		q.Lines = append(q.Lines, line)
+		discounted += line.Amount
+	}
+
+	// duration discount: the longest duration reached, over the price once the seasons are discounted
+	best := -1
+	for j, d := range p.rules.Durations {
+		if d.MinDays <= days && (best < 0 || d.MinDays > p.rules.Durations[best].MinDays) {
+			best = j
+		}
+	}
+	if best >= 0 {
+		d := p.rules.Durations[best]
+		description := fmt.Sprintf("%d days or more", d.MinDays)
+		q.Lines = append(q.Lines, internal.QuoteLine{Kind: internal.QuoteLineDuration, Description: description, Days: days, Percent: d.Percent, Amount: -cents(discounted * d.Percent / 100)})
+	}
+
+	for _, l := range q.Lines {
+		if l.Amount < 0 {
+			q.Discount -= l.Amount
+		}
+	}
+	q.Discount = cents(q.Discount)
+	q.Total = cents(q.Subtotal - q.Discount)
+	return
+}
+
+// baseRate is a method that returns the most specific base rate that applies to a vehicle
+func (p *PricingDefault) baseRate(v internal.Vehicle) (r internal.BaseRate, ok bool) {
+	for _, value := range p.rules.BaseRates {
+		if value.Matches(v) && (!ok || value.Specificity() > r.Specificity()) {
+			r, ok = value, true
+		}
+	}
+	return
+}
@@ -0,0 +1,176 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for PricingDefault
+func TestPricingDefault_Quote(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2030, month, d, 0, 0, 0, 0, time.UTC) }
+	seven := 7.0
+
+	// rules is a function that returns rules with rates by size class and brand, surcharges for automatic
+	// transmissions and 7 passengers or more, a winter season wrapping the new year and a discount from 7 days
+	rules := func() internal.PricingRules {
+		return internal.PricingRules{
+			Currency: "EUR",
+			BaseRates: []internal.BaseRate{
+				{Daily: 40},
+				{SizeClass: "compact", Daily: 30},
+				{Brand: "Fiat", Daily: 25},
+				{Brand: "Fiat", SizeClass: "compact", Daily: 20},
+			},
+			Surcharges: []internal.Surcharge{
+				{Name: "automatic", When: internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldTransmission: "automatic"}}, Daily: 5},
+				{Name: "7 passengers or more", When: internal.VehicleFilter{Ranges: map[internal.VehicleField]internal.Range{internal.FieldCapacity: {Min: &seven}}}, Daily: 10},
+			},
+			Seasons: []internal.SeasonalDiscount{
+				{Name: "winter", From: "12-30", To: "01-02", Percent: 10},
+				{Name: "new year", From: "01-01", To: "01-01", Percent: 50},
+			},
+			Durations: []internal.DurationDiscount{
+				{MinDays: 7, Percent: 10},
+				{MinDays: 3, Percent: 5},
+			},
+		}
+	}
+	vehicle := func(brand string, sizeClass string, transmission string, capacity int) internal.Vehicle {
+		v := internal.Vehicle{Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: brand, Transmission: transmission, Capacity: capacity}}
+		v.Metrics.SizeClass = sizeClass
+		return v
+	}
+
+	t.Run("case 1: the most specific base rate plus the matching surcharges", func(t *testing.T) {
+		// arrange
+		pr, err := service.NewPricingDefault(rules())
+		require.NoError(t, err)
+		from, to := day(6, 1), day(6, 3)
+
+		// act
+		fiatCompact, err := pr.Quote(vehicle("fiat", "compact", "manual", 4), from, to)
+		require.NoError(t, err)
+		fiatVan, err := pr.Quote(vehicle("Fiat", "van", "automatic", 4), from, to)
+		require.NoError(t, err)
+		fordCompact, err := pr.Quote(vehicle("Ford", "compact", "automatic", 7), from, to)
+		require.NoError(t, err)
+		fordVan, err := pr.Quote(vehicle("Ford", "van", "manual", 2), from, to)
+		require.NoError(t, err)
+
+		// assert
+		require.Equal(t, 2, fiatCompact.Days)
+		require.Equal(t, 20.0, fiatCompact.Daily)
+		require.Equal(t, 40.0, fiatCompact.Total)
+		require.Equal(t, "base rate Fiat compact", fiatCompact.Lines[0].Description)
+		require.Equal(t, 30.0, fiatVan.Daily)
+		require.Equal(t, 45.0, fordCompact.Daily)
+		require.Equal(t, []internal.QuoteLine{
+			{Kind: internal.QuoteLineBase, Description: "base rate compact", Days: 2, Daily: 30, Amount: 60},
+			{Kind: internal.QuoteLineSurcharge, Description: "automatic", Days: 2, Daily: 5, Amount: 10},
+			{Kind: internal.QuoteLineSurcharge, Description: "7 passengers or more", Days: 2, Daily: 10, Amount: 20},
+		}, fordCompact.Lines)
+		require.Equal(t, 90.0, fordCompact.Total)
+		require.Equal(t, 40.0, fordVan.Daily)
+		require.Equal(t, "EUR", fordVan.Currency)
+	})
+
+	t.Run("case 2: seasonal discounts by day, the greatest one when seasons overlap", func(t *testing.T) {
+		// arrange
+		pr, err := service.NewPricingDefault(internal.PricingRules{
+			BaseRates: []internal.BaseRate{{Daily: 100}},
+			Seasons:   rules().Seasons,
+		})
+		require.NoError(t, err)
+
+		// act: from the 29th of december to the 3rd of january, 5 days
+		q, err := pr.Quote(vehicle("Ford", "van", "manual", 2), day(12, 29).AddDate(-1, 0, 0), day(1, 3))
+		require.NoError(t, err)
+
+		// assert: the 30th, the 31st and the 2nd are in winter, the 1st is new year
+		require.Equal(t, 5, q.Days)
+		require.Equal(t, 500.0, q.Subtotal)
+		require.Equal(t, []internal.QuoteLine{
+			{Kind: internal.QuoteLineBase, Description: "base rate", Days: 5, Daily: 100, Amount: 500},
+			{Kind: internal.QuoteLineSeason, Description: "winter", Days: 3, Daily: 100, Percent: 10, Amount: -30},
+			{Kind: internal.QuoteLineSeason, Description: "new year", Days: 1, Daily: 100, Percent: 50, Amount: -50},
+		}, q.Lines)
+		require.Equal(t, 80.0, q.Discount)
+		require.Equal(t, 420.0, q.Total)
+	})
+
+	t.Run("case 3: the longest duration reached discounts the price once the seasons are discounted", func(t *testing.T) {
+		// arrange
+		pr, err := service.NewPricingDefault(rules())
+		require.NoError(t, err)
+		v := vehicle("Ford", "van", "manual", 2)
+
+		// act: every started day is charged
+		short, err := pr.Quote(v, day(6, 1), day(6, 2).Add(time.Hour))
+		require.NoError(t, err)
+		week, err := pr.Quote(v, day(12, 26), day(1, 2).AddDate(1, 0, 0))
+		require.NoError(t, err)
+
+		// assert
+		require.Equal(t, 2, short.Days)
+		require.Len(t, short.Lines, 1)
+		require.Equal(t, 80.0, short.Total)
+
+		// 7 days of 40 = 280, winter the 30th and the 31st = -8, new year the 1st = -20, 7 days or more = -25.2
+		require.Equal(t, 7, week.Days)
+		last := week.Lines[len(week.Lines)-1]
+		require.Equal(t, internal.QuoteLine{Kind: internal.QuoteLineDuration, Description: "7 days or more", Days: 7, Percent: 10, Amount: -25.2}, last)
+		require.Equal(t, 280.0, week.Subtotal)
+		require.Equal(t, 53.2, week.Discount)
+		require.Equal(t, 226.8, week.Total)
+	})
+
+	t.Run("case 4: invalid periods and vehicles without a base rate", func(t *testing.T) {
+		// arrange
+		pr, err := service.NewPricingDefault(internal.PricingRules{
+			BaseRates: []internal.BaseRate{{SizeClass: "compact", Daily: 30}},
+		})
+		require.NoError(t, err)
+		compact := vehicle("Ford", "compact", "manual", 4)
+
+		// act
+		_, errEmpty := pr.Quote(compact, day(6, 1), time.Time{})
+		_, errBackwards := pr.Quote(compact, day(6, 2), day(6, 1))
+		_, errLong := pr.Quote(compact, day(1, 1), day(1, 1).AddDate(2, 0, 0))
+		_, errRate := pr.Quote(vehicle("Ford", "van", "manual", 4), day(6, 1), day(6, 2))
+
+		// assert
+		require.ErrorIs(t, errEmpty, internal.ErrInvalidQuoteQuery)
+		require.ErrorIs(t, errBackwards, internal.ErrInvalidQuoteQuery)
+		require.ErrorIs(t, errLong, internal.ErrInvalidQuoteQuery)
+		require.ErrorIs(t, errRate, internal.ErrNoBaseRate)
+	})
+}
+
+// Tests for NewPricingDefault
+func TestNewPricingDefault(t *testing.T) {
+	t.Run("case 1: invalid rules are rejected", func(t *testing.T) {
+		// arrange
+		cases := []internal.PricingRules{
+			{BaseRates: []internal.BaseRate{{Daily: 0}}},
+			{BaseRates: []internal.BaseRate{{Brand: "Fiat", Daily: 20}, {Brand: "fiat", Daily: 25}}},
+			{Surcharges: []internal.Surcharge{{Name: "", Daily: 5}}},
+			{Surcharges: []internal.Surcharge{{Name: "heavy", When: internal.VehicleFilter{Equals: map[internal.VehicleField]string{internal.FieldWeight: "2000"}}, Daily: 5}}},
+			{Seasons: []internal.SeasonalDiscount{{Name: "winter", From: "13-01", To: "02-28", Percent: 10}}},
+			{Seasons: []internal.SeasonalDiscount{{Name: "winter", From: "12-01", To: "02-28", Percent: 120}}},
+			{Durations: []internal.DurationDiscount{{MinDays: 0, Percent: 10}}},
+			{Durations: []internal.DurationDiscount{{MinDays: 7, Percent: 10}, {MinDays: 7, Percent: 15}}},
+		}
+
+		for i, rules := range cases {
+			// act
+			_, err := service.NewPricingDefault(rules)
+
+			// assert
+			require.ErrorIs(t, err, internal.ErrInvalidPricingRules, "rules %d", i)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NewRegistrationDefault is a function that returns a new instance of RegistrationDefault
+func NewRegistrationDefault(rules []internal.RegistrationRule) (v *RegistrationDefault, err error) {
+	v = &RegistrationDefault{formats: make(map[string][]registrationFormat)}
+
+	// compile the patterns once
+	for _, rl := range rules {
+		pattern, err := regexp.Compile(rl.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("registration rule %s %s: %w", rl.Country, rl.Name, err)
+		}
+
+		country := strings.ToUpper(rl.Country)
+		v.formats[country] = append(v.formats[country], registrationFormat{name: rl.Name, pattern: pattern})
+	}
+
+	return
+}
+
+// registrationFormat is a struct that represents a compiled registration rule
+type registrationFormat struct {
+	// name is the name of the format
+	name string
+	// pattern is the compiled regular expression of the format
+	pattern *regexp.Regexp
+}
+
+// RegistrationDefault is a struct that implements the RegistrationValidator interface with regular expressions by country
+type RegistrationDefault struct {
+	// formats is a map of the accepted formats of each country
+	formats map[string][]registrationFormat
+}
+
+// Validate is a method that checks a normalized registration against the formats of a country
+func (v *RegistrationDefault) Validate(country string, registration string) (format string, err error) {
+	formats, ok := v.formats[strings.ToUpper(country)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", internal.ErrUnknownRegistrationCountry, country)
+	}
+
+	for _, f := range formats {
+		if f.pattern.MatchString(registration) {
+			return f.name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s for %s", internal.ErrInvalidRegistrationFormat, registration, country)
+}
@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for RegistrationDefault with the shipped registration formats
+func TestRegistrationDefault_Validate(t *testing.T) {
+	rules, err := loader.NewRegistrationRuleJSONFile("../../docs/registration/formats.json").Load()
+	require.NoError(t, err)
+	rg, err := service.NewRegistrationDefault(rules)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		country      string
+		registration string
+		format       string
+		err          error
+	}{
+		{name: "case 1: spanish current format", country: "es", registration: "1234-bcd", format: "current"},
+		{name: "case 2: spanish plates have no vowels", country: "ES", registration: "1234 ABC", err: internal.ErrInvalidRegistrationFormat},
+		{name: "case 3: french siv format", country: "FR", registration: "AB-123-CD", format: "siv"},
+		{name: "case 4: uk current format", country: "UK", registration: "AB12 CDE", format: "current"},
+		{name: "case 5: californian format", country: "US-CA", registration: "7ABC123", format: "standard"},
+		{name: "case 6: plates of the data set are not valid", country: "DE", registration: "05715", err: internal.ErrInvalidRegistrationFormat},
+		{name: "case 7: unknown country", country: "XX", registration: "1234", err: internal.ErrUnknownRegistrationCountry},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			format, err := rg.Validate(c.country, internal.NormalizeRegistration(c.registration))
+
+			// assert
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.format, format)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"app/internal"
+)
+
+// NewReservationAuthorized is a function that returns a new instance of ReservationAuthorized
+func NewReservationAuthorized(sv internal.ReservationService, az internal.Authorizer, p *internal.Principal) internal.ReservationService {
+	return &ReservationAuthorized{authorization: authorization{az: az, p: p, operations: internal.ReservationOperations}, sv: sv}
+}
+
+// ReservationAuthorized is a struct that implements the ReservationService interface
+// - every method requires the permission of internal.ReservationOperations before being delegated
+type ReservationAuthorized struct {
+	authorization
+	// sv is the service the authorized calls are delegated to
+	sv internal.ReservationService
+}
+
+// Reserve is a method that books a vehicle for a period and returns the stored reservation
+func (s *ReservationAuthorized) Reserve(r internal.Reservation) (stored internal.Reservation, err error) {
+	if err = s.authorize("Reserve"); err != nil {
+		return
+	}
+	return s.sv.Reserve(r)
+}
+
+// Reservation is a method that returns a reservation of a vehicle
+func (s *ReservationAuthorized) Reservation(vehicleId int, id int) (r internal.Reservation, err error) {
+	if err = s.authorize("Reservation"); err != nil {
+		return
+	}
+	return s.sv.Reservation(vehicleId, id)
+}
+
+// Cancel is a method that cancels a reservation of a vehicle and returns it
+func (s *ReservationAuthorized) Cancel(vehicleId int, id int) (r internal.Reservation, err error) {
+	if err = s.authorize("Cancel"); err != nil {
+		return
+	}
+	return s.sv.Cancel(vehicleId, id)
+}
+
+// VehicleReservations is a method that returns the reservations of a vehicle
+func (s *ReservationAuthorized) VehicleReservations(vehicleId int) (r []internal.Reservation, err error) {
+	if err = s.authorize("VehicleReservations"); err != nil {
+		return
+	}
+	return s.sv.VehicleReservations(vehicleId)
+}
+
+// Available is a method that returns the vehicles matching a filter that are free for a period
+func (s *ReservationAuthorized) Available(q internal.AvailabilityQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("Available"); err != nil {
+		return
+	}
+	return s.sv.Available(q)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewReservationDefault is a function that returns a new instance of ReservationDefault
+// - vr is the repository of the vehicles of the same tenant, the ones that are reserved
+func NewReservationDefault(rp internal.ReservationRepository, vr internal.VehicleRepository) *ReservationDefault {
+	return &ReservationDefault{rp: rp, vr: vr, now: time.Now}
+}
+
+// ReservationDefault is a struct that represents the default service for the reservations of the vehicles
+type ReservationDefault struct {
+	// rp is the repository that will be used by the service
+	rp internal.ReservationRepository
+	// vr is the repository of the vehicles that are reserved
+	vr internal.VehicleRepository
+	// now is the clock of the reservations
+	now func() time.Time
+}
+
+// validatePeriod is a function that checks a period [from, to)
+func validatePeriod(from time.Time, to time.Time) (err error) {
+	switch {
+	case from.IsZero() || to.IsZero():
+		return fmt.Errorf("%w: from and to are required", internal.ErrInvalidReservation)
+	case !to.After(from):
+		return fmt.Errorf("%w: to must be after from", internal.ErrInvalidReservation)
+	}
+	return
+}
+
+// Reserve is a method that books a vehicle for a period and returns the stored reservation
+// - deleted vehicles cannot be reserved, nor periods that already ended
+func (s *ReservationDefault) Reserve(r internal.Reservation) (stored internal.Reservation, err error) {
+	if _, err = s.vr.FindById(r.VehicleId, internal.VehicleQuery{}); err != nil {
+		return
+	}
+
+	r.Holder, r.Purpose = strings.TrimSpace(r.Holder), strings.TrimSpace(r.Purpose)
+	if err = validatePeriod(r.From, r.To); err != nil {
+		return
+	}
+	r.CreatedAt = s.now()
+	switch {
+	case r.Holder == "":
+		return internal.Reservation{}, fmt.Errorf("%w: holder is required", internal.ErrInvalidReservation)
+	case !r.To.After(r.CreatedAt):
+		return internal.Reservation{}, fmt.Errorf("%w: the period already ended", internal.ErrInvalidReservation)
+	}
+
+	if r.Id, err = s.rp.Reserve(r); err != nil {
+		return internal.Reservation{}, err
+	}
+	return r, nil
+}
+
+// Reservation is a method that returns a reservation of a vehicle, canceled or not
+// - the reservations of other vehicles are not found
+func (s *ReservationDefault) Reservation(vehicleId int, id int) (r internal.Reservation, err error) {
+	if r, err = s.rp.FindById(id); err != nil {
+		return
+	}
+	if r.VehicleId != vehicleId {
+		return internal.Reservation{}, fmt.Errorf("%w: %d", internal.ErrReservationNotFound, id)
+	}
+	return
+}
+
+// Cancel is a method that cancels a reservation of a vehicle now and returns it
+// - the reservations that already ended cannot be canceled, the canceled ones are returned as they were
+func (s *ReservationDefault) Cancel(vehicleId int, id int) (r internal.Reservation, err error) {
+	if r, err = s.Reservation(vehicleId, id); err != nil {
+		return
+	}
+	now := s.now()
+	if r.IsActive() && !r.To.After(now) {
+		return internal.Reservation{}, fmt.Errorf("%w: the reservation already ended", internal.ErrInvalidReservation)
+	}
+
+	return s.rp.Cancel(id, now)
+}
+
+// VehicleReservations is a method that returns the reservations of a vehicle, canceled or not, by start
+// - the reservations of the deleted vehicles are kept
+func (s *ReservationDefault) VehicleReservations(vehicleId int) (r []internal.Reservation, err error) {
+	if _, err = s.vr.FindById(vehicleId, internal.VehicleQuery{IncludeDeleted: true}); err != nil {
+		return
+	}
+	return s.rp.FindByVehicle(vehicleId)
+}
+
+// Available is a method that returns the vehicles matching a filter that are free for a period, by id
+func (s *ReservationDefault) Available(q internal.AvailabilityQuery) (v []internal.Vehicle, err error) {
+	if err = validatePeriod(q.From, q.To); err != nil {
+		return
+	}
+
+	reserved := make(map[int]bool)
+	overlapping, err := s.rp.FindOverlapping(q.From, q.To)
+	if err != nil {
+		return
+	}
+	for _, r := range overlapping {
+		reserved[r.VehicleId] = true
+	}
+
+	vehicles, err := s.vr.FindAll(internal.VehicleQuery{})
+	if err != nil {
+		return
+	}
+	v = make([]internal.Vehicle, 0)
+	for _, value := range vehicles {
+		if !reserved[value.Id] && q.Filter.Match(value) {
+			v = append(v, value)
+		}
+	}
+	sort.Slice(v, func(i, j int) bool {
+		return v[i].Id < v[j].Id
+	})
+	return
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for ReservationDefault
+func TestReservationDefault(t *testing.T) {
+	tomorrow := time.Now().UTC().Truncate(time.Hour).Add(24 * time.Hour)
+	at := func(hours int) time.Time { return tomorrow.Add(time.Duration(hours) * time.Hour) }
+
+	// newService is a function that returns a service with the fleet
+	newService := func() *service.ReservationDefault {
+		return service.NewReservationDefault(repository.NewReservationMap(), newFleet())
+	}
+
+	t.Run("case 1: reservations are validated", func(t *testing.T) {
+		// arrange
+		sv := newService()
+
+		// act
+		stored, err := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: " jane ", From: at(0), To: at(4)})
+		_, errConflict := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(2), To: at(6)})
+		_, errPeriod := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(6), To: at(6)})
+		_, errEnded := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(-48), To: at(-30)})
+		_, errHolder := sv.Reserve(internal.Reservation{VehicleId: 1, From: at(6), To: at(8)})
+		_, errDeleted := sv.Reserve(internal.Reservation{VehicleId: 6, Holder: "john", From: at(6), To: at(8)})
+		reservations, _ := sv.VehicleReservations(1)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, reservations, 1)
+		require.Equal(t, reservations[0], stored)
+		require.NotZero(t, stored.Id)
+		require.Equal(t, "jane", stored.Holder)
+		require.False(t, stored.CreatedAt.IsZero())
+		require.ErrorIs(t, errConflict, internal.ErrReservationConflict)
+		require.ErrorIs(t, errPeriod, internal.ErrInvalidReservation)
+		require.ErrorIs(t, errEnded, internal.ErrInvalidReservation)
+		require.ErrorIs(t, errHolder, internal.ErrInvalidReservation)
+		require.ErrorIs(t, errDeleted, internal.ErrorVehicleNotFound)
+	})
+
+	t.Run("case 2: cancellation of the reservations of a vehicle", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		stored, err := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "jane", From: at(0), To: at(4)})
+		require.NoError(t, err)
+
+		// act
+		_, errVehicle := sv.Cancel(2, stored.Id)
+		r, err := sv.Cancel(1, stored.Id)
+		_, errReserve := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "john", From: at(0), To: at(4)})
+
+		// assert
+		require.ErrorIs(t, errVehicle, internal.ErrReservationNotFound)
+		require.NoError(t, err)
+		require.False(t, r.IsActive())
+		require.NoError(t, errReserve)
+	})
+
+	t.Run("case 3: vehicles available for a period matching a filter", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		_, err := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "jane", From: at(0), To: at(4)})
+		require.NoError(t, err)
+		seats := 5.0
+		filter := internal.VehicleFilter{
+			Equals: map[internal.VehicleField]string{},
+			Ranges: map[internal.VehicleField]internal.Range{internal.FieldCapacity: {Min: &seats}},
+		}
+		automatic := internal.VehicleFilter{
+			Equals: map[internal.VehicleField]string{internal.FieldTransmission: "automatic"},
+			Ranges: filter.Ranges,
+		}
+
+		// act
+		during, err := sv.Available(internal.AvailabilityQuery{From: at(2), To: at(3), Filter: filter})
+		require.NoError(t, err)
+		after, err := sv.Available(internal.AvailabilityQuery{From: at(4), To: at(8), Filter: automatic})
+		require.NoError(t, err)
+		_, errPeriod := sv.Available(internal.AvailabilityQuery{From: at(4), To: at(2)})
+
+		// assert
+		ids := func(v []internal.Vehicle) (ids []int) {
+			for _, value := range v {
+				ids = append(ids, value.Id)
+			}
+			return
+		}
+		require.Equal(t, []int{2, 3, 5}, ids(during))
+		require.Equal(t, []int{1, 3, 5}, ids(after))
+		require.ErrorIs(t, errPeriod, internal.ErrInvalidReservation)
+	})
+
+	t.Run("case 4: a reservation is found only through its vehicle", func(t *testing.T) {
+		// arrange
+		sv := newService()
+		stored, err := sv.Reserve(internal.Reservation{VehicleId: 1, Holder: "jane", From: at(0), To: at(4)})
+		require.NoError(t, err)
+		_, err = sv.Cancel(1, stored.Id)
+		require.NoError(t, err)
+
+		// act
+		r, err := sv.Reservation(1, stored.Id)
+		_, errVehicle := sv.Reservation(2, stored.Id)
+		_, errUnknown := sv.Reservation(1, stored.Id+1)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, stored.Id, r.Id)
+		require.False(t, r.IsActive())
+		require.ErrorIs(t, errVehicle, internal.ErrReservationNotFound)
+		require.ErrorIs(t, errUnknown, internal.ErrReservationNotFound)
+	})
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+)
+
+// NewTenantProvider is a function that returns a new instance of TenantProvider
+// - bind returns the service of an unknown tenant, the one that fails closed
+func NewTenantProvider[S any](services map[string]S, bind binder[S]) *TenantProvider[S] {
+	return &TenantProvider[S]{services: services, bind: bind}
+}
+
+// TenantProvider is a struct that implements the service providers and the TenantDirectory interface
+// - each tenant has its own service, repository and sequence, so no query can reach the data of another
+type TenantProvider[S any] struct {
+	// services is a map of the service of each tenant
+	services map[string]S
+	// bind returns the authorized service that rejects the calls of an unknown tenant
+	bind binder[S]
+}
+
+// HasTenant is a method that reports if a tenant exists
+func (p *TenantProvider[S]) HasTenant(id string) bool {
+	_, ok := p.services[id]
+	return ok
+}
+
+// Service is a method that returns the service of the tenant of a context
+// - the tenant middleware only lets known tenants through, otherwise the service fails closed on every call
+func (p *TenantProvider[S]) Service(ctx context.Context) S {
+	id, _ := internal.TenantFrom(ctx)
+	if sv, ok := p.services[id]; ok {
+		return sv
+	}
+	var none S
+	return p.bind(none, tenantNotFound(id), nil)
+}
+
+// tenantNotFound is a type that implements the Authorizer interface rejecting every call of an unknown tenant
+type tenantNotFound string
+
+// Authorize is a method that returns ErrTenantNotFound
+func (t tenantNotFound) Authorize(_ *internal.Principal, _ internal.Permission) (err error) {
+	return fmt.Errorf("%w: %s", internal.ErrTenantNotFound, string(t))
+}
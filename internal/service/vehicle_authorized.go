@@ -0,0 +1,299 @@
+package service
+
+import (
+	"app/internal"
+	"time"
+)
+
+// NewVehicleAuthorized is a function that returns a new instance of VehicleAuthorized
+func NewVehicleAuthorized(sv internal.VehicleService, az internal.Authorizer, p *internal.Principal) internal.VehicleService {
+	return &VehicleAuthorized{authorization: authorization{az: az, p: p, operations: internal.VehicleOperations}, sv: sv}
+}
+
+// VehicleAuthorized is a struct that implements the VehicleService interface
+// - every method requires the permission of internal.VehicleOperations before being delegated
+type VehicleAuthorized struct {
+	authorization
+	// sv is the service the authorized calls are delegated to
+	sv internal.VehicleService
+}
+
+// authorizeIds is a method that checks the permission to choose the ids when any of the vehicles carries one
+func (s *VehicleAuthorized) authorizeIds(vehicles ...internal.Vehicle) (err error) {
+	for _, v := range vehicles {
+		if v.Id != 0 {
+			return s.az.Authorize(s.p, internal.PermissionVehiclesExplicitId)
+		}
+	}
+	return
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (s *VehicleAuthorized) FindAll(q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	if err = s.authorize("FindAll"); err != nil {
+		return
+	}
+	return s.sv.FindAll(q)
+}
+
+// List is a method that returns a filtered, sorted and paged list of vehicles and the number of vehicles filtered
+func (s *VehicleAuthorized) List(l internal.VehicleListQuery, q internal.VehicleQuery) (v []internal.Vehicle, total int, err error) {
+	if err = s.authorize("List"); err != nil {
+		return
+	}
+	return s.sv.List(l, q)
+}
+
+// FindById is a method that returns a vehicle by id
+func (s *VehicleAuthorized) FindById(id int, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	if err = s.authorize("FindById"); err != nil {
+		return
+	}
+	return s.sv.FindById(id, q)
+}
+
+// CheckRegistration is a method that validates and normalizes a registration without storing anything
+func (s *VehicleAuthorized) CheckRegistration(country string, registration string) (normalized string, format string, err error) {
+	if err = s.authorize("CheckRegistration"); err != nil {
+		return
+	}
+	return s.sv.CheckRegistration(country, registration)
+}
+
+// FindByRegistration is a method that returns a vehicle by its registration
+func (s *VehicleAuthorized) FindByRegistration(registration string, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	if err = s.authorize("FindByRegistration"); err != nil {
+		return
+	}
+	return s.sv.FindByRegistration(registration, q)
+}
+
+// FindByVIN is a method that returns a vehicle by its VIN, with the decoded VIN
+func (s *VehicleAuthorized) FindByVIN(vin string, q internal.VehicleQuery) (v internal.Vehicle, info internal.VINInfo, err error) {
+	if err = s.authorize("FindByVIN"); err != nil {
+		return
+	}
+	return s.sv.FindByVIN(vin, q)
+}
+
+// Search is a method that returns the vehicles matching a text, most relevant first
+func (s *VehicleAuthorized) Search(text string, limit int, q internal.VehicleQuery) (results []internal.VehicleSearchResult, err error) {
+	if err = s.authorize("Search"); err != nil {
+		return
+	}
+	return s.sv.Search(text, limit, q)
+}
+
+// Add is a method that adds a vehicle and returns it as stored
+func (s *VehicleAuthorized) Add(v internal.Vehicle) (stored internal.Vehicle, err error) {
+	if err = s.authorize("Add"); err != nil {
+		return
+	}
+	if err = s.authorizeIds(v); err != nil {
+		return
+	}
+	return s.sv.Add(v)
+}
+
+// SearchByColorAndYear is a method that returns the vehicles of a color and a fabrication year
+func (s *VehicleAuthorized) SearchByColorAndYear(color string, year int, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("SearchByColorAndYear"); err != nil {
+		return
+	}
+	return s.sv.SearchByColorAndYear(color, year, q)
+}
+
+// SearchByBrand is a method that returns the vehicles of a brand made in a range of years
+func (s *VehicleAuthorized) SearchByBrand(brand string, startYear int, endYear int, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("SearchByBrand"); err != nil {
+		return
+	}
+	return s.sv.SearchByBrand(brand, startYear, endYear, q)
+}
+
+// GetAverageSpeedByBrand is a method that returns the average max speed of a brand
+func (s *VehicleAuthorized) GetAverageSpeedByBrand(brand string, q internal.VehicleQuery) (avgSpeed float64, err error) {
+	if err = s.authorize("GetAverageSpeedByBrand"); err != nil {
+		return
+	}
+	return s.sv.GetAverageSpeedByBrand(brand, q)
+}
+
+// AddMultiple is a method that adds several vehicles and returns their ids
+func (s *VehicleAuthorized) AddMultiple(vehicles []internal.Vehicle) (ids []int, err error) {
+	if err = s.authorize("AddMultiple"); err != nil {
+		return
+	}
+	if err = s.authorizeIds(vehicles...); err != nil {
+		return
+	}
+	return s.sv.AddMultiple(vehicles)
+}
+
+// UpdateMaxSpeedById is a method that updates the max speed of a vehicle
+func (s *VehicleAuthorized) UpdateMaxSpeedById(id int, maxSpeed float64) (err error) {
+	if err = s.authorize("UpdateMaxSpeedById"); err != nil {
+		return
+	}
+	return s.sv.UpdateMaxSpeedById(id, maxSpeed)
+}
+
+// GetVehiclesByFuelType is a method that returns the vehicles of a fuel type
+func (s *VehicleAuthorized) GetVehiclesByFuelType(fuelType string, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("GetVehiclesByFuelType"); err != nil {
+		return
+	}
+	return s.sv.GetVehiclesByFuelType(fuelType, q)
+}
+
+// DeleteById is a method that soft deletes a vehicle
+func (s *VehicleAuthorized) DeleteById(id int) (err error) {
+	if err = s.authorize("DeleteById"); err != nil {
+		return
+	}
+	return s.sv.DeleteById(id)
+}
+
+// RestoreById is a method that restores a soft deleted vehicle
+func (s *VehicleAuthorized) RestoreById(id int) (err error) {
+	if err = s.authorize("RestoreById"); err != nil {
+		return
+	}
+	return s.sv.RestoreById(id)
+}
+
+// PurgeDeleted is a method that hard deletes the vehicles soft deleted longer than the retention
+func (s *VehicleAuthorized) PurgeDeleted(retention time.Duration) (purged int, err error) {
+	if err = s.authorize("PurgeDeleted"); err != nil {
+		return
+	}
+	return s.sv.PurgeDeleted(retention)
+}
+
+// GetVehiclesByTransmission is a method that returns the vehicles of a transmission type
+func (s *VehicleAuthorized) GetVehiclesByTransmission(transmission string, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("GetVehiclesByTransmission"); err != nil {
+		return
+	}
+	return s.sv.GetVehiclesByTransmission(transmission, q)
+}
+
+// UpdateFuelTypeById is a method that updates the fuel type of a vehicle
+func (s *VehicleAuthorized) UpdateFuelTypeById(id int, fuelType string) (err error) {
+	if err = s.authorize("UpdateFuelTypeById"); err != nil {
+		return
+	}
+	return s.sv.UpdateFuelTypeById(id, fuelType)
+}
+
+// UpdateRegistrationById is a method that updates the registration of a vehicle
+func (s *VehicleAuthorized) UpdateRegistrationById(id int, registration string) (err error) {
+	if err = s.authorize("UpdateRegistrationById"); err != nil {
+		return
+	}
+	return s.sv.UpdateRegistrationById(id, registration)
+}
+
+// UpdateDepotById is a method that moves a vehicle to a depot
+func (s *VehicleAuthorized) UpdateDepotById(id int, depotId int) (err error) {
+	if err = s.authorize("UpdateDepotById"); err != nil {
+		return
+	}
+	return s.sv.UpdateDepotById(id, depotId)
+}
+
+// GetAverageCapacityByBrand is a method that returns the average capacity of people of a brand
+func (s *VehicleAuthorized) GetAverageCapacityByBrand(brand string, q internal.VehicleQuery) (avgCapacity int, err error) {
+	if err = s.authorize("GetAverageCapacityByBrand"); err != nil {
+		return
+	}
+	return s.sv.GetAverageCapacityByBrand(brand, q)
+}
+
+// GetVehiclesByDimensions is a method that returns the vehicles in a range of length and width
+func (s *VehicleAuthorized) GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("GetVehiclesByDimensions"); err != nil {
+		return
+	}
+	return s.sv.GetVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth, q)
+}
+
+// GetVehiclesByWeight is a method that returns the vehicles in a range of weight
+func (s *VehicleAuthorized) GetVehiclesByWeight(minWeight float64, maxWeight float64, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	if err = s.authorize("GetVehiclesByWeight"); err != nil {
+		return
+	}
+	return s.sv.GetVehiclesByWeight(minWeight, maxWeight, q)
+}
+
+// NormalizeAll is a method that canonicalizes the categorical values of the stored vehicles and reports the changes
+func (s *VehicleAuthorized) NormalizeAll(dryRun bool) (changes []internal.NormalizationChange, err error) {
+	if err = s.authorize("NormalizeAll"); err != nil {
+		return
+	}
+	return s.sv.NormalizeAll(dryRun)
+}
+
+// Compare is a method that returns a side-by-side comparison of vehicles
+func (s *VehicleAuthorized) Compare(ids []int, q internal.VehicleQuery) (c internal.VehicleComparison, err error) {
+	if err = s.authorize("Compare"); err != nil {
+		return
+	}
+	return s.sv.Compare(ids, q)
+}
+
+// GetEmissions is a method that estimates the consumption, emissions and cost of a vehicle
+func (s *VehicleAuthorized) GetEmissions(id int, kmPerYear float64, q internal.VehicleQuery) (e internal.VehicleEmissions, err error) {
+	if err = s.authorize("GetEmissions"); err != nil {
+		return
+	}
+	return s.sv.GetEmissions(id, kmPerYear, q)
+}
+
+// GetEmissionsReport is a method that sums up the emissions and costs of the fleet by group
+func (s *VehicleAuthorized) GetEmissionsReport(r internal.EmissionsReportQuery, q internal.VehicleQuery) (groups []internal.EmissionsGroup, skipped int, err error) {
+	if err = s.authorize("GetEmissionsReport"); err != nil {
+		return
+	}
+	return s.sv.GetEmissionsReport(r, q)
+}
+
+// GetQuote is a method that prices the rental of a vehicle for a period
+func (s *VehicleAuthorized) GetQuote(id int, from time.Time, to time.Time, q internal.VehicleQuery) (qt internal.VehicleQuote, err error) {
+	if err = s.authorize("GetQuote"); err != nil {
+		return
+	}
+	return s.sv.GetQuote(id, from, to, q)
+}
+
+// GetSimilar is a method that returns the vehicles closest to a reference one, closest first
+func (s *VehicleAuthorized) GetSimilar(sm internal.VehicleSimilarQuery, q internal.VehicleQuery) (similar []internal.SimilarVehicle, err error) {
+	if err = s.authorize("GetSimilar"); err != nil {
+		return
+	}
+	return s.sv.GetSimilar(sm, q)
+}
+
+// GetStats is a method that computes the statistics of a numeric field by group
+func (s *VehicleAuthorized) GetStats(st internal.VehicleStatsQuery, q internal.VehicleQuery) (stats []internal.VehicleStats, err error) {
+	if err = s.authorize("GetStats"); err != nil {
+		return
+	}
+	return s.sv.GetStats(st, q)
+}
+
+// GetHistogram is a method that counts the vehicles by buckets of a numeric field
+func (s *VehicleAuthorized) GetHistogram(h internal.VehicleHistogramQuery, q internal.VehicleQuery) (buckets []internal.HistogramBucket, err error) {
+	if err = s.authorize("GetHistogram"); err != nil {
+		return
+	}
+	return s.sv.GetHistogram(h, q)
+}
+
+// GetFrequencies is a method that counts the vehicles by value of a categorical field, most frequent first
+func (s *VehicleAuthorized) GetFrequencies(f internal.VehicleFrequencyQuery, q internal.VehicleQuery) (freq []internal.Frequency, total int, err error) {
+	if err = s.authorize("GetFrequencies"); err != nil {
+		return
+	}
+	return s.sv.GetFrequencies(f, q)
+}
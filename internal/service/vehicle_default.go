@@ -4,27 +4,348 @@ import (
 	"app/internal"
 	//"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 )
 
+// ConfigVehicleDefault is a struct that represents the configuration for VehicleDefault
+type ConfigVehicleDefault struct {
+	// Tenant is the tenant that owns the vehicles of the service, stamped on the new ones
+	Tenant string
+	// Sequence is the generator of the ids of the new vehicles (required)
+	Sequence internal.VehicleSequence
+	// Registrations is the validator of the registration formats, nil rejects the vehicles with a country
+	Registrations internal.RegistrationValidator
+	// VINs is the decoder of the VINs, nil skips the VIN checks
+	VINs internal.VINDecoder
+	// Normalizer is the canonicalization of the categorical values, nil keeps them as sent
+	Normalizer internal.VehicleNormalizer
+	// Deriver is the computation of the derived attributes, the default one has no size classes
+	Deriver internal.VehicleDeriver
+	// Emissions is the estimation of the emissions, nil disables the emission reports
+	Emissions internal.VehicleEmissionsEstimator
+	// Pricing is the pricing of the rentals, nil disables the quotes
+	Pricing internal.VehiclePricer
+	// SimilarityWeights are the default weights of the fields in the distance between vehicles
+	SimilarityWeights map[internal.VehicleField]float64
+	// Depots is the repository of the depots of the tenant, nil leaves the vehicles out of any depot
+	Depots internal.DepotRepository
+}
+
 // NewVehicleDefault is a function that returns a new instance of VehicleDefault
-func NewVehicleDefault(rp internal.VehicleRepository) *VehicleDefault {
-	return &VehicleDefault{rp: rp}
+func NewVehicleDefault(rp internal.VehicleRepository, cfg *ConfigVehicleDefault) *VehicleDefault {
+	// default values
+	defaultConfig := &ConfigVehicleDefault{
+		Deriver:           &MetricsDefault{},
+		SimilarityWeights: internal.DefaultSimilarityWeights,
+	}
+	if cfg != nil {
+		defaultConfig.Tenant = cfg.Tenant
+		defaultConfig.Sequence = cfg.Sequence
+		defaultConfig.Registrations = cfg.Registrations
+		defaultConfig.VINs = cfg.VINs
+		defaultConfig.Normalizer = cfg.Normalizer
+		if cfg.Deriver != nil {
+			defaultConfig.Deriver = cfg.Deriver
+		}
+		defaultConfig.Emissions = cfg.Emissions
+		defaultConfig.Pricing = cfg.Pricing
+		defaultConfig.Depots = cfg.Depots
+		if cfg.SimilarityWeights != nil {
+			defaultConfig.SimilarityWeights = cfg.SimilarityWeights
+		}
+	}
+
+	return &VehicleDefault{
+		tenant:  defaultConfig.Tenant,
+		rp:      rp,
+		sq:      defaultConfig.Sequence,
+		rg:      defaultConfig.Registrations,
+		vn:      defaultConfig.VINs,
+		nz:      defaultConfig.Normalizer,
+		dv:      defaultConfig.Deriver,
+		em:      defaultConfig.Emissions,
+		pr:      defaultConfig.Pricing,
+		weights: defaultConfig.SimilarityWeights,
+		depots:  defaultConfig.Depots,
+	}
 }
 
 // VehicleDefault is a struct that represents the default service for vehicles
 type VehicleDefault struct {
+	// tenant is the tenant that owns the vehicles
+	tenant string
 	// rp is the repository that will be used by the service
 	rp internal.VehicleRepository
+	// sq is the generator of the ids of the new vehicles
+	sq internal.VehicleSequence
+	// rg is the validator of the registration formats
+	rg internal.RegistrationValidator
+	// vn is the decoder of the VINs
+	vn internal.VINDecoder
+	// nz is the canonicalization of the categorical values
+	nz internal.VehicleNormalizer
+	// dv is the computation of the derived attributes
+	dv internal.VehicleDeriver
+	// em is the estimation of the emissions
+	em internal.VehicleEmissionsEstimator
+	// pr is the pricing of the rentals
+	pr internal.VehiclePricer
+	// weights are the default weights of the fields in the distance between vehicles
+	weights map[internal.VehicleField]float64
+	// depots is the repository of the depots of the tenant
+	depots internal.DepotRepository
+}
+
+// normalizedFields are the categorical fields canonicalized on write
+var normalizedFields = []internal.VehicleField{internal.FieldBrand, internal.FieldColor, internal.FieldFuelType, internal.FieldTransmission}
+
+// canonical is a method that returns the canonical value of a field, used on writes and on filters
+func (s *VehicleDefault) canonical(field internal.VehicleField, value string) string {
+	if s.nz == nil {
+		return value
+	}
+	return s.nz.Normalize(field, value)
+}
+
+// normalize is a method that canonicalizes the categorical values of a vehicle
+// and returns the changes made
+func (s *VehicleDefault) normalize(v *internal.Vehicle) (changes []internal.NormalizationChange) {
+	values := map[internal.VehicleField]*string{
+		internal.FieldBrand:        &v.Brand,
+		internal.FieldColor:        &v.Color,
+		internal.FieldFuelType:     &v.FuelType,
+		internal.FieldTransmission: &v.Transmission,
+	}
+
+	for _, field := range normalizedFields {
+		value := values[field]
+		if canonical := s.canonical(field, *value); canonical != *value {
+			changes = append(changes, internal.NormalizationChange{Id: v.Id, Field: field, From: *value, To: canonical})
+			*value = canonical
+		}
+	}
+
+	return
+}
+
+// inUnits is a function that converts the measures of the vehicles to a unit system
+func inUnits(v []internal.Vehicle, u internal.UnitSystem) {
+	for i := range v {
+		v[i] = u.Vehicle(v[i])
+	}
+}
+
+// statsInUnits is a function that converts the statistics of a field to a unit system
+// - the conversions are proportional, so every statistic converts as a single value
+func statsInUnits(st *internal.VehicleStats, field internal.VehicleField, u internal.UnitSystem) {
+	for _, value := range []*float64{&st.Sum, &st.Min, &st.Max, &st.Mean, &st.Median, &st.StdDev} {
+		*value = u.FromCanonical(field, *value)
+	}
+	for p, value := range st.Percentiles {
+		st.Percentiles[p] = u.FromCanonical(field, value)
+	}
+}
+
+// normalizeFilter is a method that canonicalizes the values a filter compares
+func (s *VehicleDefault) normalizeFilter(f internal.VehicleFilter) internal.VehicleFilter {
+	equals := make(map[internal.VehicleField]string, len(f.Equals))
+	for field, value := range f.Equals {
+		equals[field] = s.canonical(field, value)
+	}
+	f.Equals = equals
+	return f
+}
+
+// NormalizeAll is a method that canonicalizes the categorical values of the stored vehicles, deleted or not,
+// and reports the changes; a dry run only reports them
+func (s *VehicleDefault) NormalizeAll(dryRun bool) (changes []internal.NormalizationChange, err error) {
+	v, err := s.rp.FindAll(internal.VehicleQuery{IncludeDeleted: true})
+	if err != nil {
+		return
+	}
+
+	ids := make([]int, 0, len(v))
+	for id := range v {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		vehicle := v[id]
+		changed := s.normalize(&vehicle)
+		if len(changed) == 0 {
+			continue
+		}
+
+		if !dryRun {
+			if err = s.rp.ReplaceById(vehicle); err != nil {
+				return
+			}
+		}
+		changes = append(changes, changed...)
+	}
+
+	return
 }
 
 // FindAll is a method that returns a map of all vehicles
-func (s *VehicleDefault) FindAll() (v map[int]internal.Vehicle, err error) {
-	v, err = s.rp.FindAll()
+func (s *VehicleDefault) FindAll(q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	v, err = s.rp.FindAll(q)
+	if err != nil {
+		return
+	}
+
+	converted := make(map[int]internal.Vehicle, len(v))
+	for id, vehicle := range v {
+		converted[id] = q.Units.Vehicle(vehicle)
+	}
+	return converted, nil
+}
+
+// List is a method that returns a filtered, sorted and paged list of vehicles
+func (s *VehicleDefault) List(l internal.VehicleListQuery, q internal.VehicleQuery) (v []internal.Vehicle, total int, err error) {
+	if l.Offset < 0 || l.Limit < 0 {
+		return nil, 0, fmt.Errorf("%w: offset and limit can not be negative", internal.ErrInvalidSort)
+	}
+	for _, key := range l.Sort {
+		if !key.Field.IsNumeric() && !key.Field.IsCategorical() {
+			return nil, 0, fmt.Errorf("%w: %s", internal.ErrInvalidSort, key.Field)
+		}
+	}
+	l.Filter = q.Units.Filter(s.normalizeFilter(l.Filter))
+
+	v, total, err = s.rp.List(l, q)
+	inUnits(v, q.Units)
+	return
+}
+
+// FindById is a method that returns a vehicle by id
+func (s *VehicleDefault) FindById(id int, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	v, err = s.rp.FindById(id, q)
+	v = q.Units.Vehicle(v)
+	return
+}
+
+// FindByRegistration is a method that returns a vehicle by its registration, in any of its written forms
+func (s *VehicleDefault) FindByRegistration(registration string, q internal.VehicleQuery) (v internal.Vehicle, err error) {
+	v, err = s.rp.FindByRegistration(registration, q)
+	v = q.Units.Vehicle(v)
+	return
+}
+
+// CheckRegistration is a method that validates and normalizes a registration without storing anything
+func (s *VehicleDefault) CheckRegistration(country string, registration string) (normalized string, format string, err error) {
+	normalized, err = ValidateRegistration(registration)
+	if err != nil {
+		return
+	}
+
+	if country == "" {
+		return
+	}
+
+	if s.rg == nil {
+		err = fmt.Errorf("%w: %s", internal.ErrUnknownRegistrationCountry, country)
+		return
+	}
+	format, err = s.rg.Validate(country, normalized)
+	return
+}
+
+// validateRegistration is a method that normalizes the registration of a vehicle
+// and checks its format when the vehicle carries a country, with the same rules as CheckRegistration
+func (s *VehicleDefault) validateRegistration(v *internal.Vehicle) (err error) {
+	v.Country = strings.ToUpper(v.Country)
+	v.Registration, _, err = s.CheckRegistration(v.Country, v.Registration)
+	return
+}
+
+// FindByVIN is a method that returns a vehicle by its VIN, with the decoded VIN
+func (s *VehicleDefault) FindByVIN(vin string, q internal.VehicleQuery) (v internal.Vehicle, info internal.VINInfo, err error) {
+	v, err = s.rp.FindByVIN(internal.NormalizeVIN(vin), q)
+	v = q.Units.Vehicle(v)
+	if err != nil || s.vn == nil {
+		return
+	}
+
+	// vehicles loaded with an invalid VIN are still found, without information
+	info, _ = s.vn.Decode(v.VIN)
+	return
+}
+
+// validateVIN is a method that normalizes the VIN of a vehicle, validates its check digit
+// and cross-checks the decoded manufacturer and model year with the brand and the fabrication year
+func (s *VehicleDefault) validateVIN(v *internal.Vehicle) (err error) {
+	v.VIN = internal.NormalizeVIN(v.VIN)
+	if v.VIN == "" || s.vn == nil {
+		return
+	}
+
+	info, err := s.vn.Decode(v.VIN)
+	if err != nil {
+		return
+	}
+
+	// brand: only known manufacturers can be checked
+	if len(info.Brands) > 0 {
+		var match bool
+		for _, brand := range info.Brands {
+			if strings.EqualFold(brand, v.Brand) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return fmt.Errorf("%w: %s builds %s, not %s", internal.ErrVINMismatch, info.WMI, strings.Join(info.Brands, ", "), v.Brand)
+		}
+	}
+
+	// year: the model year may be the year after the fabrication
+	if v.FabricationYear != 0 && info.ModelYear != v.FabricationYear && info.ModelYear != v.FabricationYear+1 {
+		return fmt.Errorf("%w: model year %d, fabrication year %d", internal.ErrVINMismatch, info.ModelYear, v.FabricationYear)
+	}
+
+	return
+}
+
+// assignId is a method that sets the id of a new vehicle
+// - vehicles without id get the next one of the sequence
+// - explicit ids are authorized by VehicleAuthorized, start at 1 and the sequence is advanced past them
+func (s *VehicleDefault) assignId(v *internal.Vehicle) (err error) {
+	if v.Id == 0 {
+		v.Id, err = s.sq.Next()
+		return
+	}
+	if v.Id < 1 {
+		return fmt.Errorf("%w: %d, the ids start at 1", internal.ErrInvalidVehicleId, v.Id)
+	}
+
+	err = s.sq.Advance(v.Id)
 	return
 }
 
 // Add is a method that adds a vehicle //Exercise 1 POST /vehicles
-func (s *VehicleDefault) Add(v internal.Vehicle) (err error) {
+func (s *VehicleDefault) Add(v internal.Vehicle) (stored internal.Vehicle, err error) {
+	v.Tenant = s.tenant
+	s.normalize(&v)
+	v.Metrics = s.dv.Derive(v)
+
+	if err = s.validateRegistration(&v); err != nil {
+		return
+	}
+
+	if err = s.validateVIN(&v); err != nil {
+		return
+	}
+
+	if err = s.assignId(&v); err != nil {
+		return
+	}
+
 	err = s.rp.Add(v)
 
 	if err != nil{
@@ -33,17 +354,23 @@ func (s *VehicleDefault) Add(v internal.Vehicle) (err error) {
 			case internal.ErrorVehicleAlreadyExists:
 				err = fmt.Errorf("%w: id", internal.ErrorVehicleAlreadyExists)
 
+			case internal.ErrorRegistrationAlreadyExists:
+				err = fmt.Errorf("%w: %s", internal.ErrorRegistrationAlreadyExists, v.Registration)
+
+			case internal.ErrorVINAlreadyExists:
+				err = fmt.Errorf("%w: %s", internal.ErrorVINAlreadyExists, v.VIN)
+
 			}
 
 			return
 		}
 	
-	return
+	return v, nil
 }
 
 // Search vehicles by color and year //Exercise 2 GET /vehicles/color/{color}/year/{year}
-func (s *VehicleDefault) SearchByColorAndYear(color string, year int) (v []internal.Vehicle, err error) {
-	v, err = s.rp.SearchByColorAndYear(color, year)
+func (s *VehicleDefault) SearchByColorAndYear(color string, year int, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
+	v, err = s.rp.SearchByColorAndYear(s.canonical(internal.FieldColor, color), year, q)
 
 	if err != nil {
 
@@ -52,13 +379,14 @@ func (s *VehicleDefault) SearchByColorAndYear(color string, year int) (v []inter
 
 	}
 
+	inUnits(v, q.Units)
 	return
 }
 
 // Search vehicles by brand and year range //Exercise 3 GET /vehicles/brand/{brand}/between/{start_year}/{end_year}
-func (s *VehicleDefault) SearchByBrand(brand string, start_year int, end_year int) (v []internal.Vehicle, err error) {
+func (s *VehicleDefault) SearchByBrand(brand string, start_year int, end_year int, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
 
-	v, err = s.rp.SearchByBrand(brand, start_year, end_year)
+	v, err = s.rp.SearchByBrand(s.canonical(internal.FieldBrand, brand), start_year, end_year, q)
 
 	if err != nil {
 
@@ -67,23 +395,43 @@ func (s *VehicleDefault) SearchByBrand(brand string, start_year int, end_year in
 
 	}
 
+	inUnits(v, q.Units)
 	return
 }
 
 // Get average speed by brand //Exercise 4 GET /vehicles/average_speed/brand/{brand}
-func (s *VehicleDefault) GetAverageSpeedByBrand(brand string) (avgSpeed float64, err error) {
+func (s *VehicleDefault) GetAverageSpeedByBrand(brand string, q internal.VehicleQuery) (avgSpeed float64, err error) {
 
-	avgSpeed, err = s.rp.GetAverageSpeedByBrand(brand)
+	avgSpeed, err = s.rp.GetAverageSpeedByBrand(s.canonical(internal.FieldBrand, brand), q)
 
 	if err != nil {
 		err = fmt.Errorf("%w", internal.ErrorVehiclesNotFound)
 		return
 	}
-	
+
+	avgSpeed = q.Units.FromCanonical(internal.FieldMaxSpeed, avgSpeed)
 	return
 }
 
-func (s *VehicleDefault) AddMultiple(vehicles []internal.Vehicle) (err error){
+func (s *VehicleDefault) AddMultiple(vehicles []internal.Vehicle) (ids []int, err error){
+	for i := range vehicles {
+		vehicles[i].Tenant = s.tenant
+		s.normalize(&vehicles[i])
+		vehicles[i].Metrics = s.dv.Derive(vehicles[i])
+		if err = s.validateRegistration(&vehicles[i]); err != nil {
+			return
+		}
+		if err = s.validateVIN(&vehicles[i]); err != nil {
+			return
+		}
+	}
+
+	for i := range vehicles {
+		if err = s.assignId(&vehicles[i]); err != nil {
+			return
+		}
+	}
+
 	err = s.rp.AddMultiple(vehicles)
 
 	if err != nil {
@@ -91,6 +439,10 @@ func (s *VehicleDefault) AddMultiple(vehicles []internal.Vehicle) (err error){
 		return
 	}
 
+	for _, value := range vehicles {
+		ids = append(ids, value.Id)
+	}
+
 	return
 }
 
@@ -115,15 +467,16 @@ func ValidateSpeed(speed float64) error{
 	return nil
 }
 
-func (s *VehicleDefault) GetVehiclesByFuelType(fuelType string) (v []internal.Vehicle, err error){
+func (s *VehicleDefault) GetVehiclesByFuelType(fuelType string, q internal.VehicleQuery) (v []internal.Vehicle, err error){
 
-	v, err = s.rp.GetVehiclesByFuelType(fuelType)
+	v, err = s.rp.GetVehiclesByFuelType(s.canonical(internal.FieldFuelType, fuelType), q)
 
 	if err != nil {
 		err = fmt.Errorf("%w", err)
 		return
 	}
 
+	inUnits(v, q.Units)
 	return v, nil
 
 }
@@ -139,14 +492,34 @@ func (s *VehicleDefault) DeleteById(id int) (err error){
 	return
 }
 
-func (s *VehicleDefault) GetVehiclesByTransmission(transmission string) (v []internal.Vehicle, err error) {
+func (s *VehicleDefault) RestoreById(id int) (err error){
+	err = s.rp.RestoreById(id)
+
+	if err != nil {
+		err = fmt.Errorf("%w", err)
+		return
+	}
+
+	return
+}
+
+// PurgeDeleted hard deletes the vehicles that have been soft deleted for longer than the retention
+func (s *VehicleDefault) PurgeDeleted(retention time.Duration) (purged int, err error){
+	purged, err = s.rp.PurgeDeleted(time.Now().Add(-retention))
+	return
+}
+
+func (s *VehicleDefault) GetVehiclesByTransmission(transmission string, q internal.VehicleQuery) (v []internal.Vehicle, err error) {
 
-	v, err = s.rp.GetVehiclesByTransmission(transmission)
+	v, err = s.rp.GetVehiclesByTransmission(s.canonical(internal.FieldTransmission, transmission), q)
+	inUnits(v, q.Units)
 	return
 
 }
 
 func (s *VehicleDefault) UpdateFuelTypeById(id int, fuelType string) (err error){
+	fuelType = s.canonical(internal.FieldFuelType, fuelType)
+
 	if err := ValidateFuelType(fuelType); err != nil{
 		return err
 	}
@@ -160,31 +533,227 @@ func (s *VehicleDefault) UpdateFuelTypeById(id int, fuelType string) (err error)
 
 func ValidateFuelType(fuelType string) error{
 	switch fuelType{
-		case "biodiesel", "gas", "gasoil", "diesel", "gasoline", "electric":
+		case "biodiesel", "gas", "diesel", "gasoline", "electric":
 			return nil
 		default:
 			return internal.ErrInvalidFuelType
 	}
 }
 
-func (s *VehicleDefault) GetAverageCapacityByBrand(brand string) (avgCapacity int, err error){
+// UpdateRegistrationById is a method that changes the registration of a vehicle, keeping it unique
+func (s *VehicleDefault) UpdateRegistrationById(id int, registration string) (err error){
+	v, err := s.rp.FindById(id, internal.VehicleQuery{})
+	if err != nil {
+		return
+	}
+
+	v.Registration = registration
+	if err = s.validateRegistration(&v); err != nil {
+		return
+	}
+	registration = v.Registration
 
-	avgCapacity, err = s.rp.GetAverageCapacityByBrand(brand)
+	if err = s.rp.UpdateRegistrationById(id, registration); err != nil {
+		if err == internal.ErrorRegistrationAlreadyExists {
+			err = fmt.Errorf("%w: %s", err, registration)
+		}
+		return
+	}
 
 	return
+}
+
+// UpdateDepotById is a method that moves a vehicle to a depot of its tenant, 0 takes it out of any depot
+func (s *VehicleDefault) UpdateDepotById(id int, depotId int) (err error) {
+	switch {
+	case depotId < 0:
+		return fmt.Errorf("%w: %d", internal.ErrDepotNotFound, depotId)
+	case depotId > 0 && s.depots == nil:
+		return fmt.Errorf("%w: %d", internal.ErrDepotNotFound, depotId)
+	case depotId > 0:
+		if _, err = s.depots.FindById(depotId); err != nil {
+			return
+		}
+	}
+
+	return s.rp.UpdateDepotById(id, depotId)
+}
 
+// ValidateRegistration is a function that returns the normalized registration, or an error if it is empty
+func ValidateRegistration(registration string) (normalized string, err error){
+	normalized = internal.NormalizeRegistration(registration)
+	if normalized == "" {
+		return "", internal.ErrInvalidRegistration
+	}
+	return normalized, nil
 }
 
-func (s *VehicleDefault) GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64) (v []internal.Vehicle, err error){
+func (s *VehicleDefault) GetAverageCapacityByBrand(brand string, q internal.VehicleQuery) (avgCapacity int, err error){
+
+	avgCapacity, err = s.rp.GetAverageCapacityByBrand(s.canonical(internal.FieldBrand, brand), q)
 
-	v, err = s.GetVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth)
 	return
 
 }
 
-func (s *VehicleDefault) GetVehiclesByWeight(minWeight float64, maxWeight float64) (v []internal.Vehicle, err error){
+func (s *VehicleDefault) GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64, q internal.VehicleQuery) (v []internal.Vehicle, err error){
+
+	minLength, maxLength = q.Units.ToCanonical(internal.FieldLength, minLength), q.Units.ToCanonical(internal.FieldLength, maxLength)
+	minWidth, maxWidth = q.Units.ToCanonical(internal.FieldWidth, minWidth), q.Units.ToCanonical(internal.FieldWidth, maxWidth)
 
-	v, err = s.GetVehiclesByWeight(minWeight, maxWeight)
+	v, err = s.rp.GetVehiclesByDimensions(minLength, maxLength, minWidth, maxWidth, q)
+	inUnits(v, q.Units)
 	return
 
+}
+
+func (s *VehicleDefault) GetVehiclesByWeight(minWeight float64, maxWeight float64, q internal.VehicleQuery) (v []internal.Vehicle, err error){
+
+	minWeight, maxWeight = q.Units.ToCanonical(internal.FieldWeight, minWeight), q.Units.ToCanonical(internal.FieldWeight, maxWeight)
+
+	v, err = s.rp.GetVehiclesByWeight(minWeight, maxWeight, q)
+	inUnits(v, q.Units)
+	return
+
+}
+// GetStats is a method that computes the statistics of a numeric field by group
+// - the field must be numeric and the grouping fields categorical
+// - the median and the quartiles are computed when no percentiles are requested
+func (s *VehicleDefault) GetStats(st internal.VehicleStatsQuery, q internal.VehicleQuery) (stats []internal.VehicleStats, err error) {
+	if !st.Field.IsNumeric() {
+		return nil, fmt.Errorf("%w: %s is not numeric", internal.ErrInvalidVehicleField, st.Field)
+	}
+	for _, field := range st.GroupBy {
+		if !field.IsCategorical() {
+			return nil, fmt.Errorf("%w: %s can not be grouped by", internal.ErrInvalidVehicleField, field)
+		}
+	}
+	for _, p := range st.Percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("%w: %g", internal.ErrInvalidPercentile, p)
+		}
+	}
+	if len(st.Percentiles) == 0 {
+		st.Percentiles = []float64{25, 50, 75}
+	}
+
+	st.Filter = q.Units.Filter(s.normalizeFilter(st.Filter))
+
+	stats, err = s.rp.Aggregate(st, q)
+	for i := range stats {
+		statsInUnits(&stats[i], st.Field, q.Units)
+	}
+	return
+}
+
+// GetHistogram is a method that counts the vehicles by buckets of a numeric field
+// - explicit edges must be strictly ascending, otherwise 10 buckets are used by default
+func (s *VehicleDefault) GetHistogram(h internal.VehicleHistogramQuery, q internal.VehicleQuery) (buckets []internal.HistogramBucket, err error) {
+	if !h.Field.IsNumeric() {
+		return nil, fmt.Errorf("%w: %s is not numeric", internal.ErrInvalidVehicleField, h.Field)
+	}
+
+	switch {
+	case len(h.Edges) > 0:
+		if len(h.Edges) < 2 {
+			return nil, fmt.Errorf("%w: at least two edges are needed", internal.ErrInvalidBuckets)
+		}
+		for i := 1; i < len(h.Edges); i++ {
+			if h.Edges[i] <= h.Edges[i-1] {
+				return nil, fmt.Errorf("%w: edges must be ascending", internal.ErrInvalidBuckets)
+			}
+		}
+	case h.Buckets == 0:
+		h.Buckets = 10
+	case h.Buckets < 0 || h.Buckets > 1000:
+		return nil, fmt.Errorf("%w: %d buckets", internal.ErrInvalidBuckets, h.Buckets)
+	}
+
+	h.Filter = q.Units.Filter(s.normalizeFilter(h.Filter))
+	edges := h.Edges
+	h.Edges = make([]float64, len(edges))
+	for i, edge := range edges {
+		h.Edges[i] = q.Units.ToCanonical(h.Field, edge)
+	}
+
+	buckets, err = s.rp.Histogram(h, q)
+	for i := range buckets {
+		// explicit edges are returned as sent, without the rounding of a round trip
+		if len(edges) > 0 {
+			buckets[i].Min, buckets[i].Max = edges[i], edges[i+1]
+			continue
+		}
+		buckets[i].Min = q.Units.FromCanonical(h.Field, buckets[i].Min)
+		buckets[i].Max = q.Units.FromCanonical(h.Field, buckets[i].Max)
+	}
+	return
+}
+
+// GetFrequencies is a method that counts the vehicles by value of a categorical field
+func (s *VehicleDefault) GetFrequencies(f internal.VehicleFrequencyQuery, q internal.VehicleQuery) (freq []internal.Frequency, total int, err error) {
+	if !f.Field.IsCategorical() {
+		return nil, 0, fmt.Errorf("%w: %s is not categorical", internal.ErrInvalidVehicleField, f.Field)
+	}
+	if f.Top < 0 {
+		f.Top = 0
+	}
+
+	f.Filter = q.Units.Filter(s.normalizeFilter(f.Filter))
+
+	freq, total, err = s.rp.Frequencies(f, q)
+	return
+}
+
+// GetSimilar is a method that returns the vehicles closest to a reference one, closest first
+// - 10 vehicles are returned by default, 100 at most
+// - the weights of the query replace the default weight of their fields
+func (s *VehicleDefault) GetSimilar(sm internal.VehicleSimilarQuery, q internal.VehicleQuery) (similar []internal.SimilarVehicle, err error) {
+	switch {
+	case sm.K == 0:
+		sm.K = 10
+	case sm.K < 0 || sm.K > 100:
+		return nil, fmt.Errorf("%w: k must be between 1 and 100", internal.ErrInvalidSimilarity)
+	}
+
+	weights := make(map[internal.VehicleField]float64, len(internal.SimilarityFields))
+	for field, weight := range s.weights {
+		weights[field] = weight
+	}
+	for field, weight := range sm.Weights {
+		if !slices.Contains(internal.SimilarityFields, field) {
+			return nil, fmt.Errorf("%w: %s can not be weighted", internal.ErrInvalidSimilarity, field)
+		}
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("%w: invalid weight of %s", internal.ErrInvalidSimilarity, field)
+		}
+		weights[field] = weight
+	}
+	sm.Weights = weights
+	sm.Filter = q.Units.Filter(s.normalizeFilter(sm.Filter))
+
+	similar, err = s.rp.Similar(sm, q)
+	for i := range similar {
+		similar[i].Vehicle = q.Units.Vehicle(similar[i].Vehicle)
+	}
+	return
+}
+
+// Search is a method that returns the vehicles matching a text, most relevant first
+// - 20 results are returned by default, 100 at most
+func (s *VehicleDefault) Search(text string, limit int, q internal.VehicleQuery) (results []internal.VehicleSearchResult, err error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("%w: empty text", internal.ErrInvalidSearch)
+	}
+	switch {
+	case limit == 0:
+		limit = 20
+	case limit < 0 || limit > 100:
+		return nil, fmt.Errorf("%w: limit must be between 1 and 100", internal.ErrInvalidSearch)
+	}
+
+	results, err = s.rp.Search(text, limit, q)
+	for i := range results {
+		results[i].Vehicle = q.Units.Vehicle(results[i].Vehicle)
+	}
+	return
 }
\ No newline at end of file
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"math"
+)
+
+// comparedCategories are the categorical attributes of a comparison, in order
+var comparedCategories = []struct {
+	name  string
+	value func(v internal.Vehicle) string
+}{
+	{name: "brand", value: func(v internal.Vehicle) string { return v.Brand }},
+	{name: "model", value: func(v internal.Vehicle) string { return v.Model }},
+	{name: "registration", value: func(v internal.Vehicle) string { return v.Registration }},
+	{name: "color", value: func(v internal.Vehicle) string { return v.Color }},
+	{name: "fuel_type", value: func(v internal.Vehicle) string { return v.FuelType }},
+	{name: "transmission", value: func(v internal.Vehicle) string { return v.Transmission }},
+	{name: "size_class", value: func(v internal.Vehicle) string { return v.Metrics.SizeClass }},
+}
+
+// comparedMetrics are the numeric attributes of a comparison, in order
+var comparedMetrics = []struct {
+	name       string
+	derived    bool
+	preference internal.Preference
+	unit       func(u internal.UnitSystem) string
+	value      func(v internal.Vehicle) float64
+}{
+	{name: "year", preference: internal.PreferHigher, value: func(v internal.Vehicle) float64 { return float64(v.FabricationYear) }},
+	{name: "passengers", preference: internal.PreferHigher, value: func(v internal.Vehicle) float64 { return float64(v.Capacity) }},
+	{name: "max_speed", unit: fieldUnit(internal.FieldMaxSpeed), preference: internal.PreferHigher, value: func(v internal.Vehicle) float64 { return v.MaxSpeed }},
+	{name: "weight", unit: fieldUnit(internal.FieldWeight), preference: internal.PreferLower, value: func(v internal.Vehicle) float64 { return v.Weight }},
+	{name: "height", unit: fieldUnit(internal.FieldHeight), value: func(v internal.Vehicle) float64 { return v.Height }},
+	{name: "length", unit: fieldUnit(internal.FieldLength), value: func(v internal.Vehicle) float64 { return v.Length }},
+	{name: "width", unit: fieldUnit(internal.FieldWidth), value: func(v internal.Vehicle) float64 { return v.Width }},
+	{name: "volume", derived: true, unit: fieldUnit(internal.FieldVolume), preference: internal.PreferHigher, value: func(v internal.Vehicle) float64 { return v.Metrics.Volume }},
+	{name: "cargo_volume", derived: true, unit: fieldUnit(internal.FieldCargoVolume), preference: internal.PreferHigher, value: func(v internal.Vehicle) float64 { return v.Metrics.CargoVolume }},
+	{name: "footprint", derived: true, unit: fieldUnit(internal.FieldFootprint), preference: internal.PreferLower, value: func(v internal.Vehicle) float64 { return v.Metrics.Footprint }},
+	{name: "density", derived: true, unit: fieldUnit(internal.FieldDensity), value: func(v internal.Vehicle) float64 { return v.Metrics.Density }},
+	// speed_to_weight stands for the power to weight ratio, the data has no engine power
+	{name: "speed_to_weight", derived: true, preference: internal.PreferHigher, unit: func(u internal.UnitSystem) string {
+		return u.Unit(internal.FieldMaxSpeed) + " per " + u.Unit(internal.FieldWeight)
+	}, value: func(v internal.Vehicle) float64 {
+		if v.Weight == 0 {
+			return 0
+		}
+		return v.MaxSpeed / v.Weight
+	}},
+}
+
+// fieldUnit is a function that returns the unit of a field in a unit system
+func fieldUnit(field internal.VehicleField) func(u internal.UnitSystem) string {
+	return func(u internal.UnitSystem) string { return u.Unit(field) }
+}
+
+// Compare is a method that returns a side-by-side comparison of 2 to 10 vehicles
+func (s *VehicleDefault) Compare(ids []int, q internal.VehicleQuery) (c internal.VehicleComparison, err error) {
+	if len(ids) < 2 || len(ids) > 10 {
+		return c, fmt.Errorf("%w: between 2 and 10 vehicles can be compared", internal.ErrInvalidComparison)
+	}
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return c, fmt.Errorf("%w: vehicle %d is repeated", internal.ErrInvalidComparison, id)
+		}
+		seen[id] = true
+	}
+
+	for _, id := range ids {
+		v, err := s.rp.FindById(id, q)
+		if err != nil {
+			return c, fmt.Errorf("%w: %d", err, id)
+		}
+		c.Vehicles = append(c.Vehicles, q.Units.Vehicle(v))
+	}
+
+	c.Rows = compareVehicles(c.Vehicles, q.Units)
+	return
+}
+
+// compareVehicles is a function that builds the rows of a comparison
+// - the vehicles and the units are those of the same unit system
+func compareVehicles(vehicles []internal.Vehicle, u internal.UnitSystem) (rows []internal.ComparisonRow) {
+	for _, ct := range comparedCategories {
+		row := internal.ComparisonRow{Name: ct.name}
+		for _, v := range vehicles {
+			row.Values = append(row.Values, ct.value(v))
+		}
+		rows = append(rows, row)
+	}
+
+	for _, mt := range comparedMetrics {
+		row := internal.ComparisonRow{Name: mt.name, Derived: mt.derived, Numeric: true, Preference: mt.preference}
+		if mt.unit != nil {
+			row.Unit = mt.unit(u)
+		}
+		for _, v := range vehicles {
+			row.Numbers = append(row.Numbers, mt.value(v))
+		}
+
+		// percentage differences with the first vehicle
+		for _, n := range row.Numbers {
+			if row.Numbers[0] == 0 {
+				row.Differences = append(row.Differences, nil)
+				continue
+			}
+			diff := math.Round((n-row.Numbers[0])/math.Abs(row.Numbers[0])*10000) / 100
+			row.Differences = append(row.Differences, &diff)
+		}
+
+		// best and worst, only when the values differ
+		if mt.preference != internal.PreferNone {
+			better := func(a, b float64) bool {
+				if mt.preference == internal.PreferHigher {
+					return a > b
+				}
+				return a < b
+			}
+			best, worst := row.Numbers[0], row.Numbers[0]
+			for _, n := range row.Numbers {
+				if better(n, best) {
+					best = n
+				}
+				if better(worst, n) {
+					worst = n
+				}
+			}
+			if best != worst {
+				for i, n := range row.Numbers {
+					if n == best {
+						row.Best = append(row.Best, vehicles[i].Id)
+					}
+					if n == worst {
+						row.Worst = append(row.Worst, vehicles[i].Id)
+					}
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the comparison of vehicles
+func TestVehicleDefault_Compare(t *testing.T) {
+	db := map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 100, Weight: 1000, Dimensions: internal.Dimensions{Height: 100, Length: 400, Width: 200}}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Audi", MaxSpeed: 150, Weight: 1000, Dimensions: internal.Dimensions{Height: 200, Length: 500, Width: 200}}},
+		3: {Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", MaxSpeed: 150, Weight: 500, Dimensions: internal.Dimensions{Height: 100, Length: 300, Width: 200}}},
+	}
+	dv, err := service.NewMetricsDefault(internal.MetricsRules{})
+	require.NoError(t, err)
+	for id, v := range db {
+		v.Metrics = dv.Derive(v)
+		db[id] = v
+	}
+	sv := service.NewVehicleDefault(repository.NewVehicleMap(db), nil)
+
+	// row is a function that returns a row of a comparison by name
+	row := func(c internal.VehicleComparison, name string) internal.ComparisonRow {
+		for _, r := range c.Rows {
+			if r.Name == name {
+				return r
+			}
+		}
+		t.Fatalf("row %s not found", name)
+		return internal.ComparisonRow{}
+	}
+
+	t.Run("case 1: best, worst and differences of each metric", func(t *testing.T) {
+		// act
+		c, err := sv.Compare([]int{1, 2, 3}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, []string{"Ford", "Audi", "Fiat"}, row(c, "brand").Values)
+
+		speed := row(c, "max_speed")
+		require.Equal(t, []int{2, 3}, speed.Best)
+		require.Equal(t, []int{1}, speed.Worst)
+		require.Equal(t, 50.0, *speed.Differences[1])
+
+		weight := row(c, "weight")
+		require.Equal(t, []int{3}, weight.Best)
+		require.Equal(t, []int{1, 2}, weight.Worst)
+
+		volume := row(c, "volume")
+		require.True(t, volume.Derived)
+		require.Equal(t, []float64{8, 20, 6}, volume.Numbers)
+		require.Equal(t, 150.0, *volume.Differences[1])
+
+		ratio := row(c, "speed_to_weight")
+		require.Equal(t, []int{3}, ratio.Best)
+
+		height := row(c, "height")
+		require.Empty(t, height.Best)
+	})
+
+	t.Run("case 2: equal values are not ranked", func(t *testing.T) {
+		// act
+		c, err := sv.Compare([]int{1, 2}, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, row(c, "weight").Best)
+		require.Empty(t, row(c, "weight").Worst)
+	})
+
+	t.Run("case 3: invalid lists of vehicles", func(t *testing.T) {
+		// act
+		_, errOne := sv.Compare([]int{1}, internal.VehicleQuery{})
+		_, errRepeated := sv.Compare([]int{1, 1}, internal.VehicleQuery{})
+		_, errMissing := sv.Compare([]int{1, 9}, internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, errOne, internal.ErrInvalidComparison)
+		require.ErrorIs(t, errRepeated, internal.ErrInvalidComparison)
+		require.ErrorIs(t, errMissing, internal.ErrorVehicleNotFound)
+	})
+}
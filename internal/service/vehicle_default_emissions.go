@@ -0,0 +1,114 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// validateKmPerYear is a function that defaults and validates the distance driven every year
+func validateKmPerYear(kmPerYear float64) (float64, error) {
+	switch {
+	case kmPerYear == 0:
+		return 15000, nil
+	case kmPerYear < 0 || kmPerYear > 1000000:
+		return 0, fmt.Errorf("%w: km_per_year must be between 1 and 1000000", internal.ErrInvalidEmissionsQuery)
+	}
+	return kmPerYear, nil
+}
+
+// GetEmissions is a method that estimates the consumption, emissions and cost of a vehicle
+// - 15000 km per year are driven by default
+func (s *VehicleDefault) GetEmissions(id int, kmPerYear float64, q internal.VehicleQuery) (e internal.VehicleEmissions, err error) {
+	if s.em == nil {
+		return e, fmt.Errorf("%w: no emission factors configured", internal.ErrNoEmissionFactor)
+	}
+	if kmPerYear, err = validateKmPerYear(kmPerYear); err != nil {
+		return
+	}
+
+	v, err := s.rp.FindById(id, q)
+	if err != nil {
+		return
+	}
+
+	// the estimation is made in the canonical units
+	if e, err = s.em.Estimate(v, kmPerYear); err != nil {
+		return
+	}
+	e.Vehicle = q.Units.Vehicle(e.Vehicle)
+	return
+}
+
+// GetEmissionsReport is a method that sums up the emissions and costs of the fleet by group
+// - the vehicles without emission factor for their fuel type are left out and counted in skipped
+// - the vehicles are grouped by brand and fuel type by default
+func (s *VehicleDefault) GetEmissionsReport(r internal.EmissionsReportQuery, q internal.VehicleQuery) (groups []internal.EmissionsGroup, skipped int, err error) {
+	if s.em == nil {
+		return nil, 0, fmt.Errorf("%w: no emission factors configured", internal.ErrNoEmissionFactor)
+	}
+	if r.KmPerYear, err = validateKmPerYear(r.KmPerYear); err != nil {
+		return
+	}
+	if len(r.GroupBy) == 0 {
+		r.GroupBy = []internal.VehicleField{internal.FieldBrand, internal.FieldFuelType}
+	}
+	for _, field := range r.GroupBy {
+		if !field.IsCategorical() {
+			return nil, 0, fmt.Errorf("%w: %s can not be grouped by", internal.ErrInvalidVehicleField, field)
+		}
+	}
+
+	v, _, err := s.rp.List(internal.VehicleListQuery{Filter: q.Units.Filter(s.normalizeFilter(r.Filter))}, q)
+	if err != nil {
+		return
+	}
+
+	// group the estimations
+	byKey := make(map[string]*internal.EmissionsGroup)
+	for _, vehicle := range v {
+		e, err := s.em.Estimate(vehicle, r.KmPerYear)
+		if err != nil {
+			skipped++
+			continue
+		}
+
+		group := make(map[internal.VehicleField]string, len(r.GroupBy))
+		parts := make([]string, len(r.GroupBy))
+		for i, field := range r.GroupBy {
+			group[field] = field.Categorical(vehicle)
+			parts[i] = group[field]
+		}
+		key := strings.Join(parts, "\x00")
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &internal.EmissionsGroup{Group: group}
+			byKey[key] = g
+		}
+		g.Count++
+		g.CO2PerYear += e.CO2PerYear
+		g.MeanCO2PerKm += e.CO2PerKm
+		g.CostPerYear += e.CostPerYear
+	}
+
+	if len(byKey) == 0 {
+		return nil, skipped, internal.ErrorVehiclesNotFound
+	}
+
+	// sorted by key, as the statistics
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		g := byKey[key]
+		g.MeanCO2PerKm /= float64(g.Count)
+		groups = append(groups, *g)
+	}
+
+	return
+}
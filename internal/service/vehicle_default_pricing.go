@@ -0,0 +1,26 @@
+package service
+
+import (
+	"app/internal"
+	"fmt"
+	"time"
+)
+
+// GetQuote is a method that prices the rental of a vehicle for a period [from, to)
+func (s *VehicleDefault) GetQuote(id int, from time.Time, to time.Time, q internal.VehicleQuery) (qt internal.VehicleQuote, err error) {
+	if s.pr == nil {
+		return qt, fmt.Errorf("%w: no pricing rules configured", internal.ErrNoBaseRate)
+	}
+
+	v, err := s.rp.FindById(id, q)
+	if err != nil {
+		return
+	}
+
+	// the rules are evaluated in the canonical units
+	if qt, err = s.pr.Quote(v, from, to); err != nil {
+		return
+	}
+	qt.Vehicle = q.Units.Vehicle(qt.Vehicle)
+	return
+}
@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the soft deletes
+func TestVehicleDefault_SoftDelete(t *testing.T) {
+	t.Run("case 1: deleted vehicles are restored or purged once the retention is over", func(t *testing.T) {
+		// arrange
+		longAgo, recently := time.Now().Add(-48*time.Hour), time.Now().Add(-time.Hour)
+		rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+			1: {Id: 1, DeletedAt: &longAgo},
+			2: {Id: 2, DeletedAt: &recently},
+			3: {Id: 3},
+		})
+		sv := service.NewVehicleDefault(rp, nil)
+
+		// act
+		purged, err := sv.PurgeDeleted(24 * time.Hour)
+		errPurged := sv.RestoreById(1)
+		errRestore := sv.RestoreById(2)
+		errActive := sv.RestoreById(3)
+		errDelete := sv.DeleteById(3)
+		v, _ := sv.FindAll(internal.VehicleQuery{})
+		withDeleted, _ := sv.FindAll(internal.VehicleQuery{IncludeDeleted: true})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, purged)
+		require.ErrorIs(t, errPurged, internal.ErrorVehicleNotFound)
+		require.NoError(t, errRestore)
+		require.ErrorIs(t, errActive, internal.ErrorVehicleNotDeleted)
+		require.NoError(t, errDelete)
+		require.Len(t, v, 1)
+		require.Contains(t, v, 2)
+		require.Len(t, withDeleted, 2)
+	})
+}
+
+// Tests for the searches by dimensions and weight, which used to call themselves forever
+func TestVehicleDefault_Ranges(t *testing.T) {
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Weight: 900, Dimensions: internal.Dimensions{Length: 370, Width: 160}}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Weight: 2500, Dimensions: internal.Dimensions{Length: 550, Width: 200}}},
+	})
+	sv := service.NewVehicleDefault(rp, nil)
+
+	t.Run("case 1: vehicles within the dimensions", func(t *testing.T) {
+		// act
+		v, err := sv.GetVehiclesByDimensions(300, 400, 150, 170, internal.VehicleQuery{})
+		_, errNone := sv.GetVehiclesByDimensions(600, 700, 150, 170, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+		require.Equal(t, 1, v[0].Id)
+		require.ErrorIs(t, errNone, internal.ErrorVehiclesNotFound)
+	})
+
+	t.Run("case 2: vehicles within the weight", func(t *testing.T) {
+		// act
+		v, err := sv.GetVehiclesByWeight(2000, 3000, internal.VehicleQuery{})
+		_, errNone := sv.GetVehiclesByWeight(3000, 4000, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+		require.Equal(t, 2, v[0].Id)
+		require.ErrorIs(t, errNone, internal.ErrorVehiclesNotFound)
+	})
+}
+
+// Tests for the registrations of the written vehicles
+func TestVehicleDefault_Registration(t *testing.T) {
+	t.Run("case 1: without formats, writes and checks reject the same countries", func(t *testing.T) {
+		// arrange
+		sq, err := repository.NewVehicleSequenceFile("")
+		require.NoError(t, err)
+		sv := service.NewVehicleDefault(repository.NewVehicleMap(nil), &service.ConfigVehicleDefault{Sequence: sq})
+
+		// act
+		_, _, errCheck := sv.CheckRegistration("es", "1234 BCD")
+		_, errAdd := sv.Add(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Registration: "1234 BCD", Country: "es"}})
+		normalized, _, errCheckNone := sv.CheckRegistration("", "1234 bcd")
+		added, errAddNone := sv.Add(internal.Vehicle{VehicleAttributes: internal.VehicleAttributes{Registration: "1234 bcd"}})
+		v, _ := sv.FindById(added.Id, internal.VehicleQuery{})
+
+		// assert
+		require.ErrorIs(t, errCheck, internal.ErrUnknownRegistrationCountry)
+		require.ErrorIs(t, errAdd, internal.ErrUnknownRegistrationCountry)
+		require.NoError(t, errCheckNone)
+		require.NoError(t, errAddNone)
+		require.Equal(t, normalized, v.Registration)
+	})
+}
@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for the reads in a unit system
+func TestVehicleDefault_Units(t *testing.T) {
+	rp := repository.NewVehicleMap(map[int]internal.Vehicle{
+		1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 160.9344, Weight: 1000, Dimensions: internal.Dimensions{Height: 254, Length: 508, Width: 254}}},
+		2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: 80.4672, Weight: 2000, Dimensions: internal.Dimensions{Height: 127, Length: 254, Width: 127}}},
+	})
+	sv := service.NewVehicleDefault(rp, nil)
+	imperial := internal.VehicleQuery{Units: internal.UnitsImperial}
+
+	t.Run("case 1: the measures of the vehicles are converted", func(t *testing.T) {
+		// act
+		v, err := sv.FindById(1, imperial)
+
+		// assert
+		require.NoError(t, err)
+		require.InDelta(t, 100, v.MaxSpeed, 1e-9)
+		require.InDelta(t, 2204.62, v.Weight, 0.01)
+		require.InDelta(t, 100, v.Height, 1e-9)
+		require.InDelta(t, 200, v.Length, 1e-9)
+	})
+
+	t.Run("case 2: the filters are read in the unit system", func(t *testing.T) {
+		// act
+		v, err := sv.GetVehiclesByWeight(4000, 5000, imperial)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, v, 1)
+		require.Equal(t, 2, v[0].Id)
+	})
+
+	t.Run("case 3: the aggregates are converted", func(t *testing.T) {
+		// arrange
+		min := 60.0
+		st := internal.VehicleStatsQuery{
+			Field:  internal.FieldMaxSpeed,
+			Filter: internal.VehicleFilter{Ranges: map[internal.VehicleField]internal.Range{internal.FieldMaxSpeed: {Min: &min}}},
+		}
+
+		// act
+		avg, errAvg := sv.GetAverageSpeedByBrand("Ford", imperial)
+		stats, errStats := sv.GetStats(st, imperial)
+
+		// assert
+		require.NoError(t, errAvg)
+		require.InDelta(t, 75, avg, 1e-9)
+		require.NoError(t, errStats)
+		require.Equal(t, 1, stats[0].Count)
+		require.InDelta(t, 100, stats[0].Mean, 1e-9)
+	})
+
+	t.Run("case 4: the stored vehicles keep the canonical units", func(t *testing.T) {
+		// act
+		v, err := sv.FindById(1, internal.VehicleQuery{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 160.9344, v.MaxSpeed)
+	})
+}
+
+// Tests for the conversion of the values tagged with a unit
+func TestConvertToCanonical(t *testing.T) {
+	cases := []struct {
+		name     string
+		field    internal.VehicleField
+		value    float64
+		unit     string
+		expected float64
+		err      error
+	}{
+		{name: "case 1: speed in mph", field: internal.FieldMaxSpeed, value: 100, unit: "mph", expected: 160.9344},
+		{name: "case 2: weight in tonnes", field: internal.FieldWeight, value: 1.5, unit: "t", expected: 1500},
+		{name: "case 3: length in feet", field: internal.FieldLength, value: 10, unit: "FT", expected: 304.8},
+		{name: "case 4: unit of another magnitude", field: internal.FieldWeight, value: 1, unit: "mph", err: internal.ErrInvalidUnit},
+		{name: "case 5: field without unit", field: internal.FieldYear, value: 1, unit: "cm", err: internal.ErrInvalidUnit},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			value, err := internal.ConvertToCanonical(c.field, c.value, c.unit)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			require.InDelta(t, c.expected, value, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"app/internal"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed vin_wmi.json
+var vinWMITable []byte
+
+// WMIJSON is a struct that represents a world manufacturer identifier in JSON format
+type WMIJSON struct {
+	WMI          string   `json:"wmi"`
+	Manufacturer string   `json:"manufacturer"`
+	Country      string   `json:"country"`
+	Brands       []string `json:"brands"`
+}
+
+// NewVINDefault is a function that returns a new instance of VINDefault with the embedded WMI table
+func NewVINDefault() (d *VINDefault, err error) {
+	var table []WMIJSON
+	if err = json.Unmarshal(vinWMITable, &table); err != nil {
+		return
+	}
+
+	d = &VINDefault{wmis: make(map[string]WMIJSON)}
+	for _, value := range table {
+		d.wmis[value.WMI] = value
+	}
+
+	return
+}
+
+// VINDefault is a struct that implements the VINDecoder interface following ISO 3779
+type VINDefault struct {
+	// wmis is a map of the known world manufacturer identifiers
+	wmis map[string]WMIJSON
+}
+
+var (
+	// vinValues is the transliteration of each VIN character to compute the check digit
+	vinValues = map[rune]int{
+		'0': 0, '1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
+		'A': 1, 'B': 2, 'C': 3, 'D': 4, 'E': 5, 'F': 6, 'G': 7, 'H': 8,
+		'J': 1, 'K': 2, 'L': 3, 'M': 4, 'N': 5, 'P': 7, 'R': 9,
+		'S': 2, 'T': 3, 'U': 4, 'V': 5, 'W': 6, 'X': 7, 'Y': 8, 'Z': 9,
+	}
+	// vinWeights is the weight of each VIN position to compute the check digit
+	vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+	// vinYearCodes are the model year codes, starting in 1980 and repeating every 30 years
+	vinYearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+)
+
+// VINCheckDigit is a function that returns the expected check digit (position 9) of a VIN
+func VINCheckDigit(vin string) (digit byte, err error) {
+	if len(vin) != 17 {
+		return 0, fmt.Errorf("%w: must have 17 characters", internal.ErrInvalidVIN)
+	}
+
+	var sum int
+	for i, r := range vin {
+		value, ok := vinValues[r]
+		if !ok {
+			return 0, fmt.Errorf("%w: invalid character %q", internal.ErrInvalidVIN, r)
+		}
+		sum += value * vinWeights[i]
+	}
+
+	if sum%11 == 10 {
+		return 'X', nil
+	}
+	return byte('0' + sum%11), nil
+}
+
+// Decode is a method that validates a normalized VIN and returns its information
+func (d *VINDefault) Decode(vin string) (info internal.VINInfo, err error) {
+	digit, err := VINCheckDigit(vin)
+	if err != nil {
+		return
+	}
+	if vin[8] != digit {
+		err = fmt.Errorf("%w: check digit is %c, expected %c", internal.ErrInvalidVIN, vin[8], digit)
+		return
+	}
+
+	// model year: the 7th position tells the cycle (digit 1980-2009, letter 2010-2039)
+	index := strings.IndexByte(vinYearCodes, vin[9])
+	if index < 0 {
+		err = fmt.Errorf("%w: invalid model year code %c", internal.ErrInvalidVIN, vin[9])
+		return
+	}
+	year := 1980 + index
+	if vin[6] < '0' || vin[6] > '9' {
+		year += 30
+	}
+
+	info = internal.VINInfo{
+		WMI:       vin[:3],
+		ModelYear: year,
+		Plant:     vin[10:11],
+		Serial:    vin[11:],
+	}
+	if wmi, ok := d.wmis[info.WMI]; ok {
+		info.Manufacturer = wmi.Manufacturer
+		info.Country = wmi.Country
+		info.Brands = wmi.Brands
+	}
+
+	return
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"app/internal"
+	"app/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests for VINDefault
+func TestVINDefault_Decode(t *testing.T) {
+	vn, err := service.NewVINDefault()
+	require.NoError(t, err)
+
+	t.Run("case 1: known manufacturer and model year of the first cycle", func(t *testing.T) {
+		// act
+		info, err := vn.Decode("1G1JC5240R7252367")
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, "1G1", info.WMI)
+		require.Equal(t, []string{"Chevrolet"}, info.Brands)
+		require.Equal(t, 1994, info.ModelYear)
+		require.Equal(t, "7", info.Plant)
+		require.Equal(t, "252367", info.Serial)
+	})
+
+	t.Run("case 2: unknown manufacturer with check digit X", func(t *testing.T) {
+		// act
+		info, err := vn.Decode("1M8GDM9AXKP042788")
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, info.Manufacturer)
+		require.Equal(t, 1989, info.ModelYear)
+	})
+
+	t.Run("case 3: wrong check digit", func(t *testing.T) {
+		// act
+		_, err := vn.Decode("1G1JC5241R7252367")
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrInvalidVIN)
+	})
+
+	t.Run("case 4: forbidden characters and length", func(t *testing.T) {
+		// act
+		_, errChar := vn.Decode("1G1JC524OR7252367")
+		_, errLen := vn.Decode("1G1JC5240R725236")
+
+		// assert
+		require.ErrorIs(t, errChar, internal.ErrInvalidVIN)
+		require.ErrorIs(t, errLen, internal.ErrInvalidVIN)
+	})
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrTenantNotFound = errors.New("Tenant not found")
+	ErrTenantRequired = errors.New("Tenant required")
+	ErrTenantMismatch = errors.New("Tenant mismatch")
+)
+
+// DefaultTenant is the tenant of the requests that name none, when it exists
+const DefaultTenant = "default"
+
+// Tenant is a struct that represents an isolated catalog of vehicles
+type Tenant struct {
+	// Id is the id of the tenant, as in the principals and the X-Tenant-Id header
+	Id string
+	// LoaderFilePath is the path to the file that contains the vehicles of the tenant
+	LoaderFilePath string
+	// SequenceFilePath is the path to the file that persists the last vehicle id of the tenant
+	SequenceFilePath string
+}
+
+// TenantLoader is an interface that represents the loader for the tenants
+type TenantLoader interface {
+	// Load is a method that loads the tenants
+	Load() (t []Tenant, err error)
+}
+
+// TenantDirectory is an interface that represents the known tenants
+type TenantDirectory interface {
+	// HasTenant is a method that reports if a tenant exists
+	HasTenant(id string) bool
+}
+
+// tenantKey is the key of the tenant in a context
+type tenantKey struct{}
+
+// WithTenant is a function that returns a copy of a context carrying a tenant
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// TenantFrom is a function that returns the tenant of a context, if any
+func TenantFrom(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(tenantKey{}).(string)
+	return
+}
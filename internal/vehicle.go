@@ -1,12 +1,14 @@
 package internal
 
+import "time"
+
 // Dimensions is a struct that represents a dimension in 3d
 type Dimensions struct {
-	// Height is the height of the dimension
+	// Height is the height of the dimension in cm
 	Height float64
-	// Length is the length of the dimension
+	// Length is the length of the dimension in cm
 	Length float64
-	// Width is the width of the dimension
+	// Width is the width of the dimension in cm
 	Width float64
 }
 
@@ -18,19 +20,23 @@ type VehicleAttributes struct {
 	Model string
 	// Registration is the registration of the vehicle
 	Registration string
+	// Country is the country code of the registration, empty if unknown
+	Country string
+	// VIN is the vehicle identification number, empty if unknown
+	VIN string
 	// Color is the color of the vehicle
 	Color string
 	// FabricationYear is the fabrication year of the vehicle
 	FabricationYear int
 	// Capacity is the capacity of people of the vehicle
 	Capacity int
-	// MaxSpeed is the maximum speed of the vehicle
+	// MaxSpeed is the maximum speed of the vehicle in km/h
 	MaxSpeed float64
 	// FuelType is the fuel type of the vehicle
 	FuelType string
 	// Transmission is the transmission of the vehicle
 	Transmission string
-	// Weight is the weight of the vehicle
+	// Weight is the weight of the vehicle in kg
 	Weight float64
 	// Dimensions is the dimensions of the vehicle
 	Dimensions
@@ -38,9 +44,21 @@ type VehicleAttributes struct {
 
 // Vehicle is a struct that represents a vehicle
 type Vehicle struct {
-	// Id is the unique identifier of the vehicle
+	// Id is the unique identifier of the vehicle within its tenant
 	Id int
 
+	// Tenant is the id of the tenant that owns the vehicle
+	Tenant string
+
+	// DepotId is the id of the depot that owns the vehicle, 0 if none
+	DepotId int
+
 	// VehicleAttribue is the attributes of a vehicle
 	VehicleAttributes
+
+	// Metrics are the attributes derived from the others
+	Metrics VehicleMetrics
+
+	// DeletedAt is the moment the vehicle was soft deleted, nil if it is not deleted
+	DeletedAt *time.Time
 }
@@ -0,0 +1,49 @@
+package internal
+
+import "errors"
+
+var (
+	ErrInvalidComparison = errors.New("Invalid comparison")
+)
+
+// Preference is the direction in which a numeric metric is better
+type Preference string
+
+const (
+	// PreferHigher means that the highest value is the best
+	PreferHigher Preference = "higher"
+	// PreferLower means that the lowest value is the best
+	PreferLower Preference = "lower"
+	// PreferNone means that the values are not ranked
+	PreferNone Preference = ""
+)
+
+// ComparisonRow is a struct that represents an attribute of the compared vehicles
+type ComparisonRow struct {
+	// Name is the name of the attribute
+	Name string
+	// Derived reports if the attribute is computed from others
+	Derived bool
+	// Unit is the unit of a numeric attribute, empty if it has none
+	Unit string
+	// Numeric reports if the attribute is a number, so Numbers is set instead of Values
+	Numeric bool
+	// Values are the values of a categorical attribute, in the order of the vehicles
+	Values []string
+	// Numbers are the values of a numeric attribute, in the order of the vehicles
+	Numbers []float64
+	// Preference is the direction in which the numeric attribute is better
+	Preference Preference
+	// Best and Worst are the ids of the vehicles with the best and the worst value, empty if not ranked
+	Best, Worst []int
+	// Differences are the percentage differences with the first vehicle, nil when its value is 0
+	Differences []*float64
+}
+
+// VehicleComparison is a struct that represents a side-by-side comparison of vehicles
+type VehicleComparison struct {
+	// Vehicles are the compared vehicles, in the requested order
+	Vehicles []Vehicle
+	// Rows are the attributes of the vehicles
+	Rows []ComparisonRow
+}
@@ -0,0 +1,96 @@
+package internal
+
+import "errors"
+
+var (
+	ErrNoEmissionFactor      = errors.New("No emission factor for the fuel type")
+	ErrInvalidEmissionsQuery = errors.New("Invalid emissions query")
+	ErrInvalidEmissionRules  = errors.New("Invalid emission rules")
+)
+
+// EmissionFactor is a struct that represents how a fuel type is consumed, emitted and paid
+// - the consumption per 100 km is Base + PerTonne * weight in t + PerKmh * max speed in km/h
+type EmissionFactor struct {
+	// FuelType is the fuel type, one of the values accepted for the vehicles
+	FuelType string
+	// Unit is the unit the fuel is measured in, e.g. l, kg or kWh
+	Unit string
+	// Base is the consumption per 100 km of a vehicle without weight nor speed
+	Base float64
+	// PerTonne is the consumption per 100 km added by each tonne of weight
+	PerTonne float64
+	// PerKmh is the consumption per 100 km added by each km/h of max speed
+	PerKmh float64
+	// CO2PerUnit is the CO2 emitted by each unit of fuel in kg
+	CO2PerUnit float64
+	// PricePerUnit is the price of each unit of fuel
+	PricePerUnit float64
+}
+
+// EmissionRules is a struct that represents the emission factors of every fuel type
+type EmissionRules struct {
+	// Currency is the currency of the prices
+	Currency string
+	// Factors are the emission factors by fuel type
+	Factors []EmissionFactor
+}
+
+// EmissionRulesLoader is an interface that represents the loader for the emission rules
+type EmissionRulesLoader interface {
+	// Load is a method that loads the emission rules
+	Load() (r EmissionRules, err error)
+}
+
+// VehicleEmissions is a struct that represents the estimated consumption, emissions and cost of a vehicle
+type VehicleEmissions struct {
+	// Vehicle is the vehicle of the estimation
+	Vehicle Vehicle
+	// Unit is the unit the fuel is measured in
+	Unit string
+	// ConsumptionPer100Km is the fuel consumed every 100 km
+	ConsumptionPer100Km float64
+	// KmPerYear is the distance driven every year
+	KmPerYear float64
+	// FuelPerYear is the fuel consumed every year
+	FuelPerYear float64
+	// CO2PerKm is the CO2 emitted every km in g
+	CO2PerKm float64
+	// CO2PerYear is the CO2 emitted every year in kg
+	CO2PerYear float64
+	// CostPerKm is the cost of the fuel of every km
+	CostPerKm float64
+	// CostPerYear is the cost of the fuel of every year
+	CostPerYear float64
+	// Currency is the currency of the costs
+	Currency string
+}
+
+// VehicleEmissionsEstimator is an interface that represents the estimation of the emissions of the vehicles
+type VehicleEmissionsEstimator interface {
+	// Estimate is a method that returns the emissions of a vehicle driven a distance every year
+	Estimate(v Vehicle, kmPerYear float64) (e VehicleEmissions, err error)
+}
+
+// EmissionsReportQuery is a struct that represents a fleet-wide report of the emissions
+type EmissionsReportQuery struct {
+	// KmPerYear is the distance each vehicle is driven every year
+	KmPerYear float64
+	// GroupBy are the categorical fields the vehicles are grouped by
+	GroupBy []VehicleField
+	// Filter are the conditions the vehicles must meet to be reported
+	Filter VehicleFilter
+}
+
+// EmissionsGroup is a struct that represents the emissions of a group of vehicles
+type EmissionsGroup struct {
+	// Group are the values of the grouping fields
+	Group map[VehicleField]string
+	// Count is the number of vehicles
+	Count int
+	// CO2PerYear is the CO2 emitted by the group every year in kg
+	CO2PerYear float64
+	// MeanCO2PerKm is the mean of the CO2 emitted by each vehicle every km in g
+	MeanCO2PerKm float64
+	// CostPerYear is the cost of the fuel of the group every year
+	CostPerYear float64
+}
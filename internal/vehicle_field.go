@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidVehicleField = errors.New("Invalid vehicle field")
+)
+
+// VehicleField is the name of an attribute of a vehicle that can be filtered, grouped or aggregated
+type VehicleField string
+
+const (
+	// categorical fields
+	FieldBrand        VehicleField = "brand"
+	FieldModel        VehicleField = "model"
+	FieldColor        VehicleField = "color"
+	FieldFuelType     VehicleField = "fuel_type"
+	FieldTransmission VehicleField = "transmission"
+	FieldCountry      VehicleField = "country"
+	FieldDecade       VehicleField = "decade"
+	FieldSizeClass    VehicleField = "size_class"
+	FieldDepot        VehicleField = "depot"
+
+	// numeric fields
+	FieldMaxSpeed VehicleField = "max_speed"
+	FieldWeight   VehicleField = "weight"
+	FieldCapacity VehicleField = "passengers"
+	FieldHeight   VehicleField = "height"
+	FieldLength   VehicleField = "length"
+	FieldWidth    VehicleField = "width"
+	FieldYear     VehicleField = "year"
+
+	// derived numeric fields
+	FieldVolume      VehicleField = "volume"
+	FieldCargoVolume VehicleField = "cargo_volume"
+	FieldFootprint   VehicleField = "footprint"
+	FieldDensity     VehicleField = "density"
+)
+
+var (
+	// categoricalFields are the fields that can be grouped by or compared for equality
+	categoricalFields = map[VehicleField]func(v Vehicle) string{
+		FieldBrand:        func(v Vehicle) string { return v.Brand },
+		FieldModel:        func(v Vehicle) string { return v.Model },
+		FieldColor:        func(v Vehicle) string { return v.Color },
+		FieldFuelType:     func(v Vehicle) string { return v.FuelType },
+		FieldTransmission: func(v Vehicle) string { return v.Transmission },
+		FieldCountry:      func(v Vehicle) string { return v.Country },
+		FieldDecade:       func(v Vehicle) string { return fmt.Sprintf("%ds", v.FabricationYear/10*10) },
+		FieldSizeClass:    func(v Vehicle) string { return v.Metrics.SizeClass },
+		FieldDepot:        func(v Vehicle) string { return depotValue(v.DepotId) },
+	}
+	// numericFields are the fields that can be aggregated or compared by range
+	numericFields = map[VehicleField]func(v Vehicle) float64{
+		FieldMaxSpeed: func(v Vehicle) float64 { return v.MaxSpeed },
+		FieldWeight:   func(v Vehicle) float64 { return v.Weight },
+		FieldCapacity: func(v Vehicle) float64 { return float64(v.Capacity) },
+		FieldHeight:   func(v Vehicle) float64 { return v.Height },
+		FieldLength:   func(v Vehicle) float64 { return v.Length },
+		FieldWidth:    func(v Vehicle) float64 { return v.Width },
+		FieldYear:     func(v Vehicle) float64 { return float64(v.FabricationYear) },
+		// derived
+		FieldVolume:      func(v Vehicle) float64 { return v.Metrics.Volume },
+		FieldCargoVolume: func(v Vehicle) float64 { return v.Metrics.CargoVolume },
+		FieldFootprint:   func(v Vehicle) float64 { return v.Metrics.Footprint },
+		FieldDensity:     func(v Vehicle) float64 { return v.Metrics.Density },
+	}
+	// fieldAliases are other accepted names of the fields
+	fieldAliases = map[string]VehicleField{
+		"capacity":         FieldCapacity,
+		"fabrication_year": FieldYear,
+	}
+)
+
+// depotValue is a function that returns the categorical value of the depot of a vehicle, empty if none
+func depotValue(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+// ParseVehicleField is a function that returns the field of a name, accepting its aliases
+func ParseVehicleField(name string) (f VehicleField, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := fieldAliases[name]; ok {
+		return alias, nil
+	}
+
+	f = VehicleField(name)
+	if !f.IsCategorical() && !f.IsNumeric() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidVehicleField, name)
+	}
+	return
+}
+
+// IsCategorical is a method that reports if the field can be grouped by
+func (f VehicleField) IsCategorical() bool {
+	_, ok := categoricalFields[f]
+	return ok
+}
+
+// IsNumeric is a method that reports if the field can be aggregated
+func (f VehicleField) IsNumeric() bool {
+	_, ok := numericFields[f]
+	return ok
+}
+
+// Categorical is a method that returns the value of a categorical field of a vehicle
+func (f VehicleField) Categorical(v Vehicle) string {
+	if value, ok := categoricalFields[f]; ok {
+		return value(v)
+	}
+	return ""
+}
+
+// Numeric is a method that returns the value of a numeric field of a vehicle
+func (f VehicleField) Numeric(v Vehicle) float64 {
+	if value, ok := numericFields[f]; ok {
+		return value(v)
+	}
+	return 0
+}
+
+// Range is a struct that represents an inclusive range of values, nil bounds are open
+type Range struct {
+	// Min is the lower bound
+	Min *float64
+	// Max is the upper bound
+	Max *float64
+}
+
+// Contains is a method that reports if a value is in the range
+func (r Range) Contains(value float64) bool {
+	return (r.Min == nil || value >= *r.Min) && (r.Max == nil || value <= *r.Max)
+}
+
+// VehicleFilter is a struct that represents the conditions a vehicle must meet
+type VehicleFilter struct {
+	// Equals are the values the categorical fields must have
+	Equals map[VehicleField]string
+	// Ranges are the ranges the numeric fields must be in
+	Ranges map[VehicleField]Range
+}
+
+// Match is a method that reports if a vehicle meets every condition of the filter
+// - categorical values are compared case insensitively
+func (f VehicleFilter) Match(v Vehicle) bool {
+	for field, value := range f.Equals {
+		if !strings.EqualFold(field.Categorical(v), value) {
+			return false
+		}
+	}
+	for field, rg := range f.Ranges {
+		if !rg.Contains(field.Numeric(v)) {
+			return false
+		}
+	}
+	return true
+}
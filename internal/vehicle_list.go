@@ -0,0 +1,27 @@
+package internal
+
+import "errors"
+
+var (
+	ErrInvalidSort = errors.New("Invalid sort")
+)
+
+// SortKey is a struct that represents a field the vehicles are sorted by
+type SortKey struct {
+	// Field is the field, categorical or numeric
+	Field VehicleField
+	// Desc is a flag that sorts from the highest to the lowest value
+	Desc bool
+}
+
+// VehicleListQuery is a struct that represents a filtered, sorted and paged list of vehicles
+type VehicleListQuery struct {
+	// Filter are the conditions the vehicles must meet
+	Filter VehicleFilter
+	// Sort are the fields the vehicles are sorted by, the id breaks the ties
+	Sort []SortKey
+	// Offset is the number of vehicles skipped
+	Offset int
+	// Limit is the maximum number of vehicles returned, 0 returns all of them
+	Limit int
+}
@@ -0,0 +1,49 @@
+package internal
+
+import "errors"
+
+var (
+	ErrInvalidMetricsRules = errors.New("Invalid metrics rules")
+)
+
+// VehicleMetrics is a struct that represents the attributes derived from the dimensions, the weight and the capacity
+type VehicleMetrics struct {
+	// Volume is the volume enclosed by the dimensions in m³
+	Volume float64
+	// CargoVolume is the volume left once the seats are taken out in m³
+	CargoVolume float64
+	// Footprint is the area the vehicle covers on the ground in m²
+	Footprint float64
+	// Density is the weight per volume in kg/m³, 0 when the volume is unknown
+	Density float64
+	// SizeClass is the size class of the vehicle, empty when no class matches
+	SizeClass string
+}
+
+// SizeClass is a struct that represents a size class and the conditions its vehicles meet
+type SizeClass struct {
+	// Name is the name of the class, e.g. mini, compact, mid-size, full-size, van or truck
+	Name string
+	// Filter are the ranges of the numeric fields, derived or not, of the vehicles of the class
+	Filter VehicleFilter
+}
+
+// MetricsRules is a struct that represents the parameters of the derived attributes
+type MetricsRules struct {
+	// SeatVolume is the volume each passenger takes out of the cargo volume in m³
+	SeatVolume float64
+	// SizeClasses are the size classes, the first one matching a vehicle is its class
+	SizeClasses []SizeClass
+}
+
+// MetricsRulesLoader is an interface that represents the loader for the rules of the derived attributes
+type MetricsRulesLoader interface {
+	// Load is a method that loads the rules of the derived attributes
+	Load() (r MetricsRules, err error)
+}
+
+// VehicleDeriver is an interface that represents the computation of the derived attributes
+type VehicleDeriver interface {
+	// Derive is a method that returns the derived attributes of a vehicle
+	Derive(v Vehicle) VehicleMetrics
+}
@@ -0,0 +1,35 @@
+package internal
+
+// NormalizationRule is a struct that represents how the values of a categorical field are canonicalized
+type NormalizationRule struct {
+	// Field is the categorical field the rule applies to
+	Field VehicleField
+	// Case is the casing of the values without synonym: lower, upper, title or empty to keep it
+	Case string
+	// Synonyms is a map of each canonical value to its aliases
+	Synonyms map[string][]string
+}
+
+// NormalizationRuleLoader is an interface that represents the loader for normalization rules
+type NormalizationRuleLoader interface {
+	// Load is a method that loads the normalization rules
+	Load() (r []NormalizationRule, err error)
+}
+
+// VehicleNormalizer is an interface that represents the canonicalization of the categorical values
+type VehicleNormalizer interface {
+	// Normalize is a method that returns the canonical value of a field
+	Normalize(field VehicleField, value string) string
+}
+
+// NormalizationChange is a struct that represents a value changed by a normalization
+type NormalizationChange struct {
+	// Id is the id of the vehicle
+	Id int
+	// Field is the field changed
+	Field VehicleField
+	// From is the value before the normalization
+	From string
+	// To is the canonical value
+	To string
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrForbidden     = errors.New("Forbidden")
+	ErrInvalidPolicy = errors.New("Invalid policy")
+)
+
+// Permission is the name of an action a principal can be allowed to do, e.g. vehicles:read
+// - "*" grants every permission and "vehicles:*" every permission of the vehicles
+type Permission string
+
+const (
+	PermissionVehiclesRead               Permission = "vehicles:read"
+	PermissionVehiclesCreate             Permission = "vehicles:create"
+	PermissionVehiclesBatchImport        Permission = "vehicles:batch_import"
+	PermissionVehiclesUpdateSpeed        Permission = "vehicles:update_speed"
+	PermissionVehiclesUpdateFuel         Permission = "vehicles:update_fuel"
+	PermissionVehiclesUpdateRegistration Permission = "vehicles:update_registration"
+	PermissionVehiclesDelete             Permission = "vehicles:delete"
+	PermissionVehiclesRestore            Permission = "vehicles:restore"
+	PermissionVehiclesPurge              Permission = "vehicles:purge"
+	PermissionVehiclesNormalize          Permission = "vehicles:normalize"
+	PermissionVehiclesUpdateDepot        Permission = "vehicles:update_depot"
+	PermissionDepotsRead                 Permission = "depots:read"
+	PermissionDepotsCreate               Permission = "depots:create"
+	PermissionDriversRead                Permission = "drivers:read"
+	PermissionDriversCreate              Permission = "drivers:create"
+	PermissionDriversAssign              Permission = "drivers:assign"
+	PermissionMaintenanceRead            Permission = "maintenance:read"
+	PermissionMaintenanceCreate          Permission = "maintenance:create"
+	PermissionFuelRead                   Permission = "fuel:read"
+	PermissionFuelCreate                 Permission = "fuel:create"
+	PermissionReservationsRead           Permission = "reservations:read"
+	PermissionReservationsCreate         Permission = "reservations:create"
+	PermissionReservationsCancel         Permission = "reservations:cancel"
+	PermissionTenantsSwitch              Permission = "tenants:switch"
+	// PermissionVehiclesExplicitId is required besides the one of the operation to add vehicles with their ids
+	PermissionVehiclesExplicitId Permission = "vehicles:explicit_id"
+)
+
+// VehicleOperations is a map of the permission each method of the VehicleService requires
+var VehicleOperations = map[string]Permission{
+	"FindAll":                   PermissionVehiclesRead,
+	"List":                      PermissionVehiclesRead,
+	"FindById":                  PermissionVehiclesRead,
+	"CheckRegistration":         PermissionVehiclesRead,
+	"FindByRegistration":        PermissionVehiclesRead,
+	"FindByVIN":                 PermissionVehiclesRead,
+	"Search":                    PermissionVehiclesRead,
+	"SearchByColorAndYear":      PermissionVehiclesRead,
+	"SearchByBrand":             PermissionVehiclesRead,
+	"GetAverageSpeedByBrand":    PermissionVehiclesRead,
+	"GetVehiclesByFuelType":     PermissionVehiclesRead,
+	"GetVehiclesByTransmission": PermissionVehiclesRead,
+	"GetAverageCapacityByBrand": PermissionVehiclesRead,
+	"GetVehiclesByDimensions":   PermissionVehiclesRead,
+	"GetVehiclesByWeight":       PermissionVehiclesRead,
+	"Compare":                   PermissionVehiclesRead,
+	"GetEmissions":              PermissionVehiclesRead,
+	"GetEmissionsReport":        PermissionVehiclesRead,
+	"GetQuote":                  PermissionVehiclesRead,
+	"GetSimilar":                PermissionVehiclesRead,
+	"GetStats":                  PermissionVehiclesRead,
+	"GetHistogram":              PermissionVehiclesRead,
+	"GetFrequencies":            PermissionVehiclesRead,
+	"Add":                       PermissionVehiclesCreate,
+	"AddMultiple":               PermissionVehiclesBatchImport,
+	"UpdateMaxSpeedById":        PermissionVehiclesUpdateSpeed,
+	"UpdateFuelTypeById":        PermissionVehiclesUpdateFuel,
+	"UpdateRegistrationById":    PermissionVehiclesUpdateRegistration,
+	"UpdateDepotById":           PermissionVehiclesUpdateDepot,
+	"DeleteById":                PermissionVehiclesDelete,
+	"RestoreById":               PermissionVehiclesRestore,
+	"PurgeDeleted":              PermissionVehiclesPurge,
+	"NormalizeAll":              PermissionVehiclesNormalize,
+}
+
+// DepotOperations is a map of the permission each method of the DepotService requires
+var DepotOperations = map[string]Permission{
+	"FindAll":  PermissionDepotsRead,
+	"FindById": PermissionDepotsRead,
+	"Add":      PermissionDepotsCreate,
+}
+
+// DriverOperations is a map of the permission each method of the DriverService requires
+var DriverOperations = map[string]Permission{
+	"FindAll":            PermissionDriversRead,
+	"FindById":           PermissionDriversRead,
+	"VehicleAssignments": PermissionDriversRead,
+	"DriverAssignments":  PermissionDriversRead,
+	"Add":                PermissionDriversCreate,
+	"Assign":             PermissionDriversAssign,
+	"Unassign":           PermissionDriversAssign,
+}
+
+// MaintenanceOperations is a map of the permission each method of the MaintenanceService requires
+var MaintenanceOperations = map[string]Permission{
+	"Records":   PermissionMaintenanceRead,
+	"Due":       PermissionMaintenanceRead,
+	"Costs":     PermissionMaintenanceRead,
+	"AddRecord": PermissionMaintenanceCreate,
+}
+
+// FuelOperations is a map of the permission each method of the FuelService requires
+var FuelOperations = map[string]Permission{
+	"Report": PermissionFuelRead,
+	"Stats":  PermissionFuelRead,
+	"AddLog": PermissionFuelCreate,
+}
+
+// ReservationOperations is a map of the permission each method of the ReservationService requires
+var ReservationOperations = map[string]Permission{
+	"VehicleReservations": PermissionReservationsRead,
+	"Reservation":         PermissionReservationsRead,
+	"Available":           PermissionReservationsRead,
+	"Reserve":             PermissionReservationsCreate,
+	"Cancel":              PermissionReservationsCancel,
+}
+
+// Role is a struct that represents a set of permissions
+type Role struct {
+	// Name is the name of the role, as in the principals
+	Name string
+	// Inherits are the roles whose permissions the role also has
+	Inherits []string
+	// Permissions are the permissions of the role
+	Permissions []Permission
+}
+
+// Policy is a struct that represents the roles and who they are granted to
+type Policy struct {
+	// Roles are the roles
+	Roles []Role
+	// Grants is a map of the roles granted to principals by method and id (api_key:<id> or jwt:<subject>), besides the roles of their credentials
+	Grants map[string][]string
+	// AnonymousRoles are the roles of the requests without credentials
+	AnonymousRoles []string
+}
+
+// PolicyLoader is an interface that represents the loader for the policy
+type PolicyLoader interface {
+	// Load is a method that loads the policy
+	Load() (p Policy, err error)
+}
+
+// Authorizer is an interface that represents the access control of the principals
+type Authorizer interface {
+	// Authorize is a method that returns a PermissionError when a principal, nil when anonymous, lacks a permission
+	Authorize(p *Principal, permission Permission) (err error)
+}
+
+// PermissionError is a struct that represents the error of a principal lacking a permission
+type PermissionError struct {
+	// Principal is the id of the principal, empty when anonymous
+	Principal string
+	// Permission is the missing permission
+	Permission Permission
+}
+
+// Error is a method that returns the message of the error
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: missing permission %s", ErrForbidden, e.Permission)
+}
+
+// Is is a method that reports the error as an ErrForbidden
+func (e *PermissionError) Is(target error) bool {
+	return target == ErrForbidden
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPricingRules = errors.New("Invalid pricing rules")
+	ErrNoBaseRate          = errors.New("No base rate for the vehicle")
+	ErrInvalidQuoteQuery   = errors.New("Invalid quote query")
+)
+
+// BaseRate is a struct that represents the price of a day of rental of the vehicles of a brand and size class
+// - the rate matching the most of brand and size class is the one applied to a vehicle, brand first
+type BaseRate struct {
+	// Brand is the brand of the vehicles of the rate, empty for any
+	Brand string
+	// SizeClass is the size class of the vehicles of the rate, empty for any
+	SizeClass string
+	// Daily is the price of a day
+	Daily float64
+}
+
+// Matches is a method that reports if the rate applies to a vehicle
+func (r BaseRate) Matches(v Vehicle) bool {
+	return (r.Brand == "" || strings.EqualFold(r.Brand, v.Brand)) && (r.SizeClass == "" || strings.EqualFold(r.SizeClass, v.Metrics.SizeClass))
+}
+
+// Specificity is a method that returns how specific the rate is, the higher the more
+func (r BaseRate) Specificity() (s int) {
+	if r.Brand != "" {
+		s += 2
+	}
+	if r.SizeClass != "" {
+		s++
+	}
+	return
+}
+
+// Surcharge is a struct that represents an extra price of a day of rental of the vehicles matching a filter
+// - every matching surcharge is added, e.g. for automatic transmissions and for 7 passengers or more
+type Surcharge struct {
+	// Name is the name of the surcharge in the quotes
+	Name string
+	// When are the conditions the vehicles must meet to be charged
+	When VehicleFilter
+	// Daily is the extra price of a day
+	Daily float64
+}
+
+// SeasonalDiscount is a struct that represents a discount of the days of rental within a season
+// - the season is a range of days of the year (01-02) with both ends included, it wraps the new year when From is after To
+type SeasonalDiscount struct {
+	// Name is the name of the season in the quotes
+	Name string
+	// From is the first day of the season, e.g. 01-15
+	From string
+	// To is the last day of the season, e.g. 03-31
+	To string
+	// Percent is the discount of the price of a day, from 0 to 100
+	Percent float64
+}
+
+// Contains is a method that reports if a day falls within the season
+func (s SeasonalDiscount) Contains(day time.Time) bool {
+	md := day.Format("01-02")
+	if s.From <= s.To {
+		return s.From <= md && md <= s.To
+	}
+	return md >= s.From || md <= s.To
+}
+
+// DurationDiscount is a struct that represents a discount of the long rentals
+// - the discount of the longest duration a rental reaches is the one applied
+type DurationDiscount struct {
+	// MinDays is the number of days the rental must last at least
+	MinDays int
+	// Percent is the discount of the price once the seasons are discounted, from 0 to 100
+	Percent float64
+}
+
+// PricingRules is a struct that represents how the rentals of the vehicles are priced
+type PricingRules struct {
+	// Currency is the currency of the prices
+	Currency string
+	// BaseRates are the prices of a day by brand and size class
+	BaseRates []BaseRate
+	// Surcharges are the extra prices of a day by attributes of the vehicles
+	Surcharges []Surcharge
+	// Seasons are the discounts of the days by season
+	Seasons []SeasonalDiscount
+	// Durations are the discounts of the long rentals
+	Durations []DurationDiscount
+}
+
+// PricingRulesLoader is an interface that represents the loader for the pricing rules
+type PricingRulesLoader interface {
+	// Load is a method that loads the pricing rules
+	Load() (r PricingRules, err error)
+}
+
+const (
+	QuoteLineBase      = "base"
+	QuoteLineSurcharge = "surcharge"
+	QuoteLineSeason    = "season"
+	QuoteLineDuration  = "duration"
+)
+
+// QuoteLine is a struct that represents an item of a quote, the discounts are negative amounts
+type QuoteLine struct {
+	// Kind is the kind of item: base, surcharge, season or duration
+	Kind string
+	// Description is the name of the item
+	Description string
+	// Days is the number of days the item applies to
+	Days int
+	// Daily is the price of a day the item is computed from
+	Daily float64
+	// Percent is the discount of the item, 0 for the prices
+	Percent float64
+	// Amount is the amount of the item
+	Amount float64
+}
+
+// VehicleQuote is a struct that represents the price of the rental of a vehicle for a period [From, To)
+type VehicleQuote struct {
+	// Vehicle is the vehicle of the quote
+	Vehicle Vehicle
+	// From is the moment the rental starts
+	From time.Time
+	// To is the moment the rental ends
+	To time.Time
+	// Days is the number of days charged, every started day is charged
+	Days int
+	// Daily is the price of a day before the discounts, the base rate plus the surcharges
+	Daily float64
+	// Lines are the items of the quote: the base rate, the surcharges and the discounts
+	Lines []QuoteLine
+	// Subtotal is the price before the discounts
+	Subtotal float64
+	// Discount is the sum of the discounts
+	Discount float64
+	// Total is the price to pay
+	Total float64
+	// Currency is the currency of the prices
+	Currency string
+}
+
+// VehiclePricer is an interface that represents the pricing of the rentals of the vehicles
+type VehiclePricer interface {
+	// Quote is a method that returns the price of the rental of a vehicle for a period [from, to)
+	Quote(v Vehicle, from time.Time, to time.Time) (q VehicleQuote, err error)
+}
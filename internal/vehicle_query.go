@@ -0,0 +1,13 @@
+package internal
+
+import "time"
+
+// VehicleQuery is a struct that represents the options of a read over the vehicles
+type VehicleQuery struct {
+	// IncludeDeleted is a flag that includes the soft deleted vehicles in the read
+	IncludeDeleted bool
+	// AsOf is the moment at which the vehicles are read, zero reads the current state
+	AsOf time.Time
+	// Units is the unit system of the measures read and of the ranges filtered, metric when empty
+	Units UnitSystem
+}
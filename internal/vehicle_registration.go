@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeRegistration is a function that returns the canonical form of a registration
+// - letters are upper cased, whitespaces and dashes are removed
+func NormalizeRegistration(registration string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, registration)
+}
@@ -1,43 +1,79 @@
 package internal
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 
 	ErrorVehicleAlreadyExists = errors.New("Vehicle already exists")
 	ErrorVehiclesNotFound = errors.New("Vehicles not found with those parameters")
 	ErrorVehicleNotFound = errors.New("Vehicle not found")
+	ErrorVehicleNotDeleted = errors.New("Vehicle is not deleted")
+	ErrorRegistrationAlreadyExists = errors.New("Registration already exists")
+	ErrorRegistrationAmbiguous = errors.New("Registration is shared by several vehicles")
+	ErrorVINAlreadyExists = errors.New("VIN already exists")
+	ErrorVINAmbiguous = errors.New("VIN is shared by several vehicles")
 
 )
 
 // VehicleRepository is an interface that represents a vehicle repository
 type VehicleRepository interface {
 	// FindAll is a method that returns a map of all vehicles
-	FindAll() (v map[int]Vehicle, err error)
+	FindAll(q VehicleQuery) (v map[int]Vehicle, err error)
+	// List is a method that returns a filtered, sorted and paged list of vehicles and the number of vehicles filtered
+	List(l VehicleListQuery, q VehicleQuery) (v []Vehicle, total int, err error)
+	// FindById is a method that returns a vehicle by id
+	FindById(id int, q VehicleQuery) (v Vehicle, err error)
+	// FindByRegistration is a method that returns a vehicle by its normalized registration
+	FindByRegistration(registration string, q VehicleQuery) (v Vehicle, err error)
+	// FindByVIN is a method that returns a vehicle by its normalized VIN
+	FindByVIN(vin string, q VehicleQuery) (v Vehicle, err error)
+	// Search is a method that returns the vehicles matching a text, most relevant first
+	Search(text string, limit int, q VehicleQuery) (results []VehicleSearchResult, err error)
 	// Add is a method that adds a vehicle
 	Add(v Vehicle) (err error)
 	// Search vehicles by color and year
-	SearchByColorAndYear(color string, year int) (v []Vehicle, err error)
+	SearchByColorAndYear(color string, year int, q VehicleQuery) (v []Vehicle, err error)
 	// Search vehicles by brand and year range
-	SearchByBrand(brand string, start_year int, end_year int) (v []Vehicle, err error)
+	SearchByBrand(brand string, start_year int, end_year int, q VehicleQuery) (v []Vehicle, err error)
 	// Get average speed by brand
-	GetAverageSpeedByBrand(brand string) (avgSpeed float64, err error)
+	GetAverageSpeedByBrand(brand string, q VehicleQuery) (avgSpeed float64, err error)
 	// Add multiple vehicles
 	AddMultiple(vehicles []Vehicle) (err error)
 	// Update max speed by id
 	UpdateMaxSpeedById(id int, maxSpeed float64) (err error)
 	// Search vehicles by fuel_type
-	GetVehiclesByFuelType(fuelType string) (v []Vehicle, err error)
-	// Delete a vehicle by id
+	GetVehiclesByFuelType(fuelType string, q VehicleQuery) (v []Vehicle, err error)
+	// Soft delete a vehicle by id
 	DeleteById(id int) (err error)
+	// Restore a soft deleted vehicle by id
+	RestoreById(id int) (err error)
+	// Hard delete the vehicles soft deleted before a moment
+	PurgeDeleted(before time.Time) (purged int, err error)
 	// Search vehicles by transmission type
-	GetVehiclesByTransmission(transmission string) (v []Vehicle, err error)
+	GetVehiclesByTransmission(transmission string, q VehicleQuery) (v []Vehicle, err error)
 	// Update fuel type by id
 	UpdateFuelTypeById(id int, fuelType string) (err error)
+	// Replace the attributes of a vehicle by id, deleted or not
+	ReplaceById(v Vehicle) (err error)
+	// Update registration by id
+	UpdateRegistrationById(id int, registration string) (err error)
+	// UpdateDepotById is a method that changes the depot that owns a vehicle
+	UpdateDepotById(id int, depotId int) (err error)
 	// Get average capacity of people by brand
-	GetAverageCapacityByBrand(brand string) (avgCapacity int, err error)
+	GetAverageCapacityByBrand(brand string, q VehicleQuery) (avgCapacity int, err error)
 	// Get vehicles by dimensions
-	GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64) (v []Vehicle, err error)
+	GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64, q VehicleQuery) (v []Vehicle, err error)
 	// Get vehicles by weight
-	GetVehiclesByWeight(minWeight float64, maxWeight float64) (v []Vehicle, err error)
+	GetVehiclesByWeight(minWeight float64, maxWeight float64, q VehicleQuery) (v []Vehicle, err error)
+	// Similar is a method that returns the vehicles closest to a reference one, closest first
+	Similar(s VehicleSimilarQuery, q VehicleQuery) (similar []SimilarVehicle, err error)
+	// Aggregate is a method that computes the statistics of a numeric field by group
+	Aggregate(s VehicleStatsQuery, q VehicleQuery) (stats []VehicleStats, err error)
+	// Histogram is a method that counts the vehicles by buckets of a numeric field
+	Histogram(h VehicleHistogramQuery, q VehicleQuery) (buckets []HistogramBucket, err error)
+	// Frequencies is a method that counts the vehicles by value of a categorical field, most frequent first
+	Frequencies(f VehicleFrequencyQuery, q VehicleQuery) (freq []Frequency, total int, err error)
 }
\ No newline at end of file
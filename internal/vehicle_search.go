@@ -0,0 +1,15 @@
+package internal
+
+import "errors"
+
+var (
+	ErrInvalidSearch = errors.New("Invalid search")
+)
+
+// VehicleSearchResult is a struct that represents a vehicle found by a text search
+type VehicleSearchResult struct {
+	// Vehicle is the vehicle found
+	Vehicle Vehicle
+	// Score is the relevance of the vehicle for the search, higher is more relevant
+	Score float64
+}
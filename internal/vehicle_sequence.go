@@ -0,0 +1,9 @@
+package internal
+
+// VehicleSequence is an interface that represents a generator of vehicle ids
+type VehicleSequence interface {
+	// Next is a method that returns a new id, greater than any id returned or advanced before
+	Next() (id int, err error)
+	// Advance is a method that makes sure the next ids are greater than id
+	Advance(id int) (err error)
+}
@@ -1,40 +1,90 @@
 package internal
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 var (
 	ErrInvalidSpeed = errors.New("Invalid speed")
 	ErrInvalidFuelType = errors.New("Invalid fuel type")
+	ErrInvalidRegistration = errors.New("Invalid registration")
+	ErrInvalidVehicleId = errors.New("Invalid vehicle id")
 )
 
 // VehicleService is an interface that represents a vehicle service
 type VehicleService interface {
 	// FindAll is a method that returns a map of all vehicles
-	FindAll() (v map[int]Vehicle, err error)
-	// Add is a method that adds a vehicle
-	Add(v Vehicle) (err error)
+	FindAll(q VehicleQuery) (v map[int]Vehicle, err error)
+	// List is a method that returns a filtered, sorted and paged list of vehicles and the number of vehicles filtered
+	List(l VehicleListQuery, q VehicleQuery) (v []Vehicle, total int, err error)
+	// FindById is a method that returns a vehicle by id
+	FindById(id int, q VehicleQuery) (v Vehicle, err error)
+	// CheckRegistration is a method that validates and normalizes a registration without storing anything
+	CheckRegistration(country string, registration string) (normalized string, format string, err error)
+	// FindByRegistration is a method that returns a vehicle by its registration
+	FindByRegistration(registration string, q VehicleQuery) (v Vehicle, err error)
+	// FindByVIN is a method that returns a vehicle by its VIN, with the decoded VIN
+	FindByVIN(vin string, q VehicleQuery) (v Vehicle, info VINInfo, err error)
+	// Search is a method that returns the vehicles matching a text, most relevant first
+	Search(text string, limit int, q VehicleQuery) (results []VehicleSearchResult, err error)
+	// Add is a method that adds a vehicle and returns it as stored: with its id, normalized and derived attributes
+	Add(v Vehicle) (stored Vehicle, err error)
 	// Search vehicles by color and year
-	SearchByColorAndYear(color string, year int) (v []Vehicle, err error)
+	SearchByColorAndYear(color string, year int, q VehicleQuery) (v []Vehicle, err error)
 	// Search vehicles by brand and year range
-	SearchByBrand(brand string, start_year int, end_year int) (v []Vehicle, err error)
+	SearchByBrand(brand string, start_year int, end_year int, q VehicleQuery) (v []Vehicle, err error)
 	// Get average speed by brand
-	GetAverageSpeedByBrand(brand string) (avgSpeed float64, err error)
-	// Add multiple vehicles
-	AddMultiple(vehicles []Vehicle) (err error)
+	GetAverageSpeedByBrand(brand string, q VehicleQuery) (avgSpeed float64, err error)
+	// Add multiple vehicles and return their ids
+	AddMultiple(vehicles []Vehicle) (ids []int, err error)
 	// // Update max speed by id
 	UpdateMaxSpeedById(id int, maxSpeed float64) (err error)
 	// // Search vehicles by fuel_type
-	GetVehiclesByFuelType(fuelType string) (v []Vehicle, err error)
-	// // Delete a vehicle by id
+	GetVehiclesByFuelType(fuelType string, q VehicleQuery) (v []Vehicle, err error)
+	// // Soft delete a vehicle by id
 	DeleteById(id int) (err error)
+	// Restore a soft deleted vehicle by id
+	RestoreById(id int) (err error)
+	// Hard delete the vehicles soft deleted longer than the retention
+	PurgeDeleted(retention time.Duration) (purged int, err error)
 	// // Search vehicles by transmission type
-	GetVehiclesByTransmission(transmission string) (v []Vehicle, err error)
+	GetVehiclesByTransmission(transmission string, q VehicleQuery) (v []Vehicle, err error)
 	// // Update fuel type by id
 	UpdateFuelTypeById(id int, fuelType string) (err error)
+	// Update registration by id
+	UpdateRegistrationById(id int, registration string) (err error)
+	// UpdateDepotById is a method that moves a vehicle to a depot of its tenant, 0 takes it out of any depot
+	UpdateDepotById(id int, depotId int) (err error)
 	// // Get average capacity of people by brand
-	GetAverageCapacityByBrand(brand string) (avgCapacity int, err error)
+	GetAverageCapacityByBrand(brand string, q VehicleQuery) (avgCapacity int, err error)
 	// Get vehicles by dimensions
-	GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64) (v []Vehicle, err error)
+	GetVehiclesByDimensions(minLength float64, maxLength float64, minWidth float64, maxWidth float64, q VehicleQuery) (v []Vehicle, err error)
 	// Get vehicles by weight
-	GetVehiclesByWeight(minWeight float64, maxWeight float64) (v []Vehicle, err error)
+	GetVehiclesByWeight(minWeight float64, maxWeight float64, q VehicleQuery) (v []Vehicle, err error)
+	// NormalizeAll is a method that canonicalizes the categorical values of the stored vehicles and reports the changes
+	NormalizeAll(dryRun bool) (changes []NormalizationChange, err error)
+	// Compare is a method that returns a side-by-side comparison of vehicles
+	Compare(ids []int, q VehicleQuery) (c VehicleComparison, err error)
+	// GetEmissions is a method that estimates the consumption, emissions and cost of a vehicle
+	GetEmissions(id int, kmPerYear float64, q VehicleQuery) (e VehicleEmissions, err error)
+	// GetEmissionsReport is a method that sums up the emissions and costs of the fleet by group
+	GetEmissionsReport(r EmissionsReportQuery, q VehicleQuery) (groups []EmissionsGroup, skipped int, err error)
+	// GetQuote is a method that prices the rental of a vehicle for a period [from, to)
+	GetQuote(id int, from time.Time, to time.Time, q VehicleQuery) (qt VehicleQuote, err error)
+	// GetSimilar is a method that returns the vehicles closest to a reference one, closest first
+	GetSimilar(s VehicleSimilarQuery, q VehicleQuery) (similar []SimilarVehicle, err error)
+	// GetStats is a method that computes the statistics of a numeric field by group
+	GetStats(s VehicleStatsQuery, q VehicleQuery) (stats []VehicleStats, err error)
+	// GetHistogram is a method that counts the vehicles by buckets of a numeric field
+	GetHistogram(h VehicleHistogramQuery, q VehicleQuery) (buckets []HistogramBucket, err error)
+	// GetFrequencies is a method that counts the vehicles by value of a categorical field, most frequent first
+	GetFrequencies(f VehicleFrequencyQuery, q VehicleQuery) (freq []Frequency, total int, err error)
+}
+
+// VehicleServiceProvider is an interface that represents the source of the vehicle service of each request
+type VehicleServiceProvider interface {
+	// Service is a method that returns the vehicle service for a context, e.g. bound to its principal
+	Service(ctx context.Context) VehicleService
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package internal
+
+import "errors"
+
+var (
+	ErrInvalidSimilarity = errors.New("Invalid similarity query")
+)
+
+// SimilarityFields are the fields that can weigh in the distance between two vehicles
+// - categorical fields add their weight when the values differ
+// - numeric fields add their weight times the squared difference scaled by the range of the catalog
+var SimilarityFields = []VehicleField{
+	FieldBrand, FieldModel, FieldFuelType, FieldTransmission,
+	FieldYear, FieldCapacity, FieldMaxSpeed, FieldWeight, FieldHeight, FieldLength, FieldWidth,
+}
+
+// DefaultSimilarityWeights are the weights used for the fields a similarity query does not weigh
+var DefaultSimilarityWeights = map[VehicleField]float64{
+	FieldBrand:        1,
+	FieldModel:        1,
+	FieldFuelType:     1,
+	FieldTransmission: 0.5,
+	FieldYear:         1,
+	FieldCapacity:     1,
+	FieldMaxSpeed:     1,
+	FieldWeight:       1,
+	FieldHeight:       0.5,
+	FieldLength:       0.5,
+	FieldWidth:        0.5,
+}
+
+// VehicleSimilarQuery is a struct that represents a search of the vehicles closest to another one
+type VehicleSimilarQuery struct {
+	// Id is the id of the reference vehicle
+	Id int
+	// K is the maximum number of vehicles returned
+	K int
+	// Weights are the weights of the fields in the distance, missing fields do not count
+	Weights map[VehicleField]float64
+	// Filter are the conditions the similar vehicles must meet
+	Filter VehicleFilter
+}
+
+// SimilarVehicle is a struct that represents a vehicle close to a reference one
+type SimilarVehicle struct {
+	// Vehicle is the similar vehicle
+	Vehicle Vehicle
+	// Distance is the weighted distance to the reference vehicle, lower is more similar
+	Distance float64
+}
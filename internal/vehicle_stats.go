@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrInvalidPercentile = errors.New("Invalid percentile")
+	ErrInvalidBuckets    = errors.New("Invalid histogram buckets")
+)
+
+// VehicleStatsQuery is a struct that represents an aggregation over the vehicles
+type VehicleStatsQuery struct {
+	// Field is the numeric field that is aggregated
+	Field VehicleField
+	// GroupBy are the categorical fields the vehicles are grouped by, none makes a single group
+	GroupBy []VehicleField
+	// Filter are the conditions the vehicles must meet to be aggregated
+	Filter VehicleFilter
+	// Percentiles are the percentiles (0-100) that are computed
+	Percentiles []float64
+}
+
+// VehicleStats is a struct that represents the aggregation of a group of vehicles
+type VehicleStats struct {
+	// Group are the values of the grouping fields
+	Group map[VehicleField]string
+	// Count is the number of vehicles
+	Count int
+	// Sum is the sum of the values
+	Sum float64
+	// Min is the minimum value
+	Min float64
+	// Max is the maximum value
+	Max float64
+	// Mean is the mean of the values
+	Mean float64
+	// Median is the median of the values
+	Median float64
+	// StdDev is the population standard deviation of the values
+	StdDev float64
+	// Percentiles are the requested percentiles of the values
+	Percentiles map[float64]float64
+}
+
+// VehicleHistogramQuery is a struct that represents a histogram of a numeric field
+// - Edges, when set, are the ascending bounds of the buckets; otherwise Buckets buckets of the same width
+// span from the minimum to the maximum value
+type VehicleHistogramQuery struct {
+	// Field is the numeric field of the histogram
+	Field VehicleField
+	// Buckets is the number of buckets of the same width
+	Buckets int
+	// Edges are the explicit bounds of the buckets
+	Edges []float64
+	// Filter are the conditions the vehicles must meet to be counted
+	Filter VehicleFilter
+}
+
+// HistogramBucket is a struct that represents a bucket of a histogram, [Min, Max) except the last one [Min, Max]
+type HistogramBucket struct {
+	// Min is the lower bound of the bucket
+	Min float64
+	// Max is the upper bound of the bucket
+	Max float64
+	// Count is the number of vehicles in the bucket
+	Count int
+}
+
+// VehicleFrequencyQuery is a struct that represents the frequencies of the values of a categorical field
+type VehicleFrequencyQuery struct {
+	// Field is the categorical field that is counted
+	Field VehicleField
+	// Top is the number of most frequent values returned, 0 returns every value
+	Top int
+	// Filter are the conditions the vehicles must meet to be counted
+	Filter VehicleFilter
+}
+
+// Frequency is a struct that represents how many vehicles have a value
+type Frequency struct {
+	// Value is the value of the field
+	Value string
+	// Count is the number of vehicles with the value
+	Count int
+}
+
+// Summarize is a function that computes the statistics of a non empty set of values
+func Summarize(values []float64, percentiles []float64) (st VehicleStats) {
+	sort.Float64s(values)
+
+	st.Count = len(values)
+	st.Min = values[0]
+	st.Max = values[len(values)-1]
+	for _, value := range values {
+		st.Sum += value
+	}
+	st.Mean = st.Sum / float64(st.Count)
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - st.Mean) * (value - st.Mean)
+	}
+	st.StdDev = math.Sqrt(squares / float64(st.Count))
+
+	st.Median = percentile(values, 50)
+	st.Percentiles = make(map[float64]float64, len(percentiles))
+	for _, p := range percentiles {
+		st.Percentiles[p] = percentile(values, p)
+	}
+
+	return
+}
+
+// percentile is a function that returns the p-th percentile of sorted values, interpolating linearly between ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
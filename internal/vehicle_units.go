@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidUnitSystem = errors.New("Invalid unit system")
+	ErrInvalidUnit       = errors.New("Invalid unit")
+)
+
+// UnitSystem is the system of units in which the measures of the vehicles are read
+// - the vehicles are stored in the canonical units of the metric system: km/h, kg and cm,
+// and the derived attributes in m³, m² and kg/m³
+type UnitSystem string
+
+const (
+	// UnitsMetric reads the measures in km/h, kg, cm, m³, m² and kg/m³
+	UnitsMetric UnitSystem = "metric"
+	// UnitsImperial reads the measures in mph, lb, in, ft³, ft² and lb/ft³
+	UnitsImperial UnitSystem = "imperial"
+)
+
+// dimension is the kind of magnitude a field measures
+type dimension string
+
+const (
+	speed  dimension = "speed"
+	mass   dimension = "mass"
+	length dimension = "length"
+	volume dimension = "volume"
+	area   dimension = "area"
+	dense  dimension = "density"
+)
+
+var (
+	// fieldDimensions are the magnitudes measured by the fields, the other fields have no unit
+	fieldDimensions = map[VehicleField]dimension{
+		FieldMaxSpeed: speed,
+		FieldWeight:   mass,
+		FieldHeight:   length,
+		FieldLength:   length,
+		FieldWidth:    length,
+		// derived
+		FieldVolume:      volume,
+		FieldCargoVolume: volume,
+		FieldFootprint:   area,
+		FieldDensity:     dense,
+	}
+	// systemUnits are the units of each magnitude in each system
+	systemUnits = map[UnitSystem]map[dimension]string{
+		UnitsMetric:   {speed: "km/h", mass: "kg", length: "cm", volume: "m³", area: "m²", dense: "kg/m³"},
+		UnitsImperial: {speed: "mph", mass: "lb", length: "in", volume: "ft³", area: "ft²", dense: "lb/ft³"},
+	}
+	// unitFactors are the canonical units each unit holds, by magnitude and accepted spelling
+	unitFactors = map[dimension]map[string]float64{
+		speed:  {"km/h": 1, "kmh": 1, "kph": 1, "mph": 1.609344, "m/s": 3.6},
+		mass:   {"kg": 1, "g": 0.001, "t": 1000, "lb": 0.45359237, "lbs": 0.45359237},
+		length: {"cm": 1, "mm": 0.1, "m": 100, "in": 2.54, "ft": 30.48},
+		volume: {"m³": 1, "ft³": 0.028316846592},
+		area:   {"m²": 1, "ft²": 0.09290304},
+		dense:  {"kg/m³": 1, "lb/ft³": 16.01846337},
+	}
+)
+
+// ParseUnitSystem is a function that returns the unit system of a name, metric when empty
+func ParseUnitSystem(name string) (u UnitSystem, err error) {
+	u = UnitSystem(strings.ToLower(strings.TrimSpace(name)))
+	switch u {
+	case "":
+		return UnitsMetric, nil
+	case UnitsMetric, UnitsImperial:
+		return
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidUnitSystem, name)
+}
+
+// Unit is a method that returns the unit of a field in the system, empty if the field has no unit
+func (u UnitSystem) Unit(field VehicleField) string {
+	if u == "" {
+		u = UnitsMetric
+	}
+	return systemUnits[u][fieldDimensions[field]]
+}
+
+// factor is a method that returns the canonical units a unit of the field holds in the system
+func (u UnitSystem) factor(field VehicleField) float64 {
+	unit := u.Unit(field)
+	if unit == "" {
+		return 1
+	}
+	return unitFactors[fieldDimensions[field]][unit]
+}
+
+// FromCanonical is a method that converts a value of a field from the canonical unit to the system
+func (u UnitSystem) FromCanonical(field VehicleField, value float64) float64 {
+	return value / u.factor(field)
+}
+
+// ToCanonical is a method that converts a value of a field from the system to the canonical unit
+func (u UnitSystem) ToCanonical(field VehicleField, value float64) float64 {
+	return value * u.factor(field)
+}
+
+// Vehicle is a method that converts the measures of a vehicle from the canonical units to the system
+func (u UnitSystem) Vehicle(v Vehicle) Vehicle {
+	v.MaxSpeed = u.FromCanonical(FieldMaxSpeed, v.MaxSpeed)
+	v.Weight = u.FromCanonical(FieldWeight, v.Weight)
+	v.Height = u.FromCanonical(FieldHeight, v.Height)
+	v.Length = u.FromCanonical(FieldLength, v.Length)
+	v.Width = u.FromCanonical(FieldWidth, v.Width)
+	v.Metrics.Volume = u.FromCanonical(FieldVolume, v.Metrics.Volume)
+	v.Metrics.CargoVolume = u.FromCanonical(FieldCargoVolume, v.Metrics.CargoVolume)
+	v.Metrics.Footprint = u.FromCanonical(FieldFootprint, v.Metrics.Footprint)
+	v.Metrics.Density = u.FromCanonical(FieldDensity, v.Metrics.Density)
+	return v
+}
+
+// Filter is a method that converts the ranges of a filter from the system to the canonical units
+func (u UnitSystem) Filter(f VehicleFilter) VehicleFilter {
+	ranges := make(map[VehicleField]Range, len(f.Ranges))
+	for field, rg := range f.Ranges {
+		if rg.Min != nil {
+			min := u.ToCanonical(field, *rg.Min)
+			rg.Min = &min
+		}
+		if rg.Max != nil {
+			max := u.ToCanonical(field, *rg.Max)
+			rg.Max = &max
+		}
+		ranges[field] = rg
+	}
+	f.Ranges = ranges
+	return f
+}
+
+// ConvertToCanonical is a function that converts a value of a field tagged with an explicit unit
+// (e.g. 120 mph, 2.5 t, 6 ft) to the canonical unit of the field
+func ConvertToCanonical(field VehicleField, value float64, unit string) (canonical float64, err error) {
+	dim, ok := fieldDimensions[field]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s has no unit", ErrInvalidUnit, field)
+	}
+	factor, ok := unitFactors[dim][strings.ToLower(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s is not a unit of %s", ErrInvalidUnit, unit, dim)
+	}
+	return value * factor, nil
+}
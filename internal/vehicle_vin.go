@@ -0,0 +1,38 @@
+package internal
+
+import "errors"
+
+var (
+	ErrInvalidVIN  = errors.New("Invalid VIN")
+	ErrVINMismatch = errors.New("VIN does not match the vehicle")
+)
+
+// NormalizeVIN is a function that returns the canonical form of a VIN
+// - letters are upper cased, whitespaces and dashes are removed
+func NormalizeVIN(vin string) string {
+	return NormalizeRegistration(vin)
+}
+
+// VINInfo is a struct that represents the information encoded in a VIN
+type VINInfo struct {
+	// WMI is the world manufacturer identifier (positions 1-3)
+	WMI string
+	// Manufacturer is the manufacturer of the WMI, empty if the WMI is unknown
+	Manufacturer string
+	// Country is the country of the manufacturer, empty if the WMI is unknown
+	Country string
+	// Brands are the brands built under the WMI
+	Brands []string
+	// ModelYear is the model year (position 10)
+	ModelYear int
+	// Plant is the code of the assembly plant (position 11)
+	Plant string
+	// Serial is the production sequence number (positions 12-17)
+	Serial string
+}
+
+// VINDecoder is an interface that represents a decoder of VINs
+type VINDecoder interface {
+	// Decode is a method that validates a normalized VIN and returns its information
+	Decode(vin string) (info VINInfo, err error)
+}